@@ -19,12 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	InteractiveService_IncrReadCnt_FullMethodName = "/intr.v1.InteractiveService/IncrReadCnt"
-	InteractiveService_Like_FullMethodName        = "/intr.v1.InteractiveService/Like"
-	InteractiveService_CancelLike_FullMethodName  = "/intr.v1.InteractiveService/CancelLike"
-	InteractiveService_Collect_FullMethodName     = "/intr.v1.InteractiveService/Collect"
-	InteractiveService_Get_FullMethodName         = "/intr.v1.InteractiveService/Get"
-	InteractiveService_GetByIds_FullMethodName    = "/intr.v1.InteractiveService/GetByIds"
+	InteractiveService_IncrReadCnt_FullMethodName     = "/intr.v1.InteractiveService/IncrReadCnt"
+	InteractiveService_Like_FullMethodName            = "/intr.v1.InteractiveService/Like"
+	InteractiveService_CancelLike_FullMethodName      = "/intr.v1.InteractiveService/CancelLike"
+	InteractiveService_Collect_FullMethodName         = "/intr.v1.InteractiveService/Collect"
+	InteractiveService_Get_FullMethodName             = "/intr.v1.InteractiveService/Get"
+	InteractiveService_GetByIds_FullMethodName        = "/intr.v1.InteractiveService/GetByIds"
+	InteractiveService_GetUserLikes_FullMethodName    = "/intr.v1.InteractiveService/GetUserLikes"
+	InteractiveService_GetUserCollects_FullMethodName = "/intr.v1.InteractiveService/GetUserCollects"
+	InteractiveService_DeleteUserData_FullMethodName  = "/intr.v1.InteractiveService/DeleteUserData"
 )
 
 // InteractiveServiceClient is the client API for InteractiveService service.
@@ -37,6 +40,12 @@ type InteractiveServiceClient interface {
 	Collect(ctx context.Context, in *CollectRequest, opts ...grpc.CallOption) (*CollectResponse, error)
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	GetByIds(ctx context.Context, in *GetByIdsRequest, opts ...grpc.CallOption) (*GetByIdsResponse, error)
+	// GetUserLikes 某个用户点赞过的资源，导出个人数据的时候用
+	GetUserLikes(ctx context.Context, in *GetUserLikesRequest, opts ...grpc.CallOption) (*GetUserLikesResponse, error)
+	// GetUserCollects 某个用户收藏过的资源，导出个人数据的时候用
+	GetUserCollects(ctx context.Context, in *GetUserCollectsRequest, opts ...grpc.CallOption) (*GetUserCollectsResponse, error)
+	// DeleteUserData 删除某个用户的点赞、收藏记录，并修正对应的计数，注销账号的时候用
+	DeleteUserData(ctx context.Context, in *DeleteUserDataRequest, opts ...grpc.CallOption) (*DeleteUserDataResponse, error)
 }
 
 type interactiveServiceClient struct {
@@ -107,6 +116,36 @@ func (c *interactiveServiceClient) GetByIds(ctx context.Context, in *GetByIdsReq
 	return out, nil
 }
 
+func (c *interactiveServiceClient) GetUserLikes(ctx context.Context, in *GetUserLikesRequest, opts ...grpc.CallOption) (*GetUserLikesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserLikesResponse)
+	err := c.cc.Invoke(ctx, InteractiveService_GetUserLikes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *interactiveServiceClient) GetUserCollects(ctx context.Context, in *GetUserCollectsRequest, opts ...grpc.CallOption) (*GetUserCollectsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserCollectsResponse)
+	err := c.cc.Invoke(ctx, InteractiveService_GetUserCollects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *interactiveServiceClient) DeleteUserData(ctx context.Context, in *DeleteUserDataRequest, opts ...grpc.CallOption) (*DeleteUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUserDataResponse)
+	err := c.cc.Invoke(ctx, InteractiveService_DeleteUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InteractiveServiceServer is the server API for InteractiveService service.
 // All implementations must embed UnimplementedInteractiveServiceServer
 // for forward compatibility.
@@ -117,6 +156,12 @@ type InteractiveServiceServer interface {
 	Collect(context.Context, *CollectRequest) (*CollectResponse, error)
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	GetByIds(context.Context, *GetByIdsRequest) (*GetByIdsResponse, error)
+	// GetUserLikes 某个用户点赞过的资源，导出个人数据的时候用
+	GetUserLikes(context.Context, *GetUserLikesRequest) (*GetUserLikesResponse, error)
+	// GetUserCollects 某个用户收藏过的资源，导出个人数据的时候用
+	GetUserCollects(context.Context, *GetUserCollectsRequest) (*GetUserCollectsResponse, error)
+	// DeleteUserData 删除某个用户的点赞、收藏记录，并修正对应的计数，注销账号的时候用
+	DeleteUserData(context.Context, *DeleteUserDataRequest) (*DeleteUserDataResponse, error)
 	mustEmbedUnimplementedInteractiveServiceServer()
 }
 
@@ -145,6 +190,15 @@ func (UnimplementedInteractiveServiceServer) Get(context.Context, *GetRequest) (
 func (UnimplementedInteractiveServiceServer) GetByIds(context.Context, *GetByIdsRequest) (*GetByIdsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetByIds not implemented")
 }
+func (UnimplementedInteractiveServiceServer) GetUserLikes(context.Context, *GetUserLikesRequest) (*GetUserLikesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserLikes not implemented")
+}
+func (UnimplementedInteractiveServiceServer) GetUserCollects(context.Context, *GetUserCollectsRequest) (*GetUserCollectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserCollects not implemented")
+}
+func (UnimplementedInteractiveServiceServer) DeleteUserData(context.Context, *DeleteUserDataRequest) (*DeleteUserDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserData not implemented")
+}
 func (UnimplementedInteractiveServiceServer) mustEmbedUnimplementedInteractiveServiceServer() {}
 func (UnimplementedInteractiveServiceServer) testEmbeddedByValue()                            {}
 
@@ -274,6 +328,60 @@ func _InteractiveService_GetByIds_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InteractiveService_GetUserLikes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserLikesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InteractiveServiceServer).GetUserLikes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InteractiveService_GetUserLikes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InteractiveServiceServer).GetUserLikes(ctx, req.(*GetUserLikesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InteractiveService_GetUserCollects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserCollectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InteractiveServiceServer).GetUserCollects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InteractiveService_GetUserCollects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InteractiveServiceServer).GetUserCollects(ctx, req.(*GetUserCollectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InteractiveService_DeleteUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InteractiveServiceServer).DeleteUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InteractiveService_DeleteUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InteractiveServiceServer).DeleteUserData(ctx, req.(*DeleteUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InteractiveService_ServiceDesc is the grpc.ServiceDesc for InteractiveService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +413,18 @@ var InteractiveService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetByIds",
 			Handler:    _InteractiveService_GetByIds_Handler,
 		},
+		{
+			MethodName: "GetUserLikes",
+			Handler:    _InteractiveService_GetUserLikes_Handler,
+		},
+		{
+			MethodName: "GetUserCollects",
+			Handler:    _InteractiveService_GetUserCollects_Handler,
+		},
+		{
+			MethodName: "DeleteUserData",
+			Handler:    _InteractiveService_DeleteUserData_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "intr/v1/interactive.proto",
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
+	"webook/internal/job"
 	"webook/pkg/saramax"
 )
 
@@ -10,4 +11,6 @@ type App struct {
 	web       *gin.Engine
 	consumers []saramax.Consumer
 	cron      *cron.Cron
+	// scheduler 基于 MySQL 的任务调度，多实例部署的时候同一个任务只会在一个实例上执行
+	scheduler *job.Scheduler
 }
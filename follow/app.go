@@ -0,0 +1,10 @@
+package main
+
+import (
+	"webook/pkg/grpcx"
+)
+
+type App struct {
+	// 所有需要 main 函数控制启动、关闭的都要在这里
+	server *grpcx.Server
+}
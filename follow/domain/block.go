@@ -0,0 +1,23 @@
+package domain
+
+type BlockType uint8
+
+const (
+	BlockTypeUnknown BlockType = iota
+	// BlockTypeBlock 拉黑，被拉黑的人不能关注，也不能点赞、收藏拉黑者的文章
+	BlockTypeBlock
+	// BlockTypeMute 屏蔽，只是自己看不到对方的内容，对方没有任何感知
+	BlockTypeMute
+)
+
+func (t BlockType) Valid() bool {
+	return t == BlockTypeBlock || t == BlockTypeMute
+}
+
+// BlockRelation Uid 拉黑（或者屏蔽）了 Target
+type BlockRelation struct {
+	Uid    int64
+	Target int64
+	Type   BlockType
+	Ctime  int64
+}
@@ -0,0 +1,30 @@
+package domain
+
+// FollowRelation 关注关系，Follower 关注了 Followee
+type FollowRelation struct {
+	Id       int64
+	Follower int64
+	Followee int64
+	Ctime    int64
+}
+
+// FollowStatics 某个人的关注统计
+type FollowStatics struct {
+	// 粉丝数
+	Followers int64
+	// 关注数
+	Followees int64
+}
+
+// FollowInfo 两个人之间的关注状态
+type FollowInfo struct {
+	// Followed follower 是否关注了 followee
+	Followed bool
+	// FollowedBack followee 是否也关注了 follower
+	FollowedBack bool
+}
+
+// Mutual 是否互相关注
+func (f FollowInfo) Mutual() bool {
+	return f.Followed && f.FollowedBack
+}
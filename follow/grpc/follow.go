@@ -0,0 +1,144 @@
+package grpc
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	followv1 "webook/api/proto/gen/follow/v1"
+	"webook/follow/domain"
+	"webook/follow/service"
+)
+
+type FollowServiceServer struct {
+	followv1.UnimplementedFollowServiceServer
+
+	svc      service.FollowRelationService
+	blockSvc service.BlockService
+}
+
+func NewFollowServiceServer(svc service.FollowRelationService, blockSvc service.BlockService) *FollowServiceServer {
+	return &FollowServiceServer{svc: svc, blockSvc: blockSvc}
+}
+
+func (f *FollowServiceServer) Register(server grpc.ServiceRegistrar) {
+	followv1.RegisterFollowServiceServer(server, f)
+}
+
+func (f *FollowServiceServer) Follow(ctx context.Context, request *followv1.FollowRequest) (*followv1.FollowResponse, error) {
+	err := f.svc.Follow(ctx, request.GetFollower(), request.GetFollowee())
+	switch err {
+	case service.ErrFollowSelf:
+		// 调用方可以根据错误码区分业务错误
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case service.ErrFollowBlocked:
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return &followv1.FollowResponse{}, err
+}
+
+func (f *FollowServiceServer) CancelFollow(ctx context.Context, request *followv1.CancelFollowRequest) (*followv1.CancelFollowResponse, error) {
+	err := f.svc.CancelFollow(ctx, request.GetFollower(), request.GetFollowee())
+	return &followv1.CancelFollowResponse{}, err
+}
+
+func (f *FollowServiceServer) GetFollowee(ctx context.Context, request *followv1.GetFolloweeRequest) (*followv1.GetFolloweeResponse, error) {
+	list, err := f.svc.GetFollowee(ctx, request.GetFollower(), request.GetOffset(), request.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.GetFolloweeResponse{
+		FollowRelations: f.toDTOs(list),
+	}, nil
+}
+
+func (f *FollowServiceServer) GetFollower(ctx context.Context, request *followv1.GetFollowerRequest) (*followv1.GetFollowerResponse, error) {
+	list, err := f.svc.GetFollower(ctx, request.GetFollowee(), request.GetOffset(), request.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.GetFollowerResponse{
+		FollowRelations: f.toDTOs(list),
+	}, nil
+}
+
+func (f *FollowServiceServer) FollowInfo(ctx context.Context, request *followv1.FollowInfoRequest) (*followv1.FollowInfoResponse, error) {
+	info, err := f.svc.FollowInfo(ctx, request.GetFollower(), request.GetFollowee())
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.FollowInfoResponse{
+		Followed:     info.Followed,
+		FollowedBack: info.FollowedBack,
+	}, nil
+}
+
+func (f *FollowServiceServer) GetFollowStatics(ctx context.Context, request *followv1.GetFollowStaticsRequest) (*followv1.GetFollowStaticsResponse, error) {
+	statics, err := f.svc.GetFollowStatics(ctx, request.GetUid())
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.GetFollowStaticsResponse{
+		Statics: &followv1.FollowStatics{
+			Followers: statics.Followers,
+			Followees: statics.Followees,
+		},
+	}, nil
+}
+
+func (f *FollowServiceServer) Block(ctx context.Context, request *followv1.BlockRequest) (*followv1.BlockResponse, error) {
+	err := f.blockSvc.Block(ctx, request.GetUid(), request.GetTarget(), domain.BlockType(request.GetType()))
+	if err == service.ErrBlockSelf || err == service.ErrInvalidBlockType {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &followv1.BlockResponse{}, err
+}
+
+func (f *FollowServiceServer) CancelBlock(ctx context.Context, request *followv1.CancelBlockRequest) (*followv1.CancelBlockResponse, error) {
+	err := f.blockSvc.CancelBlock(ctx, request.GetUid(), request.GetTarget(), domain.BlockType(request.GetType()))
+	if err == service.ErrInvalidBlockType {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &followv1.CancelBlockResponse{}, err
+}
+
+func (f *FollowServiceServer) GetBlockList(ctx context.Context, request *followv1.GetBlockListRequest) (*followv1.GetBlockListResponse, error) {
+	list, err := f.blockSvc.GetBlockList(ctx, request.GetUid(), domain.BlockType(request.GetType()),
+		request.GetOffset(), request.GetLimit())
+	if err == service.ErrInvalidBlockType {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.GetBlockListResponse{
+		BlockRelations: slice.Map(list, func(idx int, src domain.BlockRelation) *followv1.BlockRelation {
+			return &followv1.BlockRelation{
+				Uid:    src.Uid,
+				Target: src.Target,
+				Type:   followv1.BlockType(src.Type),
+				Ctime:  src.Ctime,
+			}
+		}),
+	}, nil
+}
+
+func (f *FollowServiceServer) IsBlocked(ctx context.Context, request *followv1.IsBlockedRequest) (*followv1.IsBlockedResponse, error) {
+	blocked, err := f.blockSvc.IsBlocked(ctx, request.GetUid(), request.GetTarget())
+	if err != nil {
+		return nil, err
+	}
+	return &followv1.IsBlockedResponse{Blocked: blocked}, nil
+}
+
+func (f *FollowServiceServer) toDTOs(list []domain.FollowRelation) []*followv1.FollowRelation {
+	return slice.Map(list, func(idx int, src domain.FollowRelation) *followv1.FollowRelation {
+		return &followv1.FollowRelation{
+			Id:       src.Id,
+			Follower: src.Follower,
+			Followee: src.Followee,
+			Ctime:    src.Ctime,
+		}
+	})
+}
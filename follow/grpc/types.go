@@ -0,0 +1,7 @@
+package grpc
+
+import "google.golang.org/grpc"
+
+type Service interface {
+	Register(s grpc.ServiceRegistrar)
+}
@@ -0,0 +1,25 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	grpc2 "webook/follow/grpc"
+	"webook/pkg/grpcx"
+)
+
+func InitGRPCxServer(follow *grpc2.FollowServiceServer) *grpcx.Server {
+	type Config struct {
+		Addr string `yaml:"addr"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("grpc.server", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	server := grpc.NewServer()
+	follow.Register(server)
+	return &grpcx.Server{
+		Server: server,
+		Addr:   cfg.Addr,
+	}
+}
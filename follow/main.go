@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	// 当前目录的配置
+	initViper()
+	app := Init()
+	err := app.server.Serve()
+	panic(err)
+}
+
+func initViper() {
+	cfile := pflag.String("config",
+		"config/dev.yaml", "配置文件路径")
+	pflag.Parse()
+	// 直接指定文件路径
+	viper.SetConfigFile(*cfile)
+	err := viper.ReadInConfig()
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/ecodeclub/ekit/slice"
+	"gorm.io/gorm"
+	"webook/follow/domain"
+	"webook/follow/repository/dao"
+)
+
+var ErrBlockRelationNotFound = gorm.ErrRecordNotFound
+
+type BlockRepository interface {
+	AddBlock(ctx context.Context, b domain.BlockRelation) error
+	CancelBlock(ctx context.Context, uid, target int64, typ domain.BlockType) error
+	GetBlockList(ctx context.Context, uid int64, typ domain.BlockType, offset, limit int64) ([]domain.BlockRelation, error)
+	// Blocked uid 是否对 target 生效了 typ 类型的拉黑或者屏蔽
+	Blocked(ctx context.Context, uid, target int64, typ domain.BlockType) (bool, error)
+}
+
+type blockRepository struct {
+	dao dao.BlockDAO
+}
+
+func NewBlockRepository(dao dao.BlockDAO) BlockRepository {
+	return &blockRepository{
+		dao: dao,
+	}
+}
+
+func (b *blockRepository) AddBlock(ctx context.Context, br domain.BlockRelation) error {
+	return b.dao.Insert(ctx, dao.UserBlock{
+		Uid:    br.Uid,
+		Target: br.Target,
+		Typ:    uint8(br.Type),
+	})
+}
+
+func (b *blockRepository) CancelBlock(ctx context.Context, uid, target int64, typ domain.BlockType) error {
+	return b.dao.UpdateStatus(ctx, uid, target, uint8(typ), dao.BlockStatusInactive)
+}
+
+func (b *blockRepository) GetBlockList(ctx context.Context, uid int64, typ domain.BlockType, offset, limit int64) ([]domain.BlockRelation, error) {
+	list, err := b.dao.List(ctx, uid, uint8(typ), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(list, func(idx int, src dao.UserBlock) domain.BlockRelation {
+		return domain.BlockRelation{
+			Uid:    src.Uid,
+			Target: src.Target,
+			Type:   domain.BlockType(src.Typ),
+			Ctime:  src.Ctime,
+		}
+	}), nil
+}
+
+func (b *blockRepository) Blocked(ctx context.Context, uid, target int64, typ domain.BlockType) (bool, error) {
+	_, err := b.dao.Get(ctx, uid, target, uint8(typ))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrBlockRelationNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
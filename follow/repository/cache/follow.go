@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+	"webook/follow/domain"
+)
+
+var (
+	//go:embed lua/incr_cnt.lua
+	luaIncrCnt string
+)
+
+var ErrKeyNotExist = redis.Nil
+
+const (
+	// 粉丝数
+	fieldFollowerCnt = "follower_cnt"
+	// 关注数
+	fieldFolloweeCnt = "followee_cnt"
+)
+
+type FollowCache interface {
+	StaticsInfo(ctx context.Context, uid int64) (domain.FollowStatics, error)
+	SetStaticsInfo(ctx context.Context, uid int64, statics domain.FollowStatics) error
+	// Follow 如果缓存中有对应的数据，follower 的关注数和 followee 的粉丝数都 +1
+	Follow(ctx context.Context, follower, followee int64) error
+	// CancelFollow 如果缓存中有对应的数据，follower 的关注数和 followee 的粉丝数都 -1
+	CancelFollow(ctx context.Context, follower, followee int64) error
+}
+
+type RedisFollowCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+}
+
+func NewRedisFollowCache(client redis.Cmdable) FollowCache {
+	return &RedisFollowCache{
+		client:     client,
+		expiration: time.Minute * 15,
+	}
+}
+
+func (r *RedisFollowCache) StaticsInfo(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	data, err := r.client.HGetAll(ctx, r.staticsKey(uid)).Result()
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	if len(data) == 0 {
+		return domain.FollowStatics{}, ErrKeyNotExist
+	}
+	followers, _ := strconv.ParseInt(data[fieldFollowerCnt], 10, 64)
+	followees, _ := strconv.ParseInt(data[fieldFolloweeCnt], 10, 64)
+	return domain.FollowStatics{
+		Followers: followers,
+		Followees: followees,
+	}, nil
+}
+
+func (r *RedisFollowCache) SetStaticsInfo(ctx context.Context, uid int64, statics domain.FollowStatics) error {
+	key := r.staticsKey(uid)
+	err := r.client.HMSet(ctx, key,
+		fieldFollowerCnt, statics.Followers,
+		fieldFolloweeCnt, statics.Followees).Err()
+	if err != nil {
+		return err
+	}
+	return r.client.Expire(ctx, key, r.expiration).Err()
+}
+
+func (r *RedisFollowCache) Follow(ctx context.Context, follower, followee int64) error {
+	return r.updateStaticsInfo(ctx, follower, followee, 1)
+}
+
+func (r *RedisFollowCache) CancelFollow(ctx context.Context, follower, followee int64) error {
+	return r.updateStaticsInfo(ctx, follower, followee, -1)
+}
+
+func (r *RedisFollowCache) updateStaticsInfo(ctx context.Context, follower, followee int64, delta int64) error {
+	pipe := r.client.TxPipeline()
+	// follower 的关注数
+	pipe.Eval(ctx, luaIncrCnt, []string{r.staticsKey(follower)}, fieldFolloweeCnt, delta)
+	// followee 的粉丝数
+	pipe.Eval(ctx, luaIncrCnt, []string{r.staticsKey(followee)}, fieldFollowerCnt, delta)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisFollowCache) staticsKey(uid int64) string {
+	return fmt.Sprintf("follow:statics:%d", uid)
+}
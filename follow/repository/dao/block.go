@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	BlockStatusUnknown uint8 = iota
+	BlockStatusActive
+	BlockStatusInactive
+)
+
+type BlockDAO interface {
+	// Insert 拉黑或者屏蔽，已经存在（取消过）的会重新激活
+	Insert(ctx context.Context, b UserBlock) error
+	UpdateStatus(ctx context.Context, uid, target int64, typ uint8, status uint8) error
+	List(ctx context.Context, uid int64, typ uint8, offset, limit int64) ([]UserBlock, error)
+	// Get 查询生效中的记录
+	Get(ctx context.Context, uid, target int64, typ uint8) (UserBlock, error)
+}
+
+type GORMBlockDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMBlockDAO(db *gorm.DB) BlockDAO {
+	return &GORMBlockDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMBlockDAO) Insert(ctx context.Context, b UserBlock) error {
+	now := time.Now().UnixMilli()
+	b.Status = BlockStatusActive
+	b.Ctime = now
+	b.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"status": BlockStatusActive,
+			"utime":  now,
+		}),
+	}).Create(&b).Error
+}
+
+func (dao *GORMBlockDAO) UpdateStatus(ctx context.Context, uid, target int64, typ uint8, status uint8) error {
+	return dao.db.WithContext(ctx).Model(&UserBlock{}).
+		Where("uid = ? AND target = ? AND typ = ?", uid, target, typ).
+		Updates(map[string]any{
+			"status": status,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *GORMBlockDAO) List(ctx context.Context, uid int64, typ uint8, offset, limit int64) ([]UserBlock, error) {
+	var res []UserBlock
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND typ = ? AND status = ?", uid, typ, BlockStatusActive).
+		Order("utime DESC").
+		Offset(int(offset)).Limit(int(limit)).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMBlockDAO) Get(ctx context.Context, uid, target int64, typ uint8) (UserBlock, error) {
+	var res UserBlock
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND target = ? AND typ = ? AND status = ?", uid, target, typ, BlockStatusActive).
+		First(&res).Error
+	return res, err
+}
+
+// UserBlock 拉黑和屏蔽都存在这张表，用 Typ 区分
+type UserBlock struct {
+	Id     int64 `gorm:"primaryKey,autoIncrement"`
+	Uid    int64 `gorm:"uniqueIndex:uid_target_typ"`
+	Target int64 `gorm:"uniqueIndex:uid_target_typ"`
+	Typ    uint8 `gorm:"uniqueIndex:uid_target_typ"`
+	Status uint8
+	Ctime  int64
+	Utime  int64
+}
@@ -0,0 +1,152 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	FollowRelationStatusUnknown uint8 = iota
+	// FollowRelationStatusActive 关注中
+	FollowRelationStatusActive
+	// FollowRelationStatusInactive 已经取消关注
+	FollowRelationStatusInactive
+)
+
+type FollowRelationDAO interface {
+	// CreateFollowRelation 创建关注关系，已经存在（取消过关注）的会重新激活
+	// 返回值 changed 表示关注关系是不是真的发生了变化，重复关注的时候为 false
+	CreateFollowRelation(ctx context.Context, f FollowRelation) (changed bool, err error)
+	// UpdateStatus 更新关注关系的状态，返回值 changed 的含义同上
+	UpdateStatus(ctx context.Context, followee, follower int64, status uint8) (changed bool, err error)
+	// FollowRelationList 查询 follower 关注了哪些人
+	FollowRelationList(ctx context.Context, follower, offset, limit int64) ([]FollowRelation, error)
+	// FollowerRelationList 查询哪些人关注了 followee
+	FollowerRelationList(ctx context.Context, followee, offset, limit int64) ([]FollowRelation, error)
+	FollowRelationDetail(ctx context.Context, follower, followee int64) (FollowRelation, error)
+	// CntFollower 统计粉丝数
+	CntFollower(ctx context.Context, uid int64) (int64, error)
+	// CntFollowee 统计关注数
+	CntFollowee(ctx context.Context, uid int64) (int64, error)
+}
+
+type GORMFollowRelationDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMFollowRelationDAO(db *gorm.DB) FollowRelationDAO {
+	return &GORMFollowRelationDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMFollowRelationDAO) CreateFollowRelation(ctx context.Context, f FollowRelation) (bool, error) {
+	now := time.Now().UnixMilli()
+	changed := false
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先锁住已有的记录，这样才能准确知道这一次是不是真的新增了关注
+		var old FollowRelation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("follower = ? AND followee = ?", f.Follower, f.Followee).
+			First(&old).Error
+		switch err {
+		case nil:
+			if old.Status == FollowRelationStatusActive {
+				return nil
+			}
+			changed = true
+			return tx.Model(&FollowRelation{}).
+				Where("id = ?", old.Id).
+				Updates(map[string]any{
+					"status": FollowRelationStatusActive,
+					"utime":  now,
+				}).Error
+		case gorm.ErrRecordNotFound:
+			changed = true
+			f.Status = FollowRelationStatusActive
+			f.Ctime = now
+			f.Utime = now
+			// 并发关注的时候，唯一索引冲突就更新状态
+			return tx.Clauses(clause.OnConflict{
+				DoUpdates: clause.Assignments(map[string]any{
+					"status": FollowRelationStatusActive,
+					"utime":  now,
+				}),
+			}).Create(&f).Error
+		default:
+			return err
+		}
+	})
+	return changed, err
+}
+
+func (dao *GORMFollowRelationDAO) UpdateStatus(ctx context.Context, followee, follower int64, status uint8) (bool, error) {
+	// 只更新状态不一样的记录，这样可以根据影响行数判断是不是真的发生了变化
+	res := dao.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("follower = ? AND followee = ? AND status <> ?", follower, followee, status).
+		Updates(map[string]any{
+			"status": status,
+			"utime":  time.Now().UnixMilli(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (dao *GORMFollowRelationDAO) FollowRelationList(ctx context.Context, follower, offset, limit int64) ([]FollowRelation, error) {
+	var res []FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("follower = ? AND status = ?", follower, FollowRelationStatusActive).
+		Order("utime DESC").
+		Offset(int(offset)).Limit(int(limit)).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) FollowerRelationList(ctx context.Context, followee, offset, limit int64) ([]FollowRelation, error) {
+	var res []FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("followee = ? AND status = ?", followee, FollowRelationStatusActive).
+		Order("utime DESC").
+		Offset(int(offset)).Limit(int(limit)).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) FollowRelationDetail(ctx context.Context, follower, followee int64) (FollowRelation, error) {
+	var res FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("follower = ? AND followee = ? AND status = ?",
+			follower, followee, FollowRelationStatusActive).
+		First(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) CntFollower(ctx context.Context, uid int64) (int64, error) {
+	var res int64
+	err := dao.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("followee = ? AND status = ?", uid, FollowRelationStatusActive).
+		Count(&res).Error
+	return res, err
+}
+
+func (dao *GORMFollowRelationDAO) CntFollowee(ctx context.Context, uid int64) (int64, error) {
+	var res int64
+	err := dao.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("follower = ? AND status = ?", uid, FollowRelationStatusActive).
+		Count(&res).Error
+	return res, err
+}
+
+// FollowRelation 关注关系表
+// 取消关注的时候不删除数据，只是更新状态
+type FollowRelation struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 查询某个人关注了哪些人，以及判断是否关注，都会用到 follower 开头的联合索引
+	Follower int64 `gorm:"uniqueIndex:follower_followee"`
+	// 查询某个人的粉丝列表
+	Followee int64 `gorm:"uniqueIndex:follower_followee;index"`
+	Status   uint8
+	Ctime    int64
+	Utime    int64
+}
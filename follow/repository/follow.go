@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"gorm.io/gorm"
+	"webook/follow/domain"
+	"webook/follow/repository/cache"
+	"webook/follow/repository/dao"
+	"webook/pkg/logger"
+)
+
+var ErrFollowRelationNotFound = gorm.ErrRecordNotFound
+
+type FollowRepository interface {
+	// GetFollowee 获取某人的关注列表
+	GetFollowee(ctx context.Context, follower, offset, limit int64) ([]domain.FollowRelation, error)
+	// GetFollower 获取某人的粉丝列表
+	GetFollower(ctx context.Context, followee, offset, limit int64) ([]domain.FollowRelation, error)
+	// FollowInfo 查看关注人的详情
+	FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowRelation, error)
+	// AddFollowRelation 创建关注关系
+	AddFollowRelation(ctx context.Context, f domain.FollowRelation) error
+	// InactiveFollowRelation 取消关注
+	InactiveFollowRelation(ctx context.Context, follower, followee int64) error
+	GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type CachedFollowRepository struct {
+	dao   dao.FollowRelationDAO
+	cache cache.FollowCache
+	l     logger.Logger
+}
+
+func NewCachedFollowRepository(dao dao.FollowRelationDAO, cache cache.FollowCache, l logger.Logger) FollowRepository {
+	return &CachedFollowRepository{
+		dao:   dao,
+		cache: cache,
+		l:     l,
+	}
+}
+
+func (c *CachedFollowRepository) GetFollowee(ctx context.Context, follower, offset, limit int64) ([]domain.FollowRelation, error) {
+	list, err := c.dao.FollowRelationList(ctx, follower, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return c.genFollowRelationList(list), nil
+}
+
+func (c *CachedFollowRepository) GetFollower(ctx context.Context, followee, offset, limit int64) ([]domain.FollowRelation, error) {
+	list, err := c.dao.FollowerRelationList(ctx, followee, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return c.genFollowRelationList(list), nil
+}
+
+func (c *CachedFollowRepository) FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowRelation, error) {
+	f, err := c.dao.FollowRelationDetail(ctx, follower, followee)
+	if err != nil {
+		return domain.FollowRelation{}, err
+	}
+	return c.toDomain(f), nil
+}
+
+func (c *CachedFollowRepository) AddFollowRelation(ctx context.Context, f domain.FollowRelation) error {
+	changed, err := c.dao.CreateFollowRelation(ctx, c.toEntity(f))
+	if err != nil || !changed {
+		// 重复关注的时候不需要更新计数
+		return err
+	}
+	// 这里缓存更新失败也没有关系，计数不要求完全准确，缓存过期之后就会修正
+	return c.cache.Follow(ctx, f.Follower, f.Followee)
+}
+
+func (c *CachedFollowRepository) InactiveFollowRelation(ctx context.Context, follower, followee int64) error {
+	changed, err := c.dao.UpdateStatus(ctx, followee, follower, dao.FollowRelationStatusInactive)
+	if err != nil || !changed {
+		return err
+	}
+	return c.cache.CancelFollow(ctx, follower, followee)
+}
+
+func (c *CachedFollowRepository) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	res, err := c.cache.StaticsInfo(ctx, uid)
+	if err == nil {
+		return res, nil
+	}
+	res.Followers, err = c.dao.CntFollower(ctx, uid)
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	res.Followees, err = c.dao.CntFollowee(ctx, uid)
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	if er := c.cache.SetStaticsInfo(ctx, uid, res); er != nil {
+		c.l.Error("回写关注统计缓存失败",
+			logger.Int64("uid", uid),
+			logger.Error(er))
+	}
+	return res, nil
+}
+
+func (c *CachedFollowRepository) genFollowRelationList(list []dao.FollowRelation) []domain.FollowRelation {
+	return slice.Map[dao.FollowRelation, domain.FollowRelation](list,
+		func(idx int, src dao.FollowRelation) domain.FollowRelation {
+			return c.toDomain(src)
+		})
+}
+
+func (c *CachedFollowRepository) toDomain(f dao.FollowRelation) domain.FollowRelation {
+	return domain.FollowRelation{
+		Id:       f.Id,
+		Follower: f.Follower,
+		Followee: f.Followee,
+		Ctime:    f.Ctime,
+	}
+}
+
+func (c *CachedFollowRepository) toEntity(f domain.FollowRelation) dao.FollowRelation {
+	return dao.FollowRelation{
+		Id:       f.Id,
+		Follower: f.Follower,
+		Followee: f.Followee,
+	}
+}
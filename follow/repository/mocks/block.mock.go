@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./follow/repository/block.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./follow/repository/block.go -package=repomocks -destination=./follow/repository/mocks/block.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/follow/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockRepository is a mock of BlockRepository interface.
+type MockBlockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBlockRepositoryMockRecorder is the mock recorder for MockBlockRepository.
+type MockBlockRepositoryMockRecorder struct {
+	mock *MockBlockRepository
+}
+
+// NewMockBlockRepository creates a new mock instance.
+func NewMockBlockRepository(ctrl *gomock.Controller) *MockBlockRepository {
+	mock := &MockBlockRepository{ctrl: ctrl}
+	mock.recorder = &MockBlockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockRepository) EXPECT() *MockBlockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddBlock mocks base method.
+func (m *MockBlockRepository) AddBlock(ctx context.Context, b domain.BlockRelation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBlock", ctx, b)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBlock indicates an expected call of AddBlock.
+func (mr *MockBlockRepositoryMockRecorder) AddBlock(ctx, b any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBlock", reflect.TypeOf((*MockBlockRepository)(nil).AddBlock), ctx, b)
+}
+
+// Blocked mocks base method.
+func (m *MockBlockRepository) Blocked(ctx context.Context, uid, target int64, typ domain.BlockType) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Blocked", ctx, uid, target, typ)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Blocked indicates an expected call of Blocked.
+func (mr *MockBlockRepositoryMockRecorder) Blocked(ctx, uid, target, typ any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Blocked", reflect.TypeOf((*MockBlockRepository)(nil).Blocked), ctx, uid, target, typ)
+}
+
+// CancelBlock mocks base method.
+func (m *MockBlockRepository) CancelBlock(ctx context.Context, uid, target int64, typ domain.BlockType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelBlock", ctx, uid, target, typ)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelBlock indicates an expected call of CancelBlock.
+func (mr *MockBlockRepositoryMockRecorder) CancelBlock(ctx, uid, target, typ any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBlock", reflect.TypeOf((*MockBlockRepository)(nil).CancelBlock), ctx, uid, target, typ)
+}
+
+// GetBlockList mocks base method.
+func (m *MockBlockRepository) GetBlockList(ctx context.Context, uid int64, typ domain.BlockType, offset, limit int64) ([]domain.BlockRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockList", ctx, uid, typ, offset, limit)
+	ret0, _ := ret[0].([]domain.BlockRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockList indicates an expected call of GetBlockList.
+func (mr *MockBlockRepositoryMockRecorder) GetBlockList(ctx, uid, typ, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockList", reflect.TypeOf((*MockBlockRepository)(nil).GetBlockList), ctx, uid, typ, offset, limit)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"webook/follow/domain"
+	"webook/follow/repository"
+)
+
+var (
+	ErrBlockSelf        = errors.New("不能拉黑或者屏蔽自己")
+	ErrInvalidBlockType = errors.New("未知的拉黑类型")
+)
+
+type BlockService interface {
+	// Block uid 拉黑或者屏蔽 target
+	// 拉黑的时候会顺便解除双方的关注关系
+	Block(ctx context.Context, uid, target int64, typ domain.BlockType) error
+	CancelBlock(ctx context.Context, uid, target int64, typ domain.BlockType) error
+	GetBlockList(ctx context.Context, uid int64, typ domain.BlockType, offset, limit int64) ([]domain.BlockRelation, error)
+	// IsBlocked uid 是否拉黑了 target
+	IsBlocked(ctx context.Context, uid, target int64) (bool, error)
+}
+
+type blockService struct {
+	repo       repository.BlockRepository
+	followRepo repository.FollowRepository
+}
+
+func NewBlockService(repo repository.BlockRepository, followRepo repository.FollowRepository) BlockService {
+	return &blockService{
+		repo:       repo,
+		followRepo: followRepo,
+	}
+}
+
+func (b *blockService) Block(ctx context.Context, uid, target int64, typ domain.BlockType) error {
+	if uid == target {
+		return ErrBlockSelf
+	}
+	if !typ.Valid() {
+		return ErrInvalidBlockType
+	}
+	err := b.repo.AddBlock(ctx, domain.BlockRelation{
+		Uid:    uid,
+		Target: target,
+		Type:   typ,
+	})
+	if err != nil || typ != domain.BlockTypeBlock {
+		return err
+	}
+	// 拉黑之后双方都不再关注对方
+	err = b.followRepo.InactiveFollowRelation(ctx, target, uid)
+	if err != nil {
+		return err
+	}
+	return b.followRepo.InactiveFollowRelation(ctx, uid, target)
+}
+
+func (b *blockService) CancelBlock(ctx context.Context, uid, target int64, typ domain.BlockType) error {
+	if !typ.Valid() {
+		return ErrInvalidBlockType
+	}
+	return b.repo.CancelBlock(ctx, uid, target, typ)
+}
+
+func (b *blockService) GetBlockList(ctx context.Context, uid int64, typ domain.BlockType, offset, limit int64) ([]domain.BlockRelation, error) {
+	if !typ.Valid() {
+		return nil, ErrInvalidBlockType
+	}
+	// 屏蔽列表调用方一般要一次性全部拿到，用来过滤内容，所以这里上限放宽一些
+	if limit <= 0 || limit > maxPageSize*10 {
+		limit = maxPageSize * 10
+	}
+	return b.repo.GetBlockList(ctx, uid, typ, offset, limit)
+}
+
+func (b *blockService) IsBlocked(ctx context.Context, uid, target int64) (bool, error) {
+	return b.repo.Blocked(ctx, uid, target, domain.BlockTypeBlock)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/sync/errgroup"
+	"webook/follow/domain"
+	"webook/follow/repository"
+)
+
+var (
+	ErrFollowSelf    = errors.New("不能关注自己")
+	ErrFollowBlocked = errors.New("对方已经将你拉黑")
+)
+
+// 列表每一页最多返回的数量
+const maxPageSize = 100
+
+type FollowRelationService interface {
+	// Follow follower 关注 followee，重复关注不会报错
+	Follow(ctx context.Context, follower, followee int64) error
+	// CancelFollow 取消关注，没有关注过也不会报错
+	CancelFollow(ctx context.Context, follower, followee int64) error
+	// GetFollowee 分页查询 follower 的关注列表，按照关注时间倒序
+	GetFollowee(ctx context.Context, follower, offset, limit int64) ([]domain.FollowRelation, error)
+	// GetFollower 分页查询 followee 的粉丝列表，按照关注时间倒序
+	GetFollower(ctx context.Context, followee, offset, limit int64) ([]domain.FollowRelation, error)
+	// FollowInfo 查询两个人之间的关注状态，可以用来判断是否互相关注
+	FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowInfo, error)
+	// GetFollowStatics 查询粉丝数和关注数
+	GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type followRelationService struct {
+	repo      repository.FollowRepository
+	blockRepo repository.BlockRepository
+}
+
+func NewFollowRelationService(repo repository.FollowRepository, blockRepo repository.BlockRepository) FollowRelationService {
+	return &followRelationService{
+		repo:      repo,
+		blockRepo: blockRepo,
+	}
+}
+
+func (f *followRelationService) Follow(ctx context.Context, follower, followee int64) error {
+	if follower == followee {
+		return ErrFollowSelf
+	}
+	blocked, err := f.blockRepo.Blocked(ctx, followee, follower, domain.BlockTypeBlock)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrFollowBlocked
+	}
+	return f.repo.AddFollowRelation(ctx, domain.FollowRelation{
+		Follower: follower,
+		Followee: followee,
+	})
+}
+
+func (f *followRelationService) CancelFollow(ctx context.Context, follower, followee int64) error {
+	return f.repo.InactiveFollowRelation(ctx, follower, followee)
+}
+
+func (f *followRelationService) GetFollowee(ctx context.Context, follower, offset, limit int64) ([]domain.FollowRelation, error) {
+	return f.repo.GetFollowee(ctx, follower, offset, f.pageSize(limit))
+}
+
+func (f *followRelationService) GetFollower(ctx context.Context, followee, offset, limit int64) ([]domain.FollowRelation, error) {
+	return f.repo.GetFollower(ctx, followee, offset, f.pageSize(limit))
+}
+
+func (f *followRelationService) FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowInfo, error) {
+	var (
+		eg  errgroup.Group
+		res domain.FollowInfo
+	)
+	eg.Go(func() error {
+		var err error
+		res.Followed, err = f.followed(ctx, follower, followee)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		res.FollowedBack, err = f.followed(ctx, followee, follower)
+		return err
+	})
+	return res, eg.Wait()
+}
+
+func (f *followRelationService) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	return f.repo.GetFollowStatics(ctx, uid)
+}
+
+func (f *followRelationService) followed(ctx context.Context, follower, followee int64) (bool, error) {
+	_, err := f.repo.FollowInfo(ctx, follower, followee)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, repository.ErrFollowRelationNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (f *followRelationService) pageSize(limit int64) int64 {
+	if limit <= 0 || limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/follow/domain"
+	"webook/follow/repository"
+	repomocks "webook/follow/repository/mocks"
+)
+
+func TestFollowRelationService_Follow(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (repository.FollowRepository, repository.BlockRepository)
+
+		follower int64
+		followee int64
+
+		wantErr error
+	}{
+		{
+			name: "关注成功",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.BlockRepository) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				blockRepo := repomocks.NewMockBlockRepository(ctrl)
+				blockRepo.EXPECT().Blocked(gomock.Any(), int64(2), int64(1), domain.BlockTypeBlock).
+					Return(false, nil)
+				repo.EXPECT().AddFollowRelation(gomock.Any(), domain.FollowRelation{
+					Follower: 1,
+					Followee: 2,
+				}).Return(nil)
+				return repo, blockRepo
+			},
+			follower: 1,
+			followee: 2,
+		},
+		{
+			name: "关注自己",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.BlockRepository) {
+				return repomocks.NewMockFollowRepository(ctrl), repomocks.NewMockBlockRepository(ctrl)
+			},
+			follower: 1,
+			followee: 1,
+			wantErr:  ErrFollowSelf,
+		},
+		{
+			name: "被对方拉黑",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.BlockRepository) {
+				blockRepo := repomocks.NewMockBlockRepository(ctrl)
+				blockRepo.EXPECT().Blocked(gomock.Any(), int64(2), int64(1), domain.BlockTypeBlock).
+					Return(true, nil)
+				return repomocks.NewMockFollowRepository(ctrl), blockRepo
+			},
+			follower: 1,
+			followee: 2,
+			wantErr:  ErrFollowBlocked,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, blockRepo := tc.mock(ctrl)
+			svc := NewFollowRelationService(repo, blockRepo)
+			err := svc.Follow(context.Background(), tc.follower, tc.followee)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestFollowRelationService_FollowInfo(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) repository.FollowRepository
+
+		wantInfo domain.FollowInfo
+		wantErr  error
+	}{
+		{
+			name: "互相关注",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().FollowInfo(gomock.Any(), int64(1), int64(2)).
+					Return(domain.FollowRelation{Follower: 1, Followee: 2}, nil)
+				repo.EXPECT().FollowInfo(gomock.Any(), int64(2), int64(1)).
+					Return(domain.FollowRelation{Follower: 2, Followee: 1}, nil)
+				return repo
+			},
+			wantInfo: domain.FollowInfo{Followed: true, FollowedBack: true},
+		},
+		{
+			name: "对方没有回关",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().FollowInfo(gomock.Any(), int64(1), int64(2)).
+					Return(domain.FollowRelation{Follower: 1, Followee: 2}, nil)
+				repo.EXPECT().FollowInfo(gomock.Any(), int64(2), int64(1)).
+					Return(domain.FollowRelation{}, repository.ErrFollowRelationNotFound)
+				return repo
+			},
+			wantInfo: domain.FollowInfo{Followed: true},
+		},
+		{
+			name: "查询失败",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().FollowInfo(gomock.Any(), int64(1), int64(2)).
+					Return(domain.FollowRelation{}, errors.New("mock db error"))
+				repo.EXPECT().FollowInfo(gomock.Any(), int64(2), int64(1)).
+					Return(domain.FollowRelation{}, repository.ErrFollowRelationNotFound)
+				return repo
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewFollowRelationService(tc.mock(ctrl), nil)
+			info, err := svc.FollowInfo(context.Background(), 1, 2)
+			assert.Equal(t, tc.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tc.wantInfo, info)
+				assert.Equal(t, tc.wantInfo.Followed && tc.wantInfo.FollowedBack, info.Mutual())
+			}
+		})
+	}
+}
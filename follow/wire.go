@@ -0,0 +1,43 @@
+//go:build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+	"webook/follow/grpc"
+	"webook/follow/ioc"
+	"webook/follow/repository"
+	"webook/follow/repository/cache"
+	"webook/follow/repository/dao"
+	"webook/follow/service"
+)
+
+// 第三方依赖
+var thirdProvider = wire.NewSet(
+	ioc.InitDB, ioc.InitRedis, ioc.InitLogger,
+)
+
+var followSvcProvider = wire.NewSet(
+	service.NewFollowRelationService,
+	repository.NewCachedFollowRepository,
+	dao.NewGORMFollowRelationDAO,
+	cache.NewRedisFollowCache,
+)
+
+var blockSvcProvider = wire.NewSet(
+	service.NewBlockService,
+	repository.NewBlockRepository,
+	dao.NewGORMBlockDAO,
+)
+
+func Init() *App {
+	wire.Build(
+		thirdProvider,
+		followSvcProvider,
+		blockSvcProvider,
+		grpc.NewFollowServiceServer,
+		ioc.InitGRPCxServer,
+		wire.Struct(new(App), "*"),
+	)
+	return new(App)
+}
@@ -0,0 +1,47 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+	"webook/follow/grpc"
+	"webook/follow/ioc"
+	"webook/follow/repository"
+	"webook/follow/repository/cache"
+	"webook/follow/repository/dao"
+	"webook/follow/service"
+)
+
+// Injectors from wire.go:
+
+func Init() *App {
+	logger := ioc.InitLogger()
+	db := ioc.InitDB(logger)
+	followRelationDAO := dao.NewGORMFollowRelationDAO(db)
+	cmdable := ioc.InitRedis()
+	followCache := cache.NewRedisFollowCache(cmdable)
+	followRepository := repository.NewCachedFollowRepository(followRelationDAO, followCache, logger)
+	blockDAO := dao.NewGORMBlockDAO(db)
+	blockRepository := repository.NewBlockRepository(blockDAO)
+	followRelationService := service.NewFollowRelationService(followRepository, blockRepository)
+	blockService := service.NewBlockService(blockRepository, followRepository)
+	followServiceServer := grpc.NewFollowServiceServer(followRelationService, blockService)
+	server := ioc.InitGRPCxServer(followServiceServer)
+	app := &App{
+		server: server,
+	}
+	return app
+}
+
+// wire.go:
+
+// 第三方依赖
+var thirdProvider = wire.NewSet(ioc.InitDB, ioc.InitRedis, ioc.InitLogger)
+
+var followSvcProvider = wire.NewSet(service.NewFollowRelationService, repository.NewCachedFollowRepository, dao.NewGORMFollowRelationDAO, cache.NewRedisFollowCache)
+
+var blockSvcProvider = wire.NewSet(service.NewBlockService, repository.NewBlockRepository, dao.NewGORMBlockDAO)
@@ -11,3 +11,12 @@ type Interactive struct {
 	Liked     bool `json:"liked"`
 	Collected bool `json:"collected"`
 }
+
+// UserBizRecord 用户点赞或者收藏过的某个资源
+type UserBizRecord struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"biz_id"`
+	// 收藏夹 ID，只有收藏记录才有
+	Cid   int64 `json:"cid"`
+	Ctime int64 `json:"ctime"`
+}
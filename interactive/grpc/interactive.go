@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"github.com/ecodeclub/ekit/slice"
 	"google.golang.org/grpc"
 	intrv1 "webook/api/proto/gen/intr/v1"
 	"webook/interactive/domain"
@@ -78,3 +79,39 @@ func (i *InteractiveServiceServer) toDTO(intr domain.Interactive) *intrv1.Intera
 		Collected:  intr.Collected,
 	}
 }
+
+func (i *InteractiveServiceServer) GetUserLikes(ctx context.Context, request *intrv1.GetUserLikesRequest) (*intrv1.GetUserLikesResponse, error) {
+	records, err := i.svc.GetUserLikes(ctx, request.GetUid(), int(request.GetOffset()), int(request.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.GetUserLikesResponse{
+		Records: i.toRecordDTOs(records),
+	}, nil
+}
+
+func (i *InteractiveServiceServer) GetUserCollects(ctx context.Context, request *intrv1.GetUserCollectsRequest) (*intrv1.GetUserCollectsResponse, error) {
+	records, err := i.svc.GetUserCollects(ctx, request.GetUid(), int(request.GetOffset()), int(request.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.GetUserCollectsResponse{
+		Records: i.toRecordDTOs(records),
+	}, nil
+}
+
+func (i *InteractiveServiceServer) DeleteUserData(ctx context.Context, request *intrv1.DeleteUserDataRequest) (*intrv1.DeleteUserDataResponse, error) {
+	err := i.svc.DeleteUserData(ctx, request.GetUid())
+	return &intrv1.DeleteUserDataResponse{}, err
+}
+
+func (i *InteractiveServiceServer) toRecordDTOs(records []domain.UserBizRecord) []*intrv1.UserBizRecord {
+	return slice.Map(records, func(idx int, src domain.UserBizRecord) *intrv1.UserBizRecord {
+		return &intrv1.UserBizRecord{
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Cid:   src.Cid,
+			Ctime: src.Ctime,
+		}
+	})
+}
@@ -17,6 +17,11 @@ type InteractiveDAO interface {
 	GetCollectionInfo(ctx context.Context, biz string, bizId, uid int64) (UserCollectionBiz, error)
 	BatchIncrReadCnt(ctx context.Context, bizs []string, ids []int64) error
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]Interactive, error)
+	// ListUserLikes 某个用户生效中的点赞记录
+	ListUserLikes(ctx context.Context, uid int64, offset, limit int) ([]UserLikeBiz, error)
+	ListUserCollects(ctx context.Context, uid int64, offset, limit int) ([]UserCollectionBiz, error)
+	// DeleteUserData 删除某个用户的点赞、收藏记录和收藏夹，同时修正对应资源的计数
+	DeleteUserData(ctx context.Context, uid int64) error
 }
 
 type GORMInteractiveDAO struct {
@@ -258,6 +263,75 @@ func (dao *GORMInteractiveDAO) GetCollectionInfo(ctx context.Context, biz string
 	return res, err
 }
 
+func (dao *GORMInteractiveDAO) ListUserLikes(ctx context.Context, uid int64, offset, limit int) ([]UserLikeBiz, error) {
+	var res []UserLikeBiz
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND status = ?", uid, 1).
+		Order("id").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) ListUserCollects(ctx context.Context, uid int64, offset, limit int) ([]UserCollectionBiz, error) {
+	var res []UserCollectionBiz
+	err := dao.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		Order("id").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) DeleteUserData(ctx context.Context, uid int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先把生效中的点赞对应的计数减掉
+		var likes []UserLikeBiz
+		err := tx.Where("uid = ? AND status = ?", uid, 1).Find(&likes).Error
+		if err != nil {
+			return err
+		}
+		for _, l := range likes {
+			err = tx.Model(&Interactive{}).
+				Where("biz = ? AND biz_id = ? AND like_cnt > 0", l.Biz, l.BizId).
+				Updates(map[string]any{
+					"like_cnt": gorm.Expr("`like_cnt`-1"),
+					"utime":    now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		// 收藏也是一样
+		var collects []UserCollectionBiz
+		err = tx.Where("uid = ?", uid).Find(&collects).Error
+		if err != nil {
+			return err
+		}
+		for _, c := range collects {
+			err = tx.Model(&Interactive{}).
+				Where("biz = ? AND biz_id = ? AND collect_cnt > 0", c.Biz, c.BizId).
+				Updates(map[string]any{
+					"collect_cnt": gorm.Expr("`collect_cnt`-1"),
+					"utime":       now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.Where("uid = ?", uid).Delete(&UserLikeBiz{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("uid = ?", uid).Delete(&UserCollectionBiz{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("uid = ?", uid).Delete(&Collection{}).Error
+	})
+}
+
 // 正常来说，一张主表和与它有关联关系的表会共用一个DAO，
 // 所以我们就用一个 DAO 来操作
 
@@ -278,7 +352,8 @@ type UserLikeBiz struct {
 	// 三个构成唯一索引
 	BizId int64  `gorm:"uniqueIndex:biz_type_id_uid"`
 	Biz   string `gorm:"type:varchar(128);uniqueIndex:biz_type_id_uid"`
-	Uid   int64  `gorm:"uniqueIndex:biz_type_id_uid"`
+	// 按照用户查询点赞记录的时候也需要索引
+	Uid int64 `gorm:"uniqueIndex:biz_type_id_uid;index"`
 	// 依旧是只在 DB 层面生效的状态
 	// 1- 有效，0-无效。软删除的用法
 	Status uint8
@@ -290,7 +365,7 @@ type UserLikeBiz struct {
 type Collection struct {
 	Id   int64  `gorm:"primaryKey,autoIncrement"`
 	Name string `gorm:"type=varchar(1024)"`
-	Uid  int64  `gorm:""`
+	Uid  int64  `gorm:"index"`
 
 	Ctime int64
 	Utime int64
@@ -306,7 +381,7 @@ type UserCollectionBiz struct {
 	Biz   string `gorm:"type:varchar(128);uniqueIndex:biz_type_id_uid"`
 	// 这算是一个冗余，因为正常来说，
 	// 只需要在 Collection 中维持住 Uid 就可以
-	Uid   int64 `gorm:"uniqueIndex:biz_type_id_uid"`
+	Uid   int64 `gorm:"uniqueIndex:biz_type_id_uid;index"`
 	Ctime int64
 	Utime int64
 }
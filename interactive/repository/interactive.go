@@ -23,6 +23,9 @@ type InteractiveRepository interface {
 	Liked(ctx context.Context, biz string, id int64, uid int64) (bool, error)
 	Collected(ctx context.Context, biz string, id int64, uid int64) (bool, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]domain.Interactive, error)
+	GetUserLikes(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error)
+	GetUserCollects(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error)
+	DeleteUserData(ctx context.Context, uid int64) error
 }
 
 type CachedReadCntRepository struct {
@@ -198,6 +201,40 @@ func (c *CachedReadCntRepository) Collected(ctx context.Context, biz string, id
 	}
 }
 
+func (c *CachedReadCntRepository) GetUserLikes(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error) {
+	likes, err := c.dao.ListUserLikes(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(likes, func(idx int, src dao2.UserLikeBiz) domain.UserBizRecord {
+		return domain.UserBizRecord{
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Ctime: src.Ctime,
+		}
+	}), nil
+}
+
+func (c *CachedReadCntRepository) GetUserCollects(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error) {
+	collects, err := c.dao.ListUserCollects(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(collects, func(idx int, src dao2.UserCollectionBiz) domain.UserBizRecord {
+		return domain.UserBizRecord{
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Cid:   src.Cid,
+			Ctime: src.Ctime,
+		}
+	}), nil
+}
+
+// DeleteUserData 数据库里面的计数修正之后，缓存里面的计数会有短暂的不准确，等缓存过期就好了
+func (c *CachedReadCntRepository) DeleteUserData(ctx context.Context, uid int64) error {
+	return c.dao.DeleteUserData(ctx, uid)
+}
+
 func (c *CachedReadCntRepository) toDomain(intr dao2.Interactive) domain.Interactive {
 	return domain.Interactive{
 		BizId:      intr.BizId,
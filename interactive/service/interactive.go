@@ -19,6 +19,12 @@ type InteractiveService interface {
 	Collect(ctx context.Context, biz string, bizId, cid, uid int64) error
 	Get(ctx context.Context, biz string, bizId, uid int64) (domain.Interactive, error)
 	GetByIds(ctx context.Context, biz string, bizIds []int64) (map[int64]domain.Interactive, error)
+	// GetUserLikes 用户点赞过的资源
+	GetUserLikes(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error)
+	// GetUserCollects 用户收藏过的资源
+	GetUserCollects(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error)
+	// DeleteUserData 删除用户的点赞、收藏数据
+	DeleteUserData(ctx context.Context, uid int64) error
 }
 
 type interactiveService struct {
@@ -45,6 +51,18 @@ func (i *interactiveService) GetByIds(ctx context.Context, biz string, bizIds []
 	return res, nil
 }
 
+func (i *interactiveService) GetUserLikes(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error) {
+	return i.repo.GetUserLikes(ctx, uid, offset, limit)
+}
+
+func (i *interactiveService) GetUserCollects(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error) {
+	return i.repo.GetUserCollects(ctx, uid, offset, limit)
+}
+
+func (i *interactiveService) DeleteUserData(ctx context.Context, uid int64) error {
+	return i.repo.DeleteUserData(ctx, uid)
+}
+
 func (i *interactiveService) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
 	return i.repo.IncrReadCnt(ctx, biz, bizId)
 }
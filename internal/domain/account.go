@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// AccountDeletion 注销账号的申请
+// 申请之后有一个冷静期，冷静期内可以撤销，过了冷静期才会真的执行注销
+type AccountDeletion struct {
+	Uid    int64
+	Status AccountDeletionStatus
+	// 冷静期结束的时间，也就是真正执行注销的时间
+	ExecuteAt time.Time
+	Ctime     time.Time
+}
+
+type AccountDeletionStatus uint8
+
+const (
+	// AccountDeletionStatusUnknown 未知状态，也就是没有申请过
+	AccountDeletionStatusUnknown AccountDeletionStatus = iota
+	// AccountDeletionStatusPending 冷静期中
+	AccountDeletionStatusPending
+	// AccountDeletionStatusCancelled 用户撤销了
+	AccountDeletionStatusCancelled
+	// AccountDeletionStatusDone 已经注销
+	AccountDeletionStatusDone
+)
+
+func (s AccountDeletionStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+// DataExport 导出个人数据的任务
+type DataExport struct {
+	Id     int64
+	Uid    int64
+	Status DataExportStatus
+	// 导出的压缩包在本地的路径，只有导出成功之后才有
+	Path  string
+	Ctime time.Time
+	Utime time.Time
+}
+
+type DataExportStatus uint8
+
+const (
+	DataExportStatusUnknown DataExportStatus = iota
+	// DataExportStatusPending 等待导出
+	DataExportStatusPending
+	// DataExportStatusDone 导出完毕，可以下载了
+	DataExportStatusDone
+	// DataExportStatusFailed 导出失败
+	DataExportStatusFailed
+)
+
+func (s DataExportStatus) ToUint8() uint8 {
+	return uint8(s)
+}
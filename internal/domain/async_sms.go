@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// AsyncSMS 发送失败，等待重试的短信
+type AsyncSMS struct {
+	Id      int64
+	TplId   string
+	Args    []string
+	Numbers []string
+	// RetryCnt 已经重试的次数
+	RetryCnt int
+	Status   AsyncSMSStatus
+	// NextTime 下一次重试的时间
+	NextTime time.Time
+	// Deadline 过了这个时间就不再重试，例如验证码的有效期，零值表示没有限制
+	Deadline time.Time
+	// LastErr 最近一次失败的原因，方便排查
+	LastErr string
+	Ctime   time.Time
+	Utime   time.Time
+}
+
+type AsyncSMSStatus uint8
+
+const (
+	AsyncSMSStatusUnknown AsyncSMSStatus = iota
+	// AsyncSMSStatusWaiting 等待重试
+	AsyncSMSStatusWaiting
+	// AsyncSMSStatusSuccess 重试成功
+	AsyncSMSStatusSuccess
+	// AsyncSMSStatusFailed 重试次数用完了，依旧失败
+	AsyncSMSStatusFailed
+)
+
+func (s AsyncSMSStatus) ToUint8() uint8 {
+	return uint8(s)
+}
@@ -0,0 +1,32 @@
+package domain
+
+// CodeClient 请求发送验证码的客户端，用来限制同一个 IP 或者设备的发送次数
+type CodeClient struct {
+	IP string
+	// Device 客户端生成的设备标识，可能为空
+	Device string
+	// CaptchaToken 通过图形验证码之后拿到的凭证，发送次数超过软限制之后必须提供
+	CaptchaToken string
+}
+
+// CodeChannel 验证码的发送渠道
+type CodeChannel string
+
+const (
+	CodeChannelSMS   CodeChannel = "sms"
+	CodeChannelEmail CodeChannel = "email"
+	// CodeChannelVoice 语音验证码，打电话念给用户听
+	CodeChannelVoice CodeChannel = "voice"
+)
+
+// CodeTarget 验证码发给谁
+// 渠道也是目标的一部分，同一个手机号的短信验证码和语音验证码是两个不同的验证码
+type CodeTarget struct {
+	Channel CodeChannel
+	// Address 手机号或者邮箱
+	Address string
+}
+
+func (t CodeTarget) String() string {
+	return string(t.Channel) + ":" + t.Address
+}
@@ -0,0 +1,42 @@
+package domain
+
+// FeedItem 关注流中的一条记录
+type FeedItem struct {
+	// 文章 ID
+	Aid int64
+	// 作者 ID
+	AuthorId int64
+	// 发表时间，毫秒数，关注流按照这个排序，同时也是翻页的游标
+	Ctime int64
+	// Article 收件箱和发件箱里面只有 ID，读的时候再根据 Aid 填充
+	Article Article
+}
+
+// FeedPage 一页关注流
+type FeedPage struct {
+	Items []FeedItem
+	// Next 下一页的游标，被过滤掉的文章也算在内，为零值说明没有更多了
+	Next FeedCursor
+}
+
+// Cursor 以这一条作为下一页的游标
+func (f FeedItem) Cursor() FeedCursor {
+	return FeedCursor{Ctime: f.Ctime, Aid: f.Aid}
+}
+
+// Newer 关注流中 f 是否排在 other 前面
+// 按照发表时间倒序，同一毫秒发表的再按照文章 ID 倒序
+func (f FeedItem) Newer(other FeedItem) bool {
+	if f.Ctime != other.Ctime {
+		return f.Ctime > other.Ctime
+	}
+	return f.Aid > other.Aid
+}
+
+// FeedCursor 关注流翻页的游标，也就是上一页最后一条的发表时间和文章 ID
+// 只用发表时间的话，同一毫秒发表的文章刚好跨页的时候会被漏掉
+type FeedCursor struct {
+	Ctime int64
+	// Aid 为 0 的时候不包含 Ctime 这一毫秒发表的任何文章
+	Aid int64
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// PATPrefix 访问令牌的前缀，用来和 JWT 区分，也方便做泄露扫描
+const PATPrefix = "wbk_"
+
+// PersonalAccessToken 用户自己创建的访问令牌，给脚本、内部工具调用接口用
+// 令牌本身只在创建的时候返回一次，我们只保存它的哈希值
+type PersonalAccessToken struct {
+	Id     int64
+	Uid    int64
+	Name   string
+	Scopes []Scope
+	// 过期时间
+	Expiration time.Time
+	Ctime      time.Time
+}
+
+func (t PersonalAccessToken) Expired(now time.Time) bool {
+	return !now.Before(t.Expiration)
+}
+
+// HasScope 令牌是否拥有这个权限范围
+func (t PersonalAccessToken) HasScope(s Scope) bool {
+	for _, scope := range t.Scopes {
+		if scope == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Scope 访问令牌的权限范围，每一个范围对应一组接口
+type Scope string
+
+const (
+	ScopeUserRead     Scope = "user:read"
+	ScopeArticleRead  Scope = "article:read"
+	ScopeArticleWrite Scope = "article:write"
+	ScopeFeedRead     Scope = "feed:read"
+)
+
+var scopes = map[Scope]struct{}{
+	ScopeUserRead:     {},
+	ScopeArticleRead:  {},
+	ScopeArticleWrite: {},
+	ScopeFeedRead:     {},
+}
+
+func (s Scope) Valid() bool {
+	_, ok := scopes[s]
+	return ok
+}
@@ -0,0 +1,39 @@
+package domain
+
+// QRLoginTicket 扫码登录的票据，桌面端展示成二维码，移动端扫码之后确认
+type QRLoginTicket struct {
+	Ticket string
+	// Secret 查询状态的凭证，只返回给创建票据的桌面端
+	// Ticket 会放在二维码里面，谁都能看到，所以不能只靠 Ticket 拿到登录态
+	Secret string
+	Status QRLoginStatus
+	// 扫码的用户，扫码之后才有
+	Uid int64
+	// 发起登录的桌面端的信息，移动端确认的时候展示给用户看
+	UserAgent string
+	IP        string
+}
+
+type QRLoginStatus uint8
+
+const (
+	// QRLoginStatusUnknown 票据不存在或者已经过期
+	QRLoginStatusUnknown QRLoginStatus = iota
+	// QRLoginStatusPending 等待扫码
+	QRLoginStatusPending
+	// QRLoginStatusScanned 已经扫码，等待确认
+	QRLoginStatusScanned
+	// QRLoginStatusConfirmed 移动端确认登录
+	QRLoginStatusConfirmed
+	// QRLoginStatusRejected 移动端拒绝登录
+	QRLoginStatusRejected
+)
+
+func (s QRLoginStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+// Final 是否已经是最终状态，桌面端不需要再等了
+func (s QRLoginStatus) Final() bool {
+	return s == QRLoginStatusUnknown || s == QRLoginStatusConfirmed || s == QRLoginStatusRejected
+}
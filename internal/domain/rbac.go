@@ -0,0 +1,94 @@
+package domain
+
+import "time"
+
+// Role 角色，一个用户可以有多个角色
+// 角色和权限的对应关系是写死在代码里面的，用户和角色的对应关系存在数据库里面
+type Role string
+
+const (
+	// RoleAdmin 超级管理员，拥有全部权限
+	RoleAdmin Role = "admin"
+	// RoleModerator 内容审核员，可以封禁用户、下架文章
+	RoleModerator Role = "moderator"
+	// RoleSupport 客服，可以查用户和短信的发送记录，不能做任何修改
+	RoleSupport Role = "support"
+)
+
+// Permission 权限，命名格式是 资源:操作
+type Permission string
+
+const (
+	PermissionUserRead         Permission = "user:read"
+	PermissionUserBan          Permission = "user:ban"
+	PermissionArticleUnpublish Permission = "article:unpublish"
+	PermissionAuditRead        Permission = "audit:read"
+	PermissionRoleManage       Permission = "role:manage"
+	// PermissionSMSRead 查看短信的发送记录
+	PermissionSMSRead Permission = "sms:read"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionUserRead,
+		PermissionUserBan,
+		PermissionArticleUnpublish,
+		PermissionAuditRead,
+		PermissionRoleManage,
+		PermissionSMSRead,
+	},
+	RoleModerator: {
+		PermissionUserRead,
+		PermissionUserBan,
+		PermissionArticleUnpublish,
+	},
+	RoleSupport: {
+		PermissionUserRead,
+		PermissionSMSRead,
+	},
+}
+
+// Valid 是否是已知的角色
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// HasPermission 这些角色里面，是否有任何一个角色拥有权限 p
+func HasPermission(roles []Role, p Permission) bool {
+	for _, r := range roles {
+		for _, rp := range r.Permissions() {
+			if rp == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AuditLog 管理员操作的审计日志
+type AuditLog struct {
+	Id int64
+	// 操作人
+	OperatorId int64
+	Action     AuditAction
+	// 操作的对象，例如用户 ID、文章 ID
+	TargetId int64
+	// 附加的说明，例如封禁的原因
+	Detail string
+	Ctime  time.Time
+}
+
+type AuditAction string
+
+const (
+	AuditActionBanUser          AuditAction = "ban_user"
+	AuditActionUnbanUser        AuditAction = "unban_user"
+	AuditActionUnpublishArticle AuditAction = "unpublish_article"
+	AuditActionAssignRole       AuditAction = "assign_role"
+	AuditActionRevokeRole       AuditAction = "revoke_role"
+)
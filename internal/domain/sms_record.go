@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// SMSRecord 一次短信发送的记录，每个号码一条，用来排查用户收不到短信的问题
+// 故障转移的时候每个尝试过的服务商都会有一条
+type SMSRecord struct {
+	Id       int64
+	Provider string
+	TplId    string
+	// Number 写入的时候是完整的 E.164 号码，查出来的是打码之后的，例如 +86138****5678
+	Number string
+	// MessageId 服务商返回的消息 ID，状态报告靠这个对应到记录上
+	MessageId string
+	// Latency 调用服务商接口花的时间
+	Latency time.Duration
+	Status  SMSRecordStatus
+	// Err 发送失败的原因
+	Err string
+	// ReportCode 和 ReportMsg 是状态报告里面服务商给的错误码和说明
+	ReportCode string
+	ReportMsg  string
+	ReportTime time.Time
+	Ctime      time.Time
+	Utime      time.Time
+}
+
+type SMSRecordStatus uint8
+
+const (
+	SMSRecordStatusUnknown SMSRecordStatus = iota
+	// SMSRecordStatusFailed 服务商没有受理
+	SMSRecordStatusFailed
+	// SMSRecordStatusSent 服务商已经受理，等待状态报告
+	SMSRecordStatusSent
+	// SMSRecordStatusDelivered 用户已经收到
+	SMSRecordStatusDelivered
+	// SMSRecordStatusUndelivered 运营商那边没有送达，例如停机、拦截
+	SMSRecordStatusUndelivered
+)
+
+func (s SMSRecordStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+func (s SMSRecordStatus) String() string {
+	switch s {
+	case SMSRecordStatusFailed:
+		return "failed"
+	case SMSRecordStatusSent:
+		return "sent"
+	case SMSRecordStatusDelivered:
+		return "delivered"
+	case SMSRecordStatusUndelivered:
+		return "undelivered"
+	default:
+		return "unknown"
+	}
+}
+
+// SMSReceipt 服务商推送过来的状态报告
+type SMSReceipt struct {
+	MessageId string
+	Delivered bool
+	Code      string
+	Msg       string
+	// ReportTime 运营商给出结果的时间
+	ReportTime time.Time
+}
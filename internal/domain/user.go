@@ -2,13 +2,26 @@ package domain
 
 import "time"
 
+// DefaultPhoneRegion 用户输入的手机号不带国际区号的时候，当成国内的号码
+const DefaultPhoneRegion = "CN"
+
 type User struct {
 	Id       int64
 	Email    string
 	Nickname string
 	Password string
+	// Phone E.164 格式，例如 +8613812345678
 	Phone    string
 	AboutMe  string
 	Ctime    time.Time
 	Birthday time.Time
+
+	// Handle 用户名，全局唯一，可以修改，用于个人主页的地址
+	Handle string
+	// HandleUtime 上一次修改用户名的时间，用来限制修改频率
+	HandleUtime time.Time
+	// Avatar 头像的 URL
+	Avatar string
+	// Banned 是否被管理员封禁了，封禁之后不能登录
+	Banned bool
 }
@@ -4,16 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/IBM/sarama"
+	"strconv"
 )
 
 // topicReadEvent 定义了 Kafka 消息队列中的topic，用于接收文章阅读事件
 // 在这里，所有与文章阅读相关的事件都会被发送到该topic
 const topicReadEvent = "article_read_event"
 
+// topicPublishEvent 文章发表事件的 topic，关注流之类的下游业务会消费
+const topicPublishEvent = "article_publish_event"
+
 // Producer 生产者接口
 type Producer interface {
 	// ProduceReadEvent 用于发送文章阅读事件
 	ProduceReadEvent(ctx context.Context, evt ReadEvent) error
+	// ProducePublishEvent 用于发送文章发表事件
+	ProducePublishEvent(ctx context.Context, evt PublishEvent) error
 }
 
 // KafkaProducer 定义了 Kafka 消息生产者的实现，它实现了 Producer 接口
@@ -44,9 +50,32 @@ func (k *KafkaProducer) ProduceReadEvent(ctx context.Context, evt ReadEvent) err
 	return err // 返回发送消息时的错误（如果有的话）
 }
 
+// ProducePublishEvent 将发表事件转换为 JSON 格式，并发送到 Kafka topic中
+func (k *KafkaProducer) ProducePublishEvent(ctx context.Context, evt PublishEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = k.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topicPublishEvent,
+		// 同一个作者的事件落到同一个分区，保证顺序
+		Key:   sarama.StringEncoder(strconv.FormatInt(evt.Uid, 10)),
+		Value: sarama.ByteEncoder(data),
+	})
+	return err
+}
+
 // ReadEvent 定义了一个文章阅读事件的结构体
 // 包含了用户 ID（Uid）和文章 ID（Aid），表示某个用户阅读了某篇文章
 type ReadEvent struct {
 	Uid int64 // 用户 ID，标识阅读文章的用户
 	Aid int64 // 文章 ID，标识被阅读的文章
 }
+
+// PublishEvent 文章发表事件，一篇文章每发表（或者重新发表）一次就会有一个事件
+type PublishEvent struct {
+	Aid int64 // 文章 ID
+	Uid int64 // 作者 ID
+	// 发表时间，毫秒数
+	Ctime int64
+}
@@ -0,0 +1,56 @@
+package feed
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/article"
+	"webook/internal/service"
+	"webook/pkg/logger"
+	"webook/pkg/saramax"
+)
+
+const topicPublishEvent = "article_publish_event"
+
+// ArticlePublishEventConsumer 消费文章发表事件，生成关注流
+type ArticlePublishEventConsumer struct {
+	client sarama.Client
+	svc    service.FeedService
+	l      logger.Logger
+}
+
+func NewArticlePublishEventConsumer(client sarama.Client, svc service.FeedService, l logger.Logger) *ArticlePublishEventConsumer {
+	return &ArticlePublishEventConsumer{
+		client: client,
+		svc:    svc,
+		l:      l,
+	}
+}
+
+// Start 启动消费者组，"feed" 是消费者组的名称
+func (c *ArticlePublishEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("feed", c.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(), []string{topicPublishEvent},
+			saramax.NewHandler[article.PublishEvent](c.l, c.Consume))
+		if er != nil {
+			c.l.Error("退出了消费循环异常", logger.Error(er))
+		}
+	}()
+	return err
+}
+
+func (c *ArticlePublishEventConsumer) Consume(msg *sarama.ConsumerMessage, evt article.PublishEvent) error {
+	// 推送给粉丝可能要分很多批，所以超时时间给长一点
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	return c.svc.HandlePublish(ctx, domain.FeedItem{
+		Aid:      evt.Aid,
+		AuthorId: evt.Uid,
+		Ctime:    evt.Ctime,
+	})
+}
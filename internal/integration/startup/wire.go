@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"time"
+	intrv1 "webook/api/proto/gen/intr/v1"
 	article2 "webook/internal/events/article"
 	"webook/internal/job"
 	"webook/internal/repository"
@@ -13,7 +14,11 @@ import (
 	"webook/internal/repository/dao"
 	"webook/internal/repository/dao/article"
 	"webook/internal/service"
+	"webook/internal/service/captcha"
+	"webook/internal/service/sms"
+	"webook/internal/service/sms/async"
 	"webook/internal/web"
+	"webook/internal/web/client"
 	ijwt "webook/internal/web/jwt"
 	"webook/ioc"
 
@@ -29,11 +34,19 @@ var thirdProvider = wire.NewSet(
 	InitKafka, NewSyncProducer,
 )
 
-var userSvcProvider = wire.NewSet(
+var userRepoProvider = wire.NewSet(
 	dao.NewGormUserDAO,
 	cache.NewRedisUserCache,
-	repository.NewCachedUserRepository,
-	service.NewUserService)
+	repository.NewCachedUserRepository)
+
+var userSvcProvider = wire.NewSet(
+	userRepoProvider,
+	cache.NewRedisTokenCache,
+	repository.NewCachedTokenRepository,
+	ioc.InitEmailService,
+	ioc.InitPasswordHasher,
+	ioc.InitPasswordPolicy,
+	ioc.InitUserService)
 
 var articlSvcProvider = wire.NewSet(
 	article.NewGORMArticleDAO,
@@ -49,6 +62,12 @@ var interactiveSvcProvider = wire.NewSet(
 	repository_cache.NewRedisInteractiveCache,
 )
 
+// interactive 和 follow 都通过客户端调用，和线上一样
+var grpcClientProvider = wire.NewSet(
+	ioc.InitIntrGRPCClient,
+	ioc.InitFollowGRPCClient,
+)
+
 var rankServiceProvider = wire.NewSet(
 	service.NewBatchRankingService,
 	repository.NewCachedRankingRepository,
@@ -56,6 +75,14 @@ var rankServiceProvider = wire.NewSet(
 	cache.NewRankingLocalCache,
 )
 
+var jwtProvider = wire.NewSet(
+	dao.NewGORMRBACDAO,
+	repository.NewRBACRepository,
+	ioc.InitRBACService,
+	ioc.InitJWTKeys,
+	ioc.InitJWTHandler,
+)
+
 var jobProviderSet = wire.NewSet(
 	service.NewCronJobService,
 	repository.NewCronJobRepositoryImpl,
@@ -68,20 +95,70 @@ func InitWebServer() *gin.Engine {
 		userSvcProvider,
 		articlSvcProvider,
 		interactiveSvcProvider,
+		grpcClientProvider,
+		rankServiceProvider,
+		jwtProvider,
+
+		// DAO 部分
+		dao.NewGORMAccountDAO,
+		dao.NewGORMPATDAO,
+		dao.NewGORMAsyncSMSDAO,
+		dao.NewGORMSMSRecordDAO,
+
+		// Cache 部分
 		cache.NewRedisCodeCache,
+		cache.NewRedisCodeLimitCache,
+		cache.NewRedisFeedCache,
+		cache.NewRedisQRLoginCache,
+
+		// repository 部分
 		repository.NewCachedCodeRepository,
+		repository.NewCachedCodeLimitRepository,
+		repository.NewCachedFeedRepository,
+		repository.NewCachedQRLoginRepository,
+		repository.NewAccountRepository,
+		repository.NewPATRepository,
+		ioc.InitAsyncSMSRepository,
+		ioc.InitSMSRecordRepository,
+
 		// service 部分
 		// 集成测试我们显式指定使用内存实现
+		ioc.InitSMSInbox,
+		ioc.InitSMSTemplates,
 		ioc.InitSmsService,
+		wire.Bind(new(sms.Service), new(*async.Service)),
+		ioc.InitCodeService,
+		service.NewCaptchaService,
+		captcha.NewImageGenerator,
+		wire.Bind(new(captcha.Generator), new(*captcha.ImageGenerator)),
+		service.NewFeedService,
+		service.NewSMSRecordService,
+		ioc.InitAccountService,
+		ioc.InitStorageService,
+		service.NewAdminService,
+		service.NewPATService,
+		service.NewQRLoginService,
+		ioc.InitMagicLinkService,
+		wire.Bind(new(service.SessionRevoker), new(ijwt.Handler)),
 
-		service.NewSMSCodeService,
 		// handler 部分
-		web.NewUserHandler,
+		ioc.InitUserHandler,
 		web.NewArticleHandler,
-
-		ijwt.NewRedisHandler,
+		web.NewFollowHandler,
+		web.NewFeedHandler,
+		web.NewAccountHandler,
+		web.NewProfileHandler,
+		web.NewAdminHandler,
+		web.NewJWKSHandler,
+		web.NewPATHandler,
+		web.NewQRLoginHandler,
+		web.NewMagicLinkHandler,
+		web.NewDevSMSHandler,
+		web.NewCaptchaHandler,
+		ioc.InitSMSHandler,
 
 		// gin 的中间件
+		ioc.InitAuthPolicies,
 		ioc.GinMiddlewares,
 
 		// Web 服务器
@@ -94,8 +171,10 @@ func InitWebServer() *gin.Engine {
 func InitArticleHandler(dao article.ArticleDAO) *web.ArticleHandler {
 	wire.Build(
 		thirdProvider,
-		userSvcProvider,
 		interactiveSvcProvider,
+		grpcClientProvider,
+		rankServiceProvider,
+		userRepoProvider,
 		article2.NewKafkaProducer,
 		cache.NewRedisArticleCache,
 		repository.NewArticleRepository,
@@ -108,7 +187,7 @@ func InitUserSvc() service.UserService {
 	wire.Build(
 		thirdProvider,
 		userSvcProvider)
-	return service.NewUserService(nil, nil)
+	return service.NewUserService(nil, nil, nil, nil, nil, "", nil)
 }
 
 func InitRankingService(expiration time.Duration) service.RankingService {
@@ -116,6 +195,9 @@ func InitRankingService(expiration time.Duration) service.RankingService {
 		thirdProvider,
 		interactiveSvcProvider,
 		articlSvcProvider,
+		// 排行榜直接调用本地的 interactive 服务
+		client.NewInteractiveServiceAdapter,
+		wire.Bind(new(intrv1.InteractiveServiceClient), new(*client.InteractiveLocalAdapter)),
 		// 用不上这个 user repo，所以随便搞一个
 		wire.InterfaceValue(
 			new(repository.UserRepository),
@@ -128,8 +210,8 @@ func InitRankingService(expiration time.Duration) service.RankingService {
 func InitJwtHdl() ijwt.Handler {
 	wire.Build(
 		thirdProvider,
-		ijwt.NewRedisHandler)
-	return ijwt.NewRedisHandler(nil)
+		jwtProvider)
+	return ijwt.NewRedisHandler(nil, nil, ijwt.Keys{})
 }
 
 func InitJobScheduler() *job.Scheduler {
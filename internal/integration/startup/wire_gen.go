@@ -21,7 +21,9 @@ import (
 	"webook/internal/repository/dao"
 	"webook/internal/repository/dao/article"
 	"webook/internal/service"
+	"webook/internal/service/captcha"
 	"webook/internal/web"
+	"webook/internal/web/client"
 	"webook/internal/web/jwt"
 	"webook/ioc"
 )
@@ -31,19 +33,42 @@ import (
 //go:generate wire
 func InitWebServer() *gin.Engine {
 	cmdable := InitTestRedis()
-	handler := jwt.NewRedisHandler(cmdable)
-	logger := InitTestLogger()
-	v := ioc.GinMiddlewares(cmdable, handler, logger)
 	gormDB := InitTestDB()
+	rbacdao := dao.NewGORMRBACDAO(gormDB)
+	rbacRepository := repository.NewRBACRepository(rbacdao)
+	logger := InitTestLogger()
+	rbacService := ioc.InitRBACService(rbacRepository, logger)
+	keys := ioc.InitJWTKeys()
+	handler := ioc.InitJWTHandler(cmdable, rbacService, keys)
+	authPolicies := ioc.InitAuthPolicies()
+	patdao := dao.NewGORMPATDAO(gormDB)
+	patRepository := repository.NewPATRepository(patdao)
 	userDAO := dao.NewGormUserDAO(gormDB)
 	userCache := cache.NewRedisUserCache(cmdable)
 	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
-	userService := service.NewUserService(userRepository, logger)
-	smsService := ioc.InitSmsService(cmdable)
+	patService := service.NewPATService(patRepository, userRepository)
+	v := ioc.GinMiddlewares(cmdable, handler, authPolicies, patService, logger)
+	tokenCache := cache.NewRedisTokenCache(cmdable)
+	tokenRepository := repository.NewCachedTokenRepository(tokenCache)
+	emailService := ioc.InitEmailService(logger)
+	hasher := ioc.InitPasswordHasher()
+	policy := ioc.InitPasswordPolicy()
+	userService := ioc.InitUserService(userRepository, tokenRepository, emailService, hasher, policy, logger)
+	asyncSMSDAO := dao.NewGORMAsyncSMSDAO(gormDB)
+	asyncSMSRepository := ioc.InitAsyncSMSRepository(asyncSMSDAO)
+	smsRecordDAO := dao.NewGORMSMSRecordDAO(gormDB)
+	smsRecordRepository := ioc.InitSMSRecordRepository(smsRecordDAO)
+	memoryService := ioc.InitSMSInbox()
+	templateRegistry := ioc.InitSMSTemplates()
+	asyncService := ioc.InitSmsService(cmdable, asyncSMSRepository, smsRecordRepository, memoryService, templateRegistry, logger)
 	codeCache := cache.NewRedisCodeCache(cmdable)
 	codeRepository := repository.NewCachedCodeRepository(codeCache)
-	codeService := service.NewSMSCodeService(smsService, codeRepository, logger)
-	userHandler := web.NewUserHandler(userService, codeService, handler)
+	codeLimitCache := cache.NewRedisCodeLimitCache(cmdable)
+	codeLimitRepository := repository.NewCachedCodeLimitRepository(codeLimitCache)
+	imageGenerator := captcha.NewImageGenerator()
+	captchaService := service.NewCaptchaService(tokenRepository, imageGenerator)
+	codeService := ioc.InitCodeService(asyncService, emailService, memoryService, codeRepository, codeLimitRepository, captchaService, logger)
+	userHandler := ioc.InitUserHandler(userService, codeService, captchaService, cmdable, handler, logger)
 	articleDAO := article.NewGORMArticleDAO(gormDB)
 	articleCache := cache.NewRedisArticleCache(cmdable)
 	articleRepository := repository.NewArticleRepository(articleDAO, userRepository, articleCache, logger)
@@ -55,8 +80,39 @@ func InitWebServer() *gin.Engine {
 	interactiveCache := cache2.NewRedisInteractiveCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, interactiveCache, logger)
 	interactiveService := service2.NewInteractiveService(interactiveRepository, logger)
-	articleHandler := web.NewArticleHandler(articleService, interactiveService, logger)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler)
+	followServiceClient := ioc.InitFollowGRPCClient()
+	interactiveServiceClient := ioc.InitIntrGRPCClient(interactiveService, articleService, followServiceClient, logger)
+	redisRankingCache := cache.NewRedisRankingCache(cmdable)
+	rankingLocalCache := cache.NewRankingLocalCache()
+	rankingRepository := repository.NewCachedRankingRepository(redisRankingCache, rankingLocalCache)
+	rankingService := service.NewBatchRankingService(interactiveServiceClient, articleService, rankingRepository)
+	articleHandler := web.NewArticleHandler(articleService, interactiveServiceClient, followServiceClient, rankingService, logger)
+	followHandler := web.NewFollowHandler(followServiceClient, logger)
+	feedCache := cache.NewRedisFeedCache(cmdable)
+	feedRepository := repository.NewCachedFeedRepository(feedCache)
+	feedService := service.NewFeedService(feedRepository, articleRepository, followServiceClient)
+	feedHandler := web.NewFeedHandler(feedService, logger)
+	accountDAO := dao.NewGORMAccountDAO(gormDB)
+	accountRepository := repository.NewAccountRepository(accountDAO)
+	accountService := ioc.InitAccountService(accountRepository, userRepository, articleRepository, interactiveServiceClient, handler, logger)
+	accountHandler := web.NewAccountHandler(accountService, logger)
+	storageService := ioc.InitStorageService()
+	profileHandler := web.NewProfileHandler(userService, articleService, followServiceClient, storageService, logger)
+	adminService := service.NewAdminService(userRepository, articleRepository, rbacRepository, handler, logger)
+	adminHandler := web.NewAdminHandler(adminService, rbacService, logger)
+	jwksHandler := web.NewJWKSHandler(keys)
+	patHandler := web.NewPATHandler(patService, logger)
+	qrLoginCache := cache.NewRedisQRLoginCache(cmdable)
+	qrLoginRepository := repository.NewCachedQRLoginRepository(qrLoginCache)
+	qrLoginService := service.NewQRLoginService(qrLoginRepository)
+	qrLoginHandler := web.NewQRLoginHandler(qrLoginService, handler, logger)
+	magicLinkService := ioc.InitMagicLinkService(cmdable, tokenRepository, userService, emailService, logger)
+	magicLinkHandler := web.NewMagicLinkHandler(magicLinkService, handler, logger)
+	devSMSHandler := web.NewDevSMSHandler(memoryService)
+	captchaHandler := web.NewCaptchaHandler(captchaService)
+	smsRecordService := service.NewSMSRecordService(smsRecordRepository)
+	smsHandler := ioc.InitSMSHandler(smsRecordService, memoryService, logger)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, followHandler, feedHandler, accountHandler, profileHandler, adminHandler, jwksHandler, patHandler, qrLoginHandler, magicLinkHandler, devSMSHandler, captchaHandler, smsHandler, logger)
 	return engine
 }
 
@@ -77,7 +133,13 @@ func InitArticleHandler(dao3 article.ArticleDAO) *web.ArticleHandler {
 	interactiveCache := cache2.NewRedisInteractiveCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, interactiveCache, logger)
 	interactiveService := service2.NewInteractiveService(interactiveRepository, logger)
-	articleHandler := web.NewArticleHandler(articleService, interactiveService, logger)
+	followServiceClient := ioc.InitFollowGRPCClient()
+	interactiveServiceClient := ioc.InitIntrGRPCClient(interactiveService, articleService, followServiceClient, logger)
+	redisRankingCache := cache.NewRedisRankingCache(cmdable)
+	rankingLocalCache := cache.NewRankingLocalCache()
+	rankingRepository := repository.NewCachedRankingRepository(redisRankingCache, rankingLocalCache)
+	rankingService := service.NewBatchRankingService(interactiveServiceClient, articleService, rankingRepository)
+	articleHandler := web.NewArticleHandler(articleService, interactiveServiceClient, followServiceClient, rankingService, logger)
 	return articleHandler
 }
 
@@ -87,8 +149,13 @@ func InitUserSvc() service.UserService {
 	cmdable := InitTestRedis()
 	userCache := cache.NewRedisUserCache(cmdable)
 	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
+	tokenCache := cache.NewRedisTokenCache(cmdable)
+	tokenRepository := repository.NewCachedTokenRepository(tokenCache)
 	logger := InitTestLogger()
-	userService := service.NewUserService(userRepository, logger)
+	emailService := ioc.InitEmailService(logger)
+	hasher := ioc.InitPasswordHasher()
+	policy := ioc.InitPasswordPolicy()
+	userService := ioc.InitUserService(userRepository, tokenRepository, emailService, hasher, policy, logger)
 	return userService
 }
 
@@ -100,18 +167,19 @@ func InitRankingService(expiration time.Duration) service.RankingService {
 	logger := InitTestLogger()
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, interactiveCache, logger)
 	interactiveService := service2.NewInteractiveService(interactiveRepository, logger)
+	interactiveLocalAdapter := client.NewInteractiveServiceAdapter(interactiveService)
 	articleDAO := article.NewGORMArticleDAO(gormDB)
 	userRepository := _wireCachedUserRepositoryValue
 	articleCache := cache.NewRedisArticleCache(cmdable)
 	articleRepository := repository.NewArticleRepository(articleDAO, userRepository, articleCache, logger)
-	client := InitKafka()
-	syncProducer := NewSyncProducer(client)
+	saramaClient := InitKafka()
+	syncProducer := NewSyncProducer(saramaClient)
 	producer := article2.NewKafkaProducer(syncProducer)
 	articleService := service.NewArticleService(articleRepository, logger, producer)
 	redisRankingCache := cache.NewRedisRankingCache(cmdable)
 	rankingLocalCache := cache.NewRankingLocalCache()
 	rankingRepository := repository.NewCachedRankingRepository(redisRankingCache, rankingLocalCache)
-	rankingService := service.NewBatchRankingService(interactiveService, articleService, rankingRepository)
+	rankingService := service.NewBatchRankingService(interactiveLocalAdapter, articleService, rankingRepository)
 	return rankingService
 }
 
@@ -121,7 +189,13 @@ var (
 
 func InitJwtHdl() jwt.Handler {
 	cmdable := InitTestRedis()
-	handler := jwt.NewRedisHandler(cmdable)
+	gormDB := InitTestDB()
+	rbacdao := dao.NewGORMRBACDAO(gormDB)
+	rbacRepository := repository.NewRBACRepository(rbacdao)
+	logger := InitTestLogger()
+	rbacService := ioc.InitRBACService(rbacRepository, logger)
+	keys := ioc.InitJWTKeys()
+	handler := ioc.InitJWTHandler(cmdable, rbacService, keys)
 	return handler
 }
 
@@ -143,12 +217,21 @@ var thirdProvider = wire.NewSet(
 	InitKafka, NewSyncProducer,
 )
 
-var userSvcProvider = wire.NewSet(dao.NewGormUserDAO, cache.NewRedisUserCache, repository.NewCachedUserRepository, service.NewUserService)
+var userRepoProvider = wire.NewSet(dao.NewGormUserDAO, cache.NewRedisUserCache, repository.NewCachedUserRepository)
+
+var userSvcProvider = wire.NewSet(
+	userRepoProvider, cache.NewRedisTokenCache, repository.NewCachedTokenRepository, ioc.InitEmailService, ioc.InitPasswordHasher, ioc.InitPasswordPolicy, ioc.InitUserService,
+)
 
 var articlSvcProvider = wire.NewSet(article.NewGORMArticleDAO, article2.NewKafkaProducer, cache.NewRedisArticleCache, repository.NewArticleRepository, service.NewArticleService)
 
 var interactiveSvcProvider = wire.NewSet(service2.NewInteractiveService, repository2.NewCachedInteractiveRepository, dao2.NewGORMInteractiveDAO, cache2.NewRedisInteractiveCache)
 
+// interactive 和 follow 都通过客户端调用，和线上一样
+var grpcClientProvider = wire.NewSet(ioc.InitIntrGRPCClient, ioc.InitFollowGRPCClient)
+
 var rankServiceProvider = wire.NewSet(service.NewBatchRankingService, repository.NewCachedRankingRepository, cache.NewRedisRankingCache, cache.NewRankingLocalCache)
 
+var jwtProvider = wire.NewSet(dao.NewGORMRBACDAO, repository.NewRBACRepository, ioc.InitRBACService, ioc.InitJWTKeys, ioc.InitJWTHandler)
+
 var jobProviderSet = wire.NewSet(service.NewCronJobService, repository.NewCronJobRepositoryImpl, dao.NewGORMJobDAO)
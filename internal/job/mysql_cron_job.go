@@ -99,6 +99,7 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 			// 没有抢占到，进入下一个循环
 			// 这里可以考虑睡眠一段时间
 			// 也可以进一步细分不同的错误，如果是可以容忍的错误，就继续，不然就直接 return
+			s.limiter.Release(1)
 			time.Sleep(s.interval)
 			continue
 		}
@@ -108,6 +109,7 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 			// 不支持的执行方式。
 			s.l.Error("未找到对应的执行器", logger.String("executor", j.Executor))
 			j.CancelFunc()
+			s.limiter.Release(1)
 			continue
 		}
 
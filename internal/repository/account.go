@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+// ErrAccountRecordNotFound 注销申请或者导出任务不存在
+var ErrAccountRecordNotFound = dao.ErrDataNotFound
+
+//go:generate mockgen -source=./account.go -package=repomocks -destination=mocks/account.mock.go AccountRepository
+type AccountRepository interface {
+	SaveDeletion(ctx context.Context, d domain.AccountDeletion) error
+	CancelDeletion(ctx context.Context, uid int64) error
+	FindDeletion(ctx context.Context, uid int64) (domain.AccountDeletion, error)
+	FindDueDeletions(ctx context.Context, now time.Time, limit int) ([]domain.AccountDeletion, error)
+	UpdateDeletionStatus(ctx context.Context, uid int64, status domain.AccountDeletionStatus) error
+
+	CreateExport(ctx context.Context, e domain.DataExport) (int64, error)
+	FindExport(ctx context.Context, id int64) (domain.DataExport, error)
+	FindPendingExports(ctx context.Context, limit int) ([]domain.DataExport, error)
+	UpdateExport(ctx context.Context, id int64, status domain.DataExportStatus, path string) error
+}
+
+type accountRepository struct {
+	dao dao.AccountDAO
+}
+
+func NewAccountRepository(dao dao.AccountDAO) AccountRepository {
+	return &accountRepository{dao: dao}
+}
+
+func (repo *accountRepository) SaveDeletion(ctx context.Context, d domain.AccountDeletion) error {
+	return repo.dao.UpsertDeletion(ctx, dao.AccountDeletion{
+		Uid:       d.Uid,
+		Status:    d.Status.ToUint8(),
+		ExecuteAt: d.ExecuteAt.UnixMilli(),
+	})
+}
+
+func (repo *accountRepository) CancelDeletion(ctx context.Context, uid int64) error {
+	return repo.dao.CancelDeletion(ctx, uid)
+}
+
+func (repo *accountRepository) FindDeletion(ctx context.Context, uid int64) (domain.AccountDeletion, error) {
+	d, err := repo.dao.FindDeletion(ctx, uid)
+	if err != nil {
+		return domain.AccountDeletion{}, err
+	}
+	return repo.deletionToDomain(d), nil
+}
+
+func (repo *accountRepository) FindDueDeletions(ctx context.Context, now time.Time, limit int) ([]domain.AccountDeletion, error) {
+	ds, err := repo.dao.FindDueDeletions(ctx, now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ds, func(idx int, src dao.AccountDeletion) domain.AccountDeletion {
+		return repo.deletionToDomain(src)
+	}), nil
+}
+
+func (repo *accountRepository) UpdateDeletionStatus(ctx context.Context, uid int64, status domain.AccountDeletionStatus) error {
+	return repo.dao.UpdateDeletionStatus(ctx, uid, status.ToUint8())
+}
+
+func (repo *accountRepository) CreateExport(ctx context.Context, e domain.DataExport) (int64, error) {
+	return repo.dao.InsertExport(ctx, dao.DataExport{
+		Uid:    e.Uid,
+		Status: e.Status.ToUint8(),
+	})
+}
+
+func (repo *accountRepository) FindExport(ctx context.Context, id int64) (domain.DataExport, error) {
+	e, err := repo.dao.FindExport(ctx, id)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	return repo.exportToDomain(e), nil
+}
+
+func (repo *accountRepository) FindPendingExports(ctx context.Context, limit int) ([]domain.DataExport, error) {
+	es, err := repo.dao.FindPendingExports(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(es, func(idx int, src dao.DataExport) domain.DataExport {
+		return repo.exportToDomain(src)
+	}), nil
+}
+
+func (repo *accountRepository) UpdateExport(ctx context.Context, id int64, status domain.DataExportStatus, path string) error {
+	return repo.dao.UpdateExport(ctx, id, status.ToUint8(), path)
+}
+
+func (repo *accountRepository) deletionToDomain(d dao.AccountDeletion) domain.AccountDeletion {
+	return domain.AccountDeletion{
+		Uid:       d.Uid,
+		Status:    domain.AccountDeletionStatus(d.Status),
+		ExecuteAt: time.UnixMilli(d.ExecuteAt),
+		Ctime:     time.UnixMilli(d.Ctime),
+	}
+}
+
+func (repo *accountRepository) exportToDomain(e dao.DataExport) domain.DataExport {
+	return domain.DataExport{
+		Id:     e.Id,
+		Uid:    e.Uid,
+		Status: domain.DataExportStatus(e.Status),
+		Path:   e.Path,
+		Ctime:  time.UnixMilli(e.Ctime),
+		Utime:  time.UnixMilli(e.Utime),
+	}
+}
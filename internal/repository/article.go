@@ -11,6 +11,9 @@ import (
 	"webook/pkg/logger"
 )
 
+// ErrArticleNotPublished 文章不存在，或者已经撤回、下线
+var ErrArticleNotPublished = errors.New("文章不存在或者已经下线")
+
 type ArticleRepository interface {
 	Create(ctx context.Context, art domain.Article) (int64, error)
 	Update(ctx context.Context, art domain.Article) error
@@ -20,12 +23,19 @@ type ArticleRepository interface {
 
 	// SyncStatus 仅仅同步状态
 	SyncStatus(ctx context.Context, uid, id int64, status domain.ArticleStatus) error
+	// SyncStatusByAuthor 修改作者全部文章的状态
+	SyncStatusByAuthor(ctx context.Context, uid int64, status domain.ArticleStatus) error
 
 	List(ctx context.Context, author int64, offset int, limit int) ([]domain.Article, error)
 	GetById(ctx context.Context, id int64) (domain.Article, error)
 
+	// GetPublishedById 只返回处于发表状态的文章，否则返回 ErrArticleNotPublished
 	GetPublishedById(ctx context.Context, id int64) (domain.Article, error)
 	ListPub(ctx context.Context, utime time.Time, offset int, limit int) ([]domain.Article, error)
+	// ListPubByAuthor 作者已经发表的文章，不包含撤回的
+	ListPubByAuthor(ctx context.Context, author int64, offset int, limit int) ([]domain.Article, error)
+	// ListPubByIds 批量查询处于发表状态的文章，撤回、下线或者不存在的不会返回，也不保证顺序
+	ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
 }
 
 type CachedArticleRepository struct {
@@ -54,15 +64,44 @@ func (repo *CachedArticleRepository) ListPub(ctx context.Context, utime time.Tim
 	}), nil
 }
 
+func (repo *CachedArticleRepository) ListPubByAuthor(ctx context.Context, author int64, offset int, limit int) ([]domain.Article, error) {
+	val, err := repo.dao.ListPubByAuthor(ctx, author, domain.ArticleStatusPublished.ToUint8(), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[article.PublishedArticle, domain.Article](val, func(idx int, src article.PublishedArticle) domain.Article {
+		return repo.PublishedArticletoDomain(src)
+	}), nil
+}
+
+func (repo *CachedArticleRepository) ListPubByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	val, err := repo.dao.ListPubByIds(ctx, ids, domain.ArticleStatusPublished.ToUint8())
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[article.PublishedArticle, domain.Article](val, func(idx int, src article.PublishedArticle) domain.Article {
+		return repo.PublishedArticletoDomain(src)
+	}), nil
+}
+
 func (repo *CachedArticleRepository) GetPublishedById(ctx context.Context, id int64) (domain.Article, error) {
 	res, err := repo.cache.GetPub(ctx, id)
 	if err == nil {
+		if res.Status != domain.ArticleStatusPublished {
+			return domain.Article{}, ErrArticleNotPublished
+		}
 		return res, err
 	}
 	art, err := repo.dao.GetPubById(ctx, id)
 	if err != nil {
 		return domain.Article{}, err
 	}
+	if domain.ArticleStatus(art.Status) != domain.ArticleStatusPublished {
+		return domain.Article{}, ErrArticleNotPublished
+	}
 	user, err := repo.userRepo.FindById(ctx, art.AuthorId)
 	if err != nil {
 		return domain.Article{}, err
@@ -150,7 +189,28 @@ func (repo *CachedArticleRepository) preCache(ctx context.Context, arts []domain
 }
 
 func (repo *CachedArticleRepository) SyncStatus(ctx context.Context, uid, id int64, status domain.ArticleStatus) error {
-	return repo.dao.SyncStatus(ctx, uid, id, status.ToUint8())
+	err := repo.dao.SyncStatus(ctx, uid, id, status.ToUint8())
+	if err != nil {
+		return err
+	}
+	// 撤回或者下线之后，读者端的缓存必须删掉，不然还能继续读到
+	err = repo.cache.DelPub(ctx, id)
+	if err != nil {
+		return err
+	}
+	return repo.cache.DelFirstPage(ctx, uid)
+}
+
+func (repo *CachedArticleRepository) SyncStatusByAuthor(ctx context.Context, uid int64, status domain.ArticleStatus) error {
+	ids, err := repo.dao.SyncStatusByAuthor(ctx, uid, status.ToUint8())
+	if err != nil {
+		return err
+	}
+	err = repo.cache.DelPub(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	return repo.cache.DelFirstPage(ctx, uid)
 }
 
 func (repo *CachedArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
@@ -230,6 +290,8 @@ func (repo *CachedArticleRepository) toDomain(art article.Article) domain.Articl
 		Author: domain.Author{
 			Id: art.AuthorId,
 		},
+		Ctime: time.UnixMilli(art.Ctime),
+		Utime: time.UnixMilli(art.Utime),
 	}
 }
 
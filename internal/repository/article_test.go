@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	cachemocks "webook/internal/repository/cache/mocks"
+	"webook/internal/repository/dao/article"
+	daomocks "webook/internal/repository/dao/mocks"
+)
+
+func TestCachedArticleRepository_GetPublishedById(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache)
+
+		id int64
+
+		wantArt domain.Article
+		wantErr error
+	}{
+		{
+			name: "命中缓存",
+			mock: func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().GetPub(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Title:  "标题",
+					Status: domain.ArticleStatusPublished,
+				}, nil)
+				return d, c
+			},
+			id: 1,
+			wantArt: domain.Article{
+				Id:     1,
+				Title:  "标题",
+				Status: domain.ArticleStatusPublished,
+			},
+		},
+		{
+			name: "缓存里面是已经下线的文章",
+			mock: func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().GetPub(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Status: domain.ArticleStatusPrivate,
+				}, nil)
+				return d, c
+			},
+			id:      1,
+			wantErr: ErrArticleNotPublished,
+		},
+		{
+			name: "数据库里面是已经下线的文章",
+			mock: func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().GetPub(gomock.Any(), int64(1)).
+					Return(domain.Article{}, cache.ErrKeyNotExist)
+				d.EXPECT().GetPubById(gomock.Any(), int64(1)).Return(article.PublishedArticle{
+					Article: article.Article{
+						Id:       1,
+						AuthorId: 2,
+						Status:   domain.ArticleStatusPrivate.ToUint8(),
+					},
+				}, nil)
+				return d, c
+			},
+			id:      1,
+			wantErr: ErrArticleNotPublished,
+		},
+		{
+			name: "查询数据库失败",
+			mock: func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				c.EXPECT().GetPub(gomock.Any(), int64(1)).
+					Return(domain.Article{}, cache.ErrKeyNotExist)
+				d.EXPECT().GetPubById(gomock.Any(), int64(1)).
+					Return(article.PublishedArticle{}, errors.New("mock db error"))
+				return d, c
+			},
+			id:      1,
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			d, c := tc.mock(ctrl)
+			repo := NewArticleRepository(d, nil, c, nil)
+			art, err := repo.GetPublishedById(context.Background(), tc.id)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArt, art)
+		})
+	}
+}
+
+func TestCachedArticleRepository_SyncStatus(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache)
+
+		uid int64
+		id  int64
+
+		wantErr error
+	}{
+		{
+			name: "下线并删除缓存",
+			mock: func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				d.EXPECT().SyncStatus(gomock.Any(), int64(2), int64(1),
+					domain.ArticleStatusPrivate.ToUint8()).Return(nil)
+				c.EXPECT().DelPub(gomock.Any(), int64(1)).Return(nil)
+				c.EXPECT().DelFirstPage(gomock.Any(), int64(2)).Return(nil)
+				return d, c
+			},
+			uid: 2,
+			id:  1,
+		},
+		{
+			name: "修改状态失败，不删除缓存",
+			mock: func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				d.EXPECT().SyncStatus(gomock.Any(), int64(2), int64(1),
+					domain.ArticleStatusPrivate.ToUint8()).Return(article.ErrPossibleIncorrectAuthor)
+				return d, c
+			},
+			uid:     2,
+			id:      1,
+			wantErr: article.ErrPossibleIncorrectAuthor,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			d, c := tc.mock(ctrl)
+			repo := NewArticleRepository(d, nil, c, nil)
+			err := repo.SyncStatus(context.Background(), tc.uid, tc.id, domain.ArticleStatusPrivate)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestCachedArticleRepository_SyncStatusByAuthor(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache)
+
+		uid int64
+
+		wantErr error
+	}{
+		{
+			name: "删除全部线上文章的缓存",
+			mock: func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				d.EXPECT().SyncStatusByAuthor(gomock.Any(), int64(2),
+					domain.ArticleStatusPrivate.ToUint8()).Return([]int64{1, 3}, nil)
+				c.EXPECT().DelPub(gomock.Any(), int64(1), int64(3)).Return(nil)
+				c.EXPECT().DelFirstPage(gomock.Any(), int64(2)).Return(nil)
+				return d, c
+			},
+			uid: 2,
+		},
+		{
+			name: "删除缓存失败",
+			mock: func(ctrl *gomock.Controller) (article.ArticleDAO, cache.ArticleCache) {
+				d := daomocks.NewMockArticleDAO(ctrl)
+				c := cachemocks.NewMockArticleCache(ctrl)
+				d.EXPECT().SyncStatusByAuthor(gomock.Any(), int64(2),
+					domain.ArticleStatusPrivate.ToUint8()).Return([]int64{1}, nil)
+				c.EXPECT().DelPub(gomock.Any(), int64(1)).Return(errors.New("mock redis error"))
+				return d, c
+			},
+			uid:     2,
+			wantErr: errors.New("mock redis error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			d, c := tc.mock(ctrl)
+			repo := NewArticleRepository(d, nil, c, nil)
+			err := repo.SyncStatusByAuthor(context.Background(), tc.uid, domain.ArticleStatusPrivate)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+//go:generate mockgen -source=./async_sms.go -package=repomocks -destination=mocks/async_sms.mock.go AsyncSMSRepository
+type AsyncSMSRepository interface {
+	Add(ctx context.Context, s domain.AsyncSMS) error
+	// FindDue 找到已经到了重试时间的短信
+	FindDue(ctx context.Context, limit int) ([]domain.AsyncSMS, error)
+	ReportSuccess(ctx context.Context, id int64, retryCnt int) error
+	// ReportRetry 这一次重试失败了，nextTime 的时候再试
+	ReportRetry(ctx context.Context, id int64, retryCnt int, nextTime time.Time, lastErr string) error
+	// ReportFailed 重试次数用完了，不会再重试了
+	ReportFailed(ctx context.Context, id int64, retryCnt int, lastErr string) error
+}
+
+type asyncSMSRepository struct {
+	dao dao.AsyncSMSDAO
+	// 模板参数里面可能有验证码，用 AES-GCM 加密之后再存
+	aead cipher.AEAD
+}
+
+// NewAsyncSMSRepository key 用来加密模板参数，任意长度，换了 key 之后以前存下来的短信就解密不了了
+func NewAsyncSMSRepository(dao dao.AsyncSMSDAO, key []byte) AsyncSMSRepository {
+	sum := sha256.Sum256(key)
+	// 32 字节的 key 一定能创建成功
+	block, _ := aes.NewCipher(sum[:])
+	aead, _ := cipher.NewGCM(block)
+	return &asyncSMSRepository{dao: dao, aead: aead}
+}
+
+func (repo *asyncSMSRepository) Add(ctx context.Context, s domain.AsyncSMS) error {
+	args, err := repo.encryptArgs(s.Args)
+	if err != nil {
+		return err
+	}
+	numbers, err := json.Marshal(s.Numbers)
+	if err != nil {
+		return err
+	}
+	return repo.dao.Insert(ctx, dao.AsyncSMS{
+		TplId:    s.TplId,
+		Args:     args,
+		Numbers:  string(numbers),
+		RetryCnt: s.RetryCnt,
+		Status:   domain.AsyncSMSStatusWaiting.ToUint8(),
+		NextTime: s.NextTime.UnixMilli(),
+		Deadline: repo.toMilli(s.Deadline),
+		LastErr:  s.LastErr,
+	})
+}
+
+func (repo *asyncSMSRepository) FindDue(ctx context.Context, limit int) ([]domain.AsyncSMS, error) {
+	res, err := repo.dao.FindDue(ctx, time.Now().UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]domain.AsyncSMS, 0, len(res))
+	for _, src := range res {
+		msg, err := repo.toDomain(src)
+		if err != nil {
+			// 换了 key 或者数据被改过，重试不了，直接标记为失败，免得每次都被找出来
+			err = repo.ReportFailed(ctx, src.Id, src.RetryCnt, "解密模板参数失败")
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (repo *asyncSMSRepository) ReportSuccess(ctx context.Context, id int64, retryCnt int) error {
+	return repo.dao.Update(ctx, id, domain.AsyncSMSStatusSuccess.ToUint8(), retryCnt, 0, "")
+}
+
+func (repo *asyncSMSRepository) ReportRetry(ctx context.Context, id int64, retryCnt int,
+	nextTime time.Time, lastErr string) error {
+	return repo.dao.Update(ctx, id, domain.AsyncSMSStatusWaiting.ToUint8(),
+		retryCnt, nextTime.UnixMilli(), lastErr)
+}
+
+func (repo *asyncSMSRepository) ReportFailed(ctx context.Context, id int64, retryCnt int, lastErr string) error {
+	return repo.dao.Update(ctx, id, domain.AsyncSMSStatusFailed.ToUint8(), retryCnt, 0, lastErr)
+}
+
+func (repo *asyncSMSRepository) encryptArgs(args []string) (string, error) {
+	plain, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, repo.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	// 存的是 nonce 加上密文
+	return base64.StdEncoding.EncodeToString(repo.aead.Seal(nonce, nonce, plain, nil)), nil
+}
+
+func (repo *asyncSMSRepository) decryptArgs(val string) ([]string, error) {
+	data, err := base64.StdEncoding.DecodeString(val)
+	if err != nil {
+		return nil, err
+	}
+	size := repo.aead.NonceSize()
+	if len(data) < size {
+		return nil, errors.New("模板参数的密文太短")
+	}
+	plain, err := repo.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, err
+	}
+	var args []string
+	err = json.Unmarshal(plain, &args)
+	return args, err
+}
+
+func (repo *asyncSMSRepository) toMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func (repo *asyncSMSRepository) toDomain(s dao.AsyncSMS) (domain.AsyncSMS, error) {
+	args, err := repo.decryptArgs(s.Args)
+	if err != nil {
+		return domain.AsyncSMS{}, err
+	}
+	var numbers []string
+	// 号码是自己写进去的，不会解析失败
+	_ = json.Unmarshal([]byte(s.Numbers), &numbers)
+	var deadline time.Time
+	if s.Deadline > 0 {
+		deadline = time.UnixMilli(s.Deadline)
+	}
+	return domain.AsyncSMS{
+		Id:       s.Id,
+		TplId:    s.TplId,
+		Args:     args,
+		Numbers:  numbers,
+		RetryCnt: s.RetryCnt,
+		Status:   domain.AsyncSMSStatus(s.Status),
+		NextTime: time.UnixMilli(s.NextTime),
+		Deadline: deadline,
+		LastErr:  s.LastErr,
+		Ctime:    time.UnixMilli(s.Ctime),
+		Utime:    time.UnixMilli(s.Utime),
+	}, nil
+}
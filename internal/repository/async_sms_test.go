@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+	daomocks "webook/internal/repository/dao/mocks"
+)
+
+func TestAsyncSMSRepository_Args(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	d := daomocks.NewMockAsyncSMSDAO(ctrl)
+	repo := NewAsyncSMSRepository(d, []byte("test-key"))
+
+	deadline := time.UnixMilli(time.Now().Add(time.Minute).UnixMilli())
+	var stored dao.AsyncSMS
+	d.EXPECT().Insert(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, s dao.AsyncSMS) error {
+			stored = s
+			return nil
+		})
+	err := repo.Add(context.Background(), domain.AsyncSMS{
+		TplId:    "login_code",
+		Args:     []string{"123456"},
+		Numbers:  []string{"+8615212345678"},
+		NextTime: time.Now(),
+		Deadline: deadline,
+	})
+	require.NoError(t, err)
+	// 数据库里面看不到验证码
+	assert.False(t, strings.Contains(stored.Args, "123456"))
+	assert.Equal(t, deadline.UnixMilli(), stored.Deadline)
+
+	stored.Id = 1
+	broken := dao.AsyncSMS{Id: 2, Args: "bm90LWVuY3J5cHRlZA==", Numbers: `["+8615212345678"]`, RetryCnt: 1}
+	d.EXPECT().FindDue(gomock.Any(), gomock.Any(), 10).Return([]dao.AsyncSMS{stored, broken}, nil)
+	// 解密不了的直接标记为失败
+	d.EXPECT().Update(gomock.Any(), int64(2), domain.AsyncSMSStatusFailed.ToUint8(), 1, int64(0), gomock.Any()).
+		Return(nil)
+	msgs, err := repo.FindDue(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, []string{"123456"}, msgs[0].Args)
+	assert.Equal(t, []string{"+8615212345678"}, msgs[0].Numbers)
+	assert.Equal(t, deadline, msgs[0].Deadline)
+
+	// 换了 key 就解密不了
+	other := NewAsyncSMSRepository(d, []byte("other-key"))
+	d.EXPECT().FindDue(gomock.Any(), gomock.Any(), 10).Return([]dao.AsyncSMS{stored}, nil)
+	d.EXPECT().Update(gomock.Any(), int64(1), domain.AsyncSMSStatusFailed.ToUint8(), 0, int64(0), gomock.Any()).
+		Return(nil)
+	msgs, err = other.FindDue(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+}
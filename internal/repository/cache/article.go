@@ -22,6 +22,8 @@ type ArticleCache interface {
 	// SetPub 正常来说，创作者和读者的 Redis 集群要分开，因为读者是一个核心中的核心
 	SetPub(ctx context.Context, article domain.Article) error
 	GetPub(ctx context.Context, id int64) (domain.Article, error)
+	// DelPub 文章下线之后删除读者端的缓存
+	DelPub(ctx context.Context, ids ...int64) error
 }
 
 type RedisArticleCache struct {
@@ -56,6 +58,17 @@ func (r *RedisArticleCache) SetPub(ctx context.Context, art domain.Article) erro
 		time.Minute*30).Err()
 }
 
+func (r *RedisArticleCache) DelPub(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, r.readerArtKey(id))
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
 func (r *RedisArticleCache) Get(ctx context.Context, id int64) (domain.Article, error) {
 	// 可以直接使用 Bytes 方法来获得 []byte
 	data, err := r.client.Get(ctx, r.authorArtKey(id)).Bytes()
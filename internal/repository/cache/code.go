@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
+	"time"
 )
 
 var (
@@ -18,31 +19,39 @@ var (
 	ErrCodeVerifyTooManyTimes = errors.New("验证次数太多")
 )
 
+// CodeSetting 验证码的有效期、重新发送的最短间隔和最多验证次数
+type CodeSetting struct {
+	TTL         time.Duration
+	Interval    time.Duration
+	MaxAttempts int
+}
+
 // CodeCache 是用于存储和验证验证码的接口
 type CodeCache interface {
 
 	// Set 用于存储验证码到缓存
-	// 该方法将验证码与手机号和业务标识 (biz) 关联，并设置缓存过期时间
+	// 该方法将验证码与接收的目标和业务标识 (biz) 关联，并设置缓存过期时间
 	// 参数:
 	//   - ctx: 上下文，用于控制请求的生命周期
 	//   - biz: 业务标识，用于区分不同的验证码用途 (例如，登录、注册等)
-	//   - phone: 用户的手机号码，用于唯一标识验证码的存储
-	//   - code: 要存储的验证码
+	//   - target: 接收验证码的目标，例如 sms:手机号，用于唯一标识验证码的存储
+	//   - code: 要存储的验证码，调用者传入的是哈希值
+	//   - setting: 有效期、重新发送的间隔和最多验证次数
 	// 返回:
 	//   - error: 如果出现错误，返回错误信息；否则返回 nil
-	Set(ctx context.Context, biz string, phone string, code string) error
+	Set(ctx context.Context, biz string, target string, code string, setting CodeSetting) error
 
 	// Verify 用于验证输入的验证码是否与存储的验证码匹配
 	// 如果验证码有效且未过期，返回 true，否则返回 false
 	// 参数:
 	//   - ctx: 上下文，用于控制请求的生命周期
 	//   - biz: 业务标识，用于查找相应的验证码
-	//   - phone: 用户的手机号码，用于定位验证码
-	//   - inputCode: 用户输入的验证码，用于与缓存中的验证码进行对比
+	//   - target: 接收验证码的目标，用于定位验证码
+	//   - inputCode: 用户输入的验证码，和 Set 的时候一样传入哈希值
 	// 返回:
 	//   - bool: 如果验证码正确且有效，返回 true；否则返回 false
 	//   - error: 如果发生错误，返回错误信息；如果没有错误发生，则返回 nil
-	Verify(ctx context.Context, biz string, phone string, inputCode string) (bool, error)
+	Verify(ctx context.Context, biz string, target string, inputCode string) (bool, error)
 }
 
 // RedisCodeCache 实现 CodeCache 接口
@@ -58,14 +67,15 @@ func NewRedisCodeCache(cmd redis.Cmdable) CodeCache {
 
 // Set 设置验证码
 // 该方法使用 Redis 执行 Lua 脚本来设置验证码，并根据不同情况做出相应处理：
-// - 如果该手机号码在该业务场景下没有验证码，或者验证码已经过期，则发送新验证码。
-// - 如果已发送验证码且超过一分钟，允许重新发送验证码。
-// - 如果验证码没有过期且不到一分钟，拒绝发送验证码。
-// - 验证码有效期为 10 分钟。
-func (c *RedisCodeCache) Set(ctx context.Context, biz string, phone string, code string) error {
+// - 如果该目标在该业务场景下没有验证码，或者验证码已经过期，则发送新验证码。
+// - 如果已发送验证码且超过 setting.Interval，允许重新发送验证码。
+// - 如果验证码没有过期且不到 setting.Interval，拒绝发送验证码。
+// - 验证码有效期为 setting.TTL。
+func (c *RedisCodeCache) Set(ctx context.Context, biz string, target string, code string, setting CodeSetting) error {
 	// 使用 Redis 执行 Lua 脚本，设置验证码
-	// `luaSetCode` 是设置验证码的 Lua 脚本字符串，`c.key(biz, phone)` 是生成存储验证码的 Redis 键，`code` 是验证码值
-	res, err := c.redis.Eval(ctx, luaSetCode, []string{c.key(biz, phone)}, code).Int()
+	// `luaSetCode` 是设置验证码的 Lua 脚本字符串，`c.key(biz, target)` 是生成存储验证码的 Redis 键，`code` 是验证码值
+	res, err := c.redis.Eval(ctx, luaSetCode, []string{c.key(biz, target)}, code,
+		int64(setting.TTL/time.Second), int64(setting.Interval/time.Second), setting.MaxAttempts).Int()
 	if err != nil {
 		// 如果执行 Redis 命令时出错，返回错误
 		return err
@@ -90,14 +100,14 @@ func (c *RedisCodeCache) Set(ctx context.Context, biz string, phone string, code
 // Verify 验证用户输入的验证码
 // 该方法使用 Redis 执行 Lua 脚本来验证验证码，避免了多个 Redis 操作的性能问题。
 // - biz：业务标识，用于区分不同业务场景的验证码。
-// - phone：用户的手机号码，用于唯一标识验证码。
+// - target：接收验证码的目标，用于唯一标识验证码。
 // - inputCode：用户输入的验证码。
-func (c *RedisCodeCache) Verify(ctx context.Context, biz string, phone string, inputCode string) (bool, error) {
+func (c *RedisCodeCache) Verify(ctx context.Context, biz string, target string, inputCode string) (bool, error) {
 	// 使用 Redis 执行 Lua 脚本验证验证码
 	// `luaVerifyCode` 是一个 Lua 脚本字符串，负责验证验证码的有效性。
-	// `c.key(biz, phone)` 是生成存储验证码的 Redis 键。
+	// `c.key(biz, target)` 是生成存储验证码的 Redis 键。
 	// `inputCode` 是用户输入的验证码。
-	res, err := c.redis.Eval(ctx, luaVerifyCode, []string{c.key(biz, phone)}, inputCode).Int()
+	res, err := c.redis.Eval(ctx, luaVerifyCode, []string{c.key(biz, target)}, inputCode).Int()
 	if err != nil {
 		// 如果执行 Redis 命令时出错，返回错误
 		return false, err
@@ -119,6 +129,6 @@ func (c *RedisCodeCache) Verify(ctx context.Context, biz string, phone string, i
 	}
 }
 
-func (c *RedisCodeCache) key(biz string, phone string) string {
-	return fmt.Sprintf("phone_code:%s:%s", biz, phone)
+func (c *RedisCodeCache) key(biz string, target string) string {
+	return fmt.Sprintf("code:%s:%s", biz, target)
 }
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+//go:embed lua/code_limit.lua
+var luaCodeLimit string
+
+// CodeLimitCounter 一个发送次数的计数器，例如某个手机号一天之内的发送次数
+type CodeLimitCounter struct {
+	Key    string
+	Window time.Duration
+	Limit  int64
+}
+
+// CodeLimitCache 验证码发送次数的计数器
+type CodeLimitCache interface {
+	// Counts 计数器当前的值，不存在或者已经过期的是 0
+	Counts(ctx context.Context, keys []string) ([]int64, error)
+	// Incr 所有的计数器都没有达到上限的时候，全部加一，返回是否成功
+	Incr(ctx context.Context, counters []CodeLimitCounter) (bool, error)
+}
+
+type RedisCodeLimitCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisCodeLimitCache(cmd redis.Cmdable) CodeLimitCache {
+	return &RedisCodeLimitCache{cmd: cmd}
+}
+
+func (c *RedisCodeLimitCache) Counts(ctx context.Context, keys []string) ([]int64, error) {
+	redisKeys := make([]string, 0, len(keys))
+	for _, k := range keys {
+		redisKeys = append(redisKeys, c.key(k))
+	}
+	vals, err := c.cmd.MGet(ctx, redisKeys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, 0, len(vals))
+	for _, val := range vals {
+		var cnt int64
+		if str, ok := val.(string); ok {
+			_, err = fmt.Sscan(str, &cnt)
+			if err != nil {
+				return nil, err
+			}
+		}
+		res = append(res, cnt)
+	}
+	return res, nil
+}
+
+func (c *RedisCodeLimitCache) Incr(ctx context.Context, counters []CodeLimitCounter) (bool, error) {
+	keys := make([]string, 0, len(counters))
+	args := make([]any, len(counters)*2)
+	for i, cnt := range counters {
+		keys = append(keys, c.key(cnt.Key))
+		args[i] = int64(cnt.Window.Seconds())
+		args[len(counters)+i] = cnt.Limit
+	}
+	res, err := c.cmd.Eval(ctx, luaCodeLimit, keys, args...).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 0, nil
+}
+
+func (c *RedisCodeLimitCache) key(k string) string {
+	return fmt.Sprintf("code_limit:%s", k)
+}
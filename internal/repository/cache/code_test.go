@@ -10,6 +10,9 @@ import (
 	"webook/internal/repository/cache/redismocks"
 )
 
+// 和以前固定的一样：有效期十分钟，一分钟之后才能重新发送，最多验证三次
+var testCodeSetting = CodeSetting{TTL: time.Minute * 10, Interval: time.Minute, MaxAttempts: 3}
+
 func TestRedisCodeCache_Set(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -76,7 +79,7 @@ func TestRedisCodeCache_Set(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			c := NewRedisCodeCache(tc.mock(ctrl))
-			err := c.Set(tc.ctx, tc.biz, tc.phone, tc.code)
+			err := c.Set(tc.ctx, tc.biz, tc.phone, tc.code, testCodeSetting)
 			assert.Equal(t, tc.wantErr, err)
 		})
 	}
@@ -201,7 +204,7 @@ func TestRedisCodeCache_Set_e2e(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.before(t)
-			err := c.Set(tc.ctx, tc.biz, tc.phone, tc.code)
+			err := c.Set(tc.ctx, tc.biz, tc.phone, tc.code, testCodeSetting)
 			assert.Equal(t, tc.wantErr, err)
 			tc.after(t)
 		})
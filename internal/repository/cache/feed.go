@@ -0,0 +1,215 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+	"strings"
+	"webook/internal/domain"
+)
+
+const (
+	// 大 V 作者的集合，这些作者发表文章的时候不推送，读的时候再拉取
+	feedBigAuthorsKey = "feed:big_authors"
+)
+
+type FeedCache interface {
+	// AddToInboxes 把一条记录推送到多个用户的收件箱，并且裁剪收件箱
+	AddToInboxes(ctx context.Context, uids []int64, item domain.FeedItem) error
+	// AddToOutbox 把一条记录放到作者的发件箱，并且裁剪发件箱
+	AddToOutbox(ctx context.Context, item domain.FeedItem) error
+	// Inbox 查询收件箱中排在游标 before 后面的记录，按照时间倒序
+	Inbox(ctx context.Context, uid int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error)
+	// Outbox 查询发件箱中排在游标 before 后面的记录，按照时间倒序
+	Outbox(ctx context.Context, author int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error)
+	// SetBigAuthor 标记或者取消标记大 V 作者
+	SetBigAuthor(ctx context.Context, author int64, big bool) error
+	// BigAuthors 从 authors 中筛选出大 V 作者
+	BigAuthors(ctx context.Context, authors []int64) ([]int64, error)
+}
+
+type RedisFeedCache struct {
+	client redis.Cmdable
+	// 收件箱和发件箱最多保留多少条记录
+	boxSize int64
+}
+
+func NewRedisFeedCache(client redis.Cmdable) FeedCache {
+	return &RedisFeedCache{
+		client:  client,
+		boxSize: 1000,
+	}
+}
+
+func (r *RedisFeedCache) AddToInboxes(ctx context.Context, uids []int64, item domain.FeedItem) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	pipe := r.client.Pipeline()
+	for _, uid := range uids {
+		r.add(ctx, pipe, r.inboxKey(uid), item)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisFeedCache) AddToOutbox(ctx context.Context, item domain.FeedItem) error {
+	pipe := r.client.Pipeline()
+	r.add(ctx, pipe, r.outboxKey(item.AuthorId), item)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// add 重新发表的文章 member 不变，只会更新 score，也就是排到最前面
+func (r *RedisFeedCache) add(ctx context.Context, pipe redis.Pipeliner, key string, item domain.FeedItem) {
+	pipe.ZAdd(ctx, key, redis.Z{
+		Score:  float64(item.Ctime),
+		Member: r.member(item),
+	})
+	// 只保留最新的 boxSize 条
+	pipe.ZRemRangeByRank(ctx, key, 0, -r.boxSize-1)
+}
+
+func (r *RedisFeedCache) Inbox(ctx context.Context, uid int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error) {
+	return r.list(ctx, r.inboxKey(uid), before, limit)
+}
+
+func (r *RedisFeedCache) Outbox(ctx context.Context, author int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error) {
+	return r.list(ctx, r.outboxKey(author), before, limit)
+}
+
+func (r *RedisFeedCache) list(ctx context.Context, key string, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error) {
+	res := make([]domain.FeedItem, 0, limit)
+	if before.Aid > 0 {
+		// 和游标同一毫秒发表的，只要文章 ID 比游标小的
+		items, err := r.bucket(ctx, key, before.Ctime)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.Aid < before.Aid {
+				res = append(res, item)
+			}
+		}
+	}
+	zs, err := r.client.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		// ( 表示不包含 before 本身
+		Max:   fmt.Sprintf("(%d", before.Ctime),
+		Min:   "-inf",
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	items := r.parseZs(zs)
+	// 同一毫秒的 member 在 Redis 里面是按照字符串排序的，被 Count 截断的时候，
+	// 最后一毫秒取到的不一定是文章 ID 最大的那几条，所以最后一毫秒要全部取出来重新排
+	if limit > 0 && int64(len(items)) == limit {
+		last := items[len(items)-1].Ctime
+		tail, err := r.bucket(ctx, key, last)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.Ctime != last {
+				res = append(res, item)
+			}
+		}
+		res = append(res, tail...)
+	} else {
+		res = append(res, items...)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Newer(res[j])
+	})
+	if int64(len(res)) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// bucket 查询同一毫秒发表的全部记录，同一毫秒发表的文章不会很多
+func (r *RedisFeedCache) bucket(ctx context.Context, key string, ctime int64) ([]domain.FeedItem, error) {
+	score := strconv.FormatInt(ctime, 10)
+	zs, err := r.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min: score,
+		Max: score,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	return r.parseZs(zs), nil
+}
+
+func (r *RedisFeedCache) parseZs(zs []redis.Z) []domain.FeedItem {
+	res := make([]domain.FeedItem, 0, len(zs))
+	for _, z := range zs {
+		item, ok := r.parseMember(z.Member)
+		if !ok {
+			continue
+		}
+		item.Ctime = int64(z.Score)
+		res = append(res, item)
+	}
+	return res
+}
+
+func (r *RedisFeedCache) SetBigAuthor(ctx context.Context, author int64, big bool) error {
+	if big {
+		return r.client.SAdd(ctx, feedBigAuthorsKey, author).Err()
+	}
+	return r.client.SRem(ctx, feedBigAuthorsKey, author).Err()
+}
+
+func (r *RedisFeedCache) BigAuthors(ctx context.Context, authors []int64) ([]int64, error) {
+	if len(authors) == 0 {
+		return nil, nil
+	}
+	members := make([]any, 0, len(authors))
+	for _, a := range authors {
+		members = append(members, a)
+	}
+	exists, err := r.client.SMIsMember(ctx, feedBigAuthorsKey, members...).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, 0, 8)
+	for i, ok := range exists {
+		if ok {
+			res = append(res, authors[i])
+		}
+	}
+	return res, nil
+}
+
+// member 的格式是 aid:authorId
+func (r *RedisFeedCache) member(item domain.FeedItem) string {
+	return fmt.Sprintf("%d:%d", item.Aid, item.AuthorId)
+}
+
+func (r *RedisFeedCache) parseMember(m any) (domain.FeedItem, bool) {
+	str, ok := m.(string)
+	if !ok {
+		return domain.FeedItem{}, false
+	}
+	segs := strings.Split(str, ":")
+	if len(segs) != 2 {
+		return domain.FeedItem{}, false
+	}
+	aid, err1 := strconv.ParseInt(segs[0], 10, 64)
+	author, err2 := strconv.ParseInt(segs[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		return domain.FeedItem{}, false
+	}
+	return domain.FeedItem{Aid: aid, AuthorId: author}, true
+}
+
+func (r *RedisFeedCache) inboxKey(uid int64) string {
+	return fmt.Sprintf("feed:inbox:%d", uid)
+}
+
+func (r *RedisFeedCache) outboxKey(author int64) string {
+	return fmt.Sprintf("feed:outbox:%d", author)
+}
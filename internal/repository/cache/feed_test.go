@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"math"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository/cache/redismocks"
+)
+
+func TestRedisFeedCache_Inbox(t *testing.T) {
+	const key = "feed:inbox:1"
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		before domain.FeedCursor
+		limit  int64
+
+		wantItems []domain.FeedItem
+		wantErr   error
+	}{
+		{
+			name: "上一页停在同一毫秒中间",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				// 同一毫秒的按照 member 的字符串排序
+				cmd.EXPECT().ZRangeByScoreWithScores(gomock.Any(), key, &redis.ZRangeBy{Min: "500", Max: "500"}).
+					Return(redis.NewZSliceCmdResult([]redis.Z{
+						{Score: 500, Member: "10:2"},
+						{Score: 500, Member: "11:2"},
+						{Score: 500, Member: "12:2"},
+						{Score: 500, Member: "9:2"},
+					}, nil))
+				cmd.EXPECT().ZRevRangeByScoreWithScores(gomock.Any(), key,
+					&redis.ZRangeBy{Max: "(500", Min: "-inf", Count: 3}).
+					Return(redis.NewZSliceCmdResult([]redis.Z{
+						{Score: 400, Member: "7:3"},
+					}, nil))
+				return cmd
+			},
+			before: domain.FeedCursor{Ctime: 500, Aid: 11},
+			limit:  3,
+			wantItems: []domain.FeedItem{
+				{Aid: 10, AuthorId: 2, Ctime: 500},
+				{Aid: 9, AuthorId: 2, Ctime: 500},
+				{Aid: 7, AuthorId: 3, Ctime: 400},
+			},
+		},
+		{
+			name: "最后一毫秒被截断",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				cmd.EXPECT().ZRevRangeByScoreWithScores(gomock.Any(), key,
+					&redis.ZRangeBy{Max: fmt.Sprintf("(%d", int64(math.MaxInt64)), Min: "-inf", Count: 2}).
+					Return(redis.NewZSliceCmdResult([]redis.Z{
+						{Score: 500, Member: "9:2"},
+						{Score: 500, Member: "11:2"},
+					}, nil))
+				cmd.EXPECT().ZRangeByScoreWithScores(gomock.Any(), key, &redis.ZRangeBy{Min: "500", Max: "500"}).
+					Return(redis.NewZSliceCmdResult([]redis.Z{
+						{Score: 500, Member: "10:2"},
+						{Score: 500, Member: "11:2"},
+						{Score: 500, Member: "9:2"},
+					}, nil))
+				return cmd
+			},
+			before: domain.FeedCursor{Ctime: math.MaxInt64},
+			limit:  2,
+			wantItems: []domain.FeedItem{
+				{Aid: 11, AuthorId: 2, Ctime: 500},
+				{Aid: 10, AuthorId: 2, Ctime: 500},
+			},
+		},
+		{
+			name: "查询失败",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				cmd.EXPECT().ZRevRangeByScoreWithScores(gomock.Any(), key, gomock.Any()).
+					Return(redis.NewZSliceCmdResult(nil, errors.New("mock error")))
+				return cmd
+			},
+			before:  domain.FeedCursor{Ctime: 500},
+			limit:   3,
+			wantErr: errors.New("mock error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c := NewRedisFeedCache(tc.mock(ctrl))
+			items, err := c.Inbox(context.Background(), 1, tc.before, tc.limit)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantItems, items)
+		})
+	}
+}
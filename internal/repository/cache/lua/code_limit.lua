@@ -0,0 +1,20 @@
+-- 验证码发送次数的计数器，每个计数器都是一个固定窗口
+-- KEYS 是所有的计数器，ARGV 前一半是每个计数器的窗口大小（秒），后一半是每个计数器的上限
+local n = #KEYS
+
+-- 先检查，任何一个计数器达到上限都不允许发送，也不增加计数
+for i = 1, n do
+    local cnt = tonumber(redis.call("get", KEYS[i]) or "0")
+    if cnt >= tonumber(ARGV[n + i]) then
+        return -1
+    end
+end
+
+for i = 1, n do
+    local cnt = redis.call("incr", KEYS[i])
+    if cnt == 1 then
+        -- 窗口从第一次发送开始计算
+        redis.call("expire", KEYS[i], ARGV[i])
+    end
+end
+return 0
@@ -0,0 +1,16 @@
+-- 扫码登录的票据
+local key = KEYS[1]
+-- 已经确认的状态
+local confirmed = ARGV[1]
+
+local status = redis.call("hget", key, "status")
+if status == false then
+    return -1
+end
+if status ~= confirmed then
+    return -2
+end
+-- 确认之后只能换取一次登录态
+local uid = redis.call("hget", key, "uid")
+redis.call("del", key)
+return tonumber(uid)
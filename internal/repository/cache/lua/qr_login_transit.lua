@@ -0,0 +1,27 @@
+-- 扫码登录的票据
+local key = KEYS[1]
+-- 当前应该处于的状态
+local from = ARGV[1]
+-- 要变成的状态
+local to = ARGV[2]
+-- 操作的用户
+local uid = ARGV[3]
+-- bind 表示扫码，记录扫码的用户；否则要求操作的用户就是扫码的用户
+local bind = ARGV[4]
+
+local status = redis.call("hget", key, "status")
+if status == false then
+    -- 票据不存在或者已经过期了
+    return -1
+end
+if status ~= from then
+    return -2
+end
+if bind == "1" then
+    redis.call("hset", key, "uid", uid)
+elseif redis.call("hget", key, "uid") ~= uid then
+    -- 不是扫码的那个用户
+    return -2
+end
+redis.call("hset", key, "status", to)
+return 0
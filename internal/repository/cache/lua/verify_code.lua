@@ -5,7 +5,7 @@ local key = KEYS[1]
 -- 验证次数的键名是原始键名 + ":cnt"
 local cntKey = key .. ":cnt"
 
--- 获取预期中的验证码（用户输入的验证码）
+-- 获取预期中的验证码（用户输入的验证码的哈希值）
 local expectedCode = ARGV[1]
 
 -- 从 Redis 获取当前验证码验证次数
@@ -13,6 +13,11 @@ local cnt = tonumber(redis.call("get", cntKey))
 -- 从 Redis 获取存储的验证码
 local code = redis.call("get", key)
 
+-- 验证码不存在或者已经过期，当成验证码错误处理
+if cnt == nil then
+    return -2
+end
+
 -- 验证次数已经耗尽，返回 -1 表示验证次数已用完
 if cnt <= 0 then
     return -1
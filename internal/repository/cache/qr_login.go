@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+)
+
+var (
+	//go:embed lua/qr_login_transit.lua
+	luaQRLoginTransit string
+	//go:embed lua/qr_login_consume.lua
+	luaQRLoginConsume string
+
+	// ErrQRLoginStatusMismatch 票据的状态不对，或者操作的不是扫码的用户
+	ErrQRLoginStatusMismatch = errors.New("扫码登录的状态不对")
+)
+
+// QRLoginCache 扫码登录的票据，只在 Redis 里面，过期了就没了
+type QRLoginCache interface {
+	Set(ctx context.Context, t domain.QRLoginTicket, expiration time.Duration) error
+	// Get 票据不存在的时候返回 ErrKeyNotExist
+	Get(ctx context.Context, ticket string) (domain.QRLoginTicket, error)
+	// Transit 修改票据的状态，要求票据当前处于 from 状态
+	// bind 为 true 的时候记录 uid 为扫码的用户，否则要求 uid 就是扫码的用户
+	Transit(ctx context.Context, ticket string, from, to domain.QRLoginStatus, uid int64, bind bool) error
+	// Consume 使用已经确认的票据，返回扫码的用户，票据随之删除
+	Consume(ctx context.Context, ticket string) (int64, error)
+}
+
+type RedisQRLoginCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisQRLoginCache(cmd redis.Cmdable) QRLoginCache {
+	return &RedisQRLoginCache{cmd: cmd}
+}
+
+func (c *RedisQRLoginCache) Set(ctx context.Context, t domain.QRLoginTicket, expiration time.Duration) error {
+	key := c.key(t.Ticket)
+	pipe := c.cmd.TxPipeline()
+	pipe.HSet(ctx, key,
+		"secret", t.Secret,
+		"status", t.Status.ToUint8(),
+		"uid", t.Uid,
+		"ua", t.UserAgent,
+		"ip", t.IP)
+	pipe.Expire(ctx, key, expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *RedisQRLoginCache) Get(ctx context.Context, ticket string) (domain.QRLoginTicket, error) {
+	vals, err := c.cmd.HGetAll(ctx, c.key(ticket)).Result()
+	if err != nil {
+		return domain.QRLoginTicket{}, err
+	}
+	if len(vals) == 0 {
+		return domain.QRLoginTicket{}, ErrKeyNotExist
+	}
+	status, _ := strconv.ParseUint(vals["status"], 10, 8)
+	uid, _ := strconv.ParseInt(vals["uid"], 10, 64)
+	return domain.QRLoginTicket{
+		Ticket:    ticket,
+		Secret:    vals["secret"],
+		Status:    domain.QRLoginStatus(status),
+		Uid:       uid,
+		UserAgent: vals["ua"],
+		IP:        vals["ip"],
+	}, nil
+}
+
+func (c *RedisQRLoginCache) Transit(ctx context.Context, ticket string,
+	from, to domain.QRLoginStatus, uid int64, bind bool) error {
+	bindFlag := "0"
+	if bind {
+		bindFlag = "1"
+	}
+	res, err := c.cmd.Eval(ctx, luaQRLoginTransit, []string{c.key(ticket)},
+		from.ToUint8(), to.ToUint8(), uid, bindFlag).Int()
+	if err != nil {
+		return err
+	}
+	switch res {
+	case 0:
+		return nil
+	case -1:
+		return ErrKeyNotExist
+	default:
+		return ErrQRLoginStatusMismatch
+	}
+}
+
+func (c *RedisQRLoginCache) Consume(ctx context.Context, ticket string) (int64, error) {
+	res, err := c.cmd.Eval(ctx, luaQRLoginConsume, []string{c.key(ticket)},
+		domain.QRLoginStatusConfirmed.ToUint8()).Int64()
+	if err != nil {
+		return 0, err
+	}
+	switch res {
+	case -1:
+		return 0, ErrKeyNotExist
+	case -2:
+		return 0, ErrQRLoginStatusMismatch
+	default:
+		return res, nil
+	}
+}
+
+func (c *RedisQRLoginCache) key(ticket string) string {
+	return fmt.Sprintf("qr_login:%s", ticket)
+}
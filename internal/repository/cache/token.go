@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// TokenCache 用于存储一次性的令牌，例如修改邮箱时发送的确认链接
+// 令牌只能被使用一次，取出来之后就会被删除
+type TokenCache interface {
+	// Set 存储令牌以及令牌关联的数据
+	// biz 用于区分不同的业务场景，expiration 是令牌的有效期
+	Set(ctx context.Context, biz string, token string, val string, expiration time.Duration) error
+	// GetDel 取出令牌关联的数据，并且删除令牌
+	// 令牌不存在或者已经过期的时候返回 ErrKeyNotExist
+	GetDel(ctx context.Context, biz string, token string) (string, error)
+}
+
+// RedisTokenCache 实现 TokenCache 接口
+type RedisTokenCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisTokenCache(cmd redis.Cmdable) TokenCache {
+	return &RedisTokenCache{
+		cmd: cmd,
+	}
+}
+
+func (c *RedisTokenCache) Set(ctx context.Context, biz string, token string, val string, expiration time.Duration) error {
+	return c.cmd.Set(ctx, c.key(biz, token), val, expiration).Err()
+}
+
+func (c *RedisTokenCache) GetDel(ctx context.Context, biz string, token string) (string, error) {
+	// GETDEL 是原子操作，保证并发的情况下令牌也只能被使用一次
+	return c.cmd.GetDel(ctx, c.key(biz, token)).Result()
+}
+
+func (c *RedisTokenCache) key(biz string, token string) string {
+	return fmt.Sprintf("token:%s:%s", biz, token)
+}
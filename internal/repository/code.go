@@ -10,6 +10,9 @@ var (
 	ErrCodeSendTooMany        = cache.ErrCodeSendTooMany
 )
 
+// CodeSetting 验证码的有效期、重新发送的最短间隔和最多验证次数
+type CodeSetting = cache.CodeSetting
+
 // CodeRepository 是用于操作验证码数据的接口，主要包括验证码的存储和验证
 // 该接口用于与验证码缓存系统进行交互，通常与 Redis 等缓存系统集成
 // 主要有两个功能：
@@ -21,22 +24,23 @@ type CodeRepository interface {
 	// 参数:
 	//   - ctx: 上下文，用于控制请求的生命周期，便于实现超时或取消操作
 	//   - biz: 业务场景标识，用于区分不同的验证码场景（例如注册、登录等）
-	//   - phone: 用户的手机号码，验证码是与手机号码绑定的
-	//   - code: 要存储的验证码
+	//   - target: 接收验证码的目标，由渠道和手机号、邮箱组成，验证码是与目标绑定的
+	//   - code: 要存储的验证码，只存哈希值，不存明文
+	//   - setting: 有效期、重新发送的间隔和最多验证次数
 	// 返回:
 	//   - error: 如果存储成功，返回 nil；如果存储失败，返回相应的错误信息
-	Store(ctx context.Context, biz string, phone string, code string) error
+	Store(ctx context.Context, biz string, target string, code string, setting CodeSetting) error
 
 	// Verify 验证用户输入的验证码
 	// 参数:
 	//   - ctx: 上下文，用于控制请求的生命周期
 	//   - biz: 业务场景标识，用于区分不同的验证码场景
-	//   - phone: 用户的手机号码
-	//   - inputCode: 用户输入的验证码
+	//   - target: 接收验证码的目标
+	//   - inputCode: 用户输入的验证码的哈希值
 	// 返回:
 	//   - bool: 如果验证码验证成功，返回 true；否则返回 false
 	//   - error: 验证过程中发生的错误。如果发生错误，返回相应的错误信息
-	Verify(ctx context.Context, biz string, phone string, inputCode string) (bool, error)
+	Verify(ctx context.Context, biz string, target string, inputCode string) (bool, error)
 }
 
 // CachedCodeRepository 实现 CodeRepository 接口
@@ -50,13 +54,13 @@ func NewCachedCodeRepository(c cache.CodeCache) CodeRepository {
 	}
 }
 
-func (repo *CachedCodeRepository) Store(ctx context.Context, biz string, phone string, code string) error {
+func (repo *CachedCodeRepository) Store(ctx context.Context, biz string, target string, code string, setting CodeSetting) error {
 	// 将验证码存储到缓存中
-	err := repo.cache.Set(ctx, biz, phone, code)
+	err := repo.cache.Set(ctx, biz, target, code, setting)
 	return err // 返回存储过程中可能发生的错误
 }
 
-func (repo *CachedCodeRepository) Verify(ctx context.Context, biz string, phone string, inputCode string) (bool, error) {
+func (repo *CachedCodeRepository) Verify(ctx context.Context, biz string, target string, inputCode string) (bool, error) {
 	// 调用缓存中的 Verify 方法来验证验证码
-	return repo.cache.Verify(ctx, biz, phone, inputCode)
+	return repo.cache.Verify(ctx, biz, target, inputCode)
 }
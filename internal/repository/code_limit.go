@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/repository/cache"
+)
+
+type CodeLimitCounter = cache.CodeLimitCounter
+
+// CodeLimitRepository 验证码发送次数的计数器，按照手机号、IP、设备分别计数
+//
+//go:generate mockgen -source=./code_limit.go -package=repomocks -destination=mocks/code_limit.mock.go CodeLimitRepository
+type CodeLimitRepository interface {
+	// Counts 计数器当前的值
+	Counts(ctx context.Context, keys []string) ([]int64, error)
+	// Incr 所有的计数器都没有达到上限的时候，全部加一，返回是否成功
+	Incr(ctx context.Context, counters []CodeLimitCounter) (bool, error)
+}
+
+type CachedCodeLimitRepository struct {
+	cache cache.CodeLimitCache
+}
+
+func NewCachedCodeLimitRepository(c cache.CodeLimitCache) CodeLimitRepository {
+	return &CachedCodeLimitRepository{cache: c}
+}
+
+func (repo *CachedCodeLimitRepository) Counts(ctx context.Context, keys []string) ([]int64, error) {
+	return repo.cache.Counts(ctx, keys)
+}
+
+func (repo *CachedCodeLimitRepository) Incr(ctx context.Context, counters []CodeLimitCounter) (bool, error) {
+	return repo.cache.Incr(ctx, counters)
+}
@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// AccountDAO 注销账号和导出个人数据相关的操作
+type AccountDAO interface {
+	// UpsertDeletion 申请注销，重复申请的时候会刷新冷静期
+	UpsertDeletion(ctx context.Context, d AccountDeletion) error
+	// CancelDeletion 撤销注销申请，只有冷静期中的申请才能撤销
+	CancelDeletion(ctx context.Context, uid int64) error
+	FindDeletion(ctx context.Context, uid int64) (AccountDeletion, error)
+	// FindDueDeletions 找到冷静期已经结束的申请
+	FindDueDeletions(ctx context.Context, now int64, limit int) ([]AccountDeletion, error)
+	UpdateDeletionStatus(ctx context.Context, uid int64, status uint8) error
+
+	InsertExport(ctx context.Context, e DataExport) (int64, error)
+	FindExport(ctx context.Context, id int64) (DataExport, error)
+	FindPendingExports(ctx context.Context, limit int) ([]DataExport, error)
+	UpdateExport(ctx context.Context, id int64, status uint8, path string) error
+}
+
+type GORMAccountDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMAccountDAO(db *gorm.DB) AccountDAO {
+	return &GORMAccountDAO{db: db}
+}
+
+func (dao *GORMAccountDAO) UpsertDeletion(ctx context.Context, d AccountDeletion) error {
+	now := time.Now().UnixMilli()
+	d.Ctime = now
+	d.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"status":     d.Status,
+			"execute_at": d.ExecuteAt,
+			"utime":      now,
+		}),
+	}).Create(&d).Error
+}
+
+func (dao *GORMAccountDAO) CancelDeletion(ctx context.Context, uid int64) error {
+	res := dao.db.WithContext(ctx).Model(&AccountDeletion{}).
+		Where("uid = ? AND status = ?", uid, accountDeletionStatusPending).
+		Updates(map[string]any{
+			"status": accountDeletionStatusCancelled,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrDataNotFound
+	}
+	return nil
+}
+
+func (dao *GORMAccountDAO) FindDeletion(ctx context.Context, uid int64) (AccountDeletion, error) {
+	var d AccountDeletion
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).First(&d).Error
+	return d, err
+}
+
+func (dao *GORMAccountDAO) FindDueDeletions(ctx context.Context, now int64, limit int) ([]AccountDeletion, error) {
+	var res []AccountDeletion
+	err := dao.db.WithContext(ctx).
+		Where("status = ? AND execute_at <= ?", accountDeletionStatusPending, now).
+		Order("execute_at ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMAccountDAO) UpdateDeletionStatus(ctx context.Context, uid int64, status uint8) error {
+	return dao.db.WithContext(ctx).Model(&AccountDeletion{}).
+		Where("uid = ?", uid).
+		Updates(map[string]any{
+			"status": status,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *GORMAccountDAO) InsertExport(ctx context.Context, e DataExport) (int64, error) {
+	now := time.Now().UnixMilli()
+	e.Ctime = now
+	e.Utime = now
+	err := dao.db.WithContext(ctx).Create(&e).Error
+	return e.Id, err
+}
+
+func (dao *GORMAccountDAO) FindExport(ctx context.Context, id int64) (DataExport, error) {
+	var e DataExport
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&e).Error
+	return e, err
+}
+
+func (dao *GORMAccountDAO) FindPendingExports(ctx context.Context, limit int) ([]DataExport, error) {
+	var res []DataExport
+	err := dao.db.WithContext(ctx).
+		Where("status = ?", dataExportStatusPending).
+		Order("id ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMAccountDAO) UpdateExport(ctx context.Context, id int64, status uint8, path string) error {
+	return dao.db.WithContext(ctx).Model(&DataExport{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status": status,
+			"path":   path,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+// AccountDeletion 注销申请，一个用户只有一条
+type AccountDeletion struct {
+	Id     int64 `gorm:"primaryKey,autoIncrement"`
+	Uid    int64 `gorm:"unique"`
+	Status uint8
+	// 冷静期结束的时间，毫秒数
+	ExecuteAt int64 `gorm:"index"`
+	Ctime     int64
+	Utime     int64
+}
+
+type DataExport struct {
+	Id     int64 `gorm:"primaryKey,autoIncrement"`
+	Uid    int64 `gorm:"index"`
+	Status uint8 `gorm:"index"`
+	Path   string
+	Ctime  int64
+	Utime  int64
+}
+
+// 这里的取值和 domain 中的保持一致
+const (
+	accountDeletionStatusPending uint8 = iota + 1
+	accountDeletionStatusCancelled
+	accountDeletionStatusDone
+)
+
+const (
+	dataExportStatusPending uint8 = iota + 1
+	dataExportStatusDone
+	dataExportStatusFailed
+)
@@ -28,6 +28,23 @@ func (dao *GORMArticleDAO) ListPubByUtime(ctx context.Context, utime time.Time,
 	return res, err
 }
 
+func (dao *GORMArticleDAO) ListPubByAuthor(ctx context.Context, author int64, status uint8, offset int, limit int) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	err := dao.db.WithContext(ctx).
+		Where("author_id = ? AND status = ?", author, status).
+		Order("utime DESC").
+		Limit(limit).Offset(offset).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMArticleDAO) ListPubByIds(ctx context.Context, ids []int64, status uint8) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	err := dao.db.WithContext(ctx).
+		Where("id IN ? AND status = ?", ids, status).
+		Find(&res).Error
+	return res, err
+}
+
 func (dao *GORMArticleDAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
 	var pub PublishedArticle
 	err := dao.db.WithContext(ctx).
@@ -86,6 +103,35 @@ func (dao *GORMArticleDAO) SyncStatus(ctx context.Context, uid, id int64, status
 	})
 }
 
+func (dao *GORMArticleDAO) SyncStatusByAuthor(ctx context.Context, uid int64, status uint8) ([]int64, error) {
+	now := time.Now().UnixMilli()
+	var ids []int64
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Article{}).
+			Where("author_id = ?", uid).
+			Updates(map[string]interface{}{
+				"status": status,
+				"utime":  now,
+			}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&PublishedArticle{}).
+			Where("author_id = ?", uid).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&PublishedArticle{}).
+			Where("author_id = ?", uid).
+			Updates(map[string]interface{}{
+				"status": status,
+				"utime":  now,
+			}).Error
+	})
+	return ids, err
+}
+
 // Sync 同步 Article 数据到数据库，并在发布文章表中同步数据
 func (dao *GORMArticleDAO) Sync(ctx context.Context, art Article) (int64, error) {
 	// 开始一个事务
@@ -21,6 +21,34 @@ func (m *MongoDBDAO) ListPubByUtime(ctx context.Context, utime time.Time, offset
 	panic("implement me")
 }
 
+func (m *MongoDBDAO) ListPubByAuthor(ctx context.Context, author int64, status uint8, offset int, limit int) ([]PublishedArticle, error) {
+	filter := bson.D{bson.E{Key: "author_id", Value: author},
+		bson.E{Key: "status", Value: status}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBDAO) ListPubByIds(ctx context.Context, ids []int64, status uint8) ([]PublishedArticle, error) {
+	filter := bson.D{bson.E{Key: "id", Value: bson.D{bson.E{Key: "$in", Value: ids}}},
+		bson.E{Key: "status", Value: status}}
+	cursor, err := m.liveCol.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
 func InitCollections(db *mongo.Database) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
@@ -146,3 +174,25 @@ func (m *MongoDBDAO) SyncStatus(ctx context.Context, uid, id int64, status uint8
 	}
 	return nil
 }
+
+func (m *MongoDBDAO) SyncStatusByAuthor(ctx context.Context, uid int64, status uint8) ([]int64, error) {
+	filter := bson.D{bson.E{Key: "author_id", Value: uid}}
+	sets := bson.D{bson.E{Key: "$set",
+		Value: bson.D{bson.E{Key: "status", Value: status}}}}
+	_, err := m.col.UpdateMany(ctx, filter, sets)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := m.liveCol.Distinct(ctx, "id", filter)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(vals))
+	for _, val := range vals {
+		if id, ok := val.(int64); ok {
+			ids = append(ids, id)
+		}
+	}
+	_, err = m.liveCol.UpdateMany(ctx, filter, sets)
+	return ids, err
+}
@@ -11,8 +11,15 @@ type ArticleDAO interface {
 	Sync(ctx context.Context, art Article) (int64, error)
 	SyncClosure(ctx context.Context, art Article) (int64, error)
 	SyncStatus(ctx context.Context, uid, id int64, status uint8) error
+	// SyncStatusByAuthor 修改某个作者全部文章的状态，注销账号的时候使用
+	// 返回的是线上库里面受影响的文章 ID，用于清理读者端的缓存
+	SyncStatusByAuthor(ctx context.Context, uid int64, status uint8) ([]int64, error)
 	GetByAuthor(ctx context.Context, author int64, offset, limit int) ([]Article, error)
 	GetById(ctx context.Context, id int64) (Article, error)
 	GetPubById(ctx context.Context, id int64) (PublishedArticle, error)
 	ListPubByUtime(ctx context.Context, utime time.Time, offset int, limit int) ([]PublishedArticle, error)
+	// ListPubByAuthor 某个作者某个状态的线上文章，按照更新时间倒序
+	ListPubByAuthor(ctx context.Context, author int64, status uint8, offset int, limit int) ([]PublishedArticle, error)
+	// ListPubByIds 批量查询某个状态的线上文章，不存在或者状态不对的直接忽略
+	ListPubByIds(ctx context.Context, ids []int64, status uint8) ([]PublishedArticle, error)
 }
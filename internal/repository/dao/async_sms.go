@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+// AsyncSMSDAO 等待重试的短信
+type AsyncSMSDAO interface {
+	Insert(ctx context.Context, s AsyncSMS) error
+	// FindDue 找到已经到了重试时间的短信
+	FindDue(ctx context.Context, now int64, limit int) ([]AsyncSMS, error)
+	// Update 更新重试的结果
+	Update(ctx context.Context, id int64, status uint8, retryCnt int, nextTime int64, lastErr string) error
+}
+
+type GORMAsyncSMSDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMAsyncSMSDAO(db *gorm.DB) AsyncSMSDAO {
+	return &GORMAsyncSMSDAO{db: db}
+}
+
+func (dao *GORMAsyncSMSDAO) Insert(ctx context.Context, s AsyncSMS) error {
+	now := time.Now().UnixMilli()
+	s.Ctime = now
+	s.Utime = now
+	return dao.db.WithContext(ctx).Create(&s).Error
+}
+
+func (dao *GORMAsyncSMSDAO) FindDue(ctx context.Context, now int64, limit int) ([]AsyncSMS, error) {
+	var res []AsyncSMS
+	err := dao.db.WithContext(ctx).
+		Where("status = ? AND next_time <= ?", asyncSMSStatusWaiting, now).
+		Order("next_time ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMAsyncSMSDAO) Update(ctx context.Context, id int64, status uint8,
+	retryCnt int, nextTime int64, lastErr string) error {
+	return dao.db.WithContext(ctx).Model(&AsyncSMS{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":    status,
+			"retry_cnt": retryCnt,
+			"next_time": nextTime,
+			"last_err":  lastErr,
+			"utime":     time.Now().UnixMilli(),
+		}).Error
+}
+
+type AsyncSMS struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	TplId string `gorm:"type:varchar(128)"`
+	// Args 是加密之后的 JSON 数组，里面可能有验证码
+	Args string
+	// Numbers 是 JSON 数组
+	Numbers  string
+	RetryCnt int
+	Status   uint8 `gorm:"index:idx_status_next_time"`
+	NextTime int64 `gorm:"index:idx_status_next_time"`
+	// Deadline 为 0 表示没有限制
+	Deadline int64
+	LastErr  string `gorm:"type:varchar(1024)"`
+	Ctime    int64
+	Utime    int64
+}
+
+// 这里的取值和 domain 中的保持一致
+const (
+	asyncSMSStatusWaiting uint8 = iota + 1
+)
@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -34,7 +35,15 @@ func (dao *GORMJobDAO) Insert(ctx context.Context, j Job) error {
 	now := time.Now().UnixMilli()
 	j.Ctime = now
 	j.Utime = now
-	return dao.db.WithContext(ctx).Create(&j).Error
+	// 同名的任务已经存在的时候，例如重启之后重复注册，只更新任务的配置
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"executor":   j.Executor,
+			"cfg":        j.Cfg,
+			"expression": j.Expression,
+			"utime":      now,
+		}),
+	}).Create(&j).Error
 }
 
 func (dao *GORMJobDAO) UpdateUtime(ctx context.Context, id int64, version int64) error {
@@ -88,8 +97,8 @@ func (dao *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
 }
 
 type Job struct {
-	Id         int64 `gorm:"primaryKey,autoIncrement"`
-	Name       string
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	Name       string `gorm:"type:varchar(128);unique"`
 	Executor   string
 	Cfg        string
 	Expression string
@@ -6,10 +6,30 @@ import (
 )
 
 func InitTables(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&User{},
 		&article.Article{},
 		&article.PublishedArticle{},
 		&Job{},
+		&AccountDeletion{},
+		&DataExport{},
+		&UserRole{},
+		&AuditLog{},
+		&PersonalAccessToken{},
+		&AsyncSMS{},
+		&SMSRecord{},
 	)
+	if err != nil {
+		return err
+	}
+	return migratePhoneE164(db)
+}
+
+// migratePhoneE164 以前的手机号是原样保存的，并且只支持国内的号码，
+// 现在统一保存成 E.164 格式，没有加号的都补上 +86
+// 只会更新还没有迁移的数据，所以每次启动都执行也没关系
+func migratePhoneE164(db *gorm.DB) error {
+	return db.Model(&User{}).
+		Where("phone IS NOT NULL AND phone NOT LIKE ?", "+%").
+		Update("phone", gorm.Expr("CONCAT('+86', phone)")).Error
 }
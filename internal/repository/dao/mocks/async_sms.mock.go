@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/dao/async_sms.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/dao/async_sms.go -package=daomocks -destination=./internal/repository/dao/mocks/async_sms.mock.go
+//
+
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	dao "webook/internal/repository/dao"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAsyncSMSDAO is a mock of AsyncSMSDAO interface.
+type MockAsyncSMSDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockAsyncSMSDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockAsyncSMSDAOMockRecorder is the mock recorder for MockAsyncSMSDAO.
+type MockAsyncSMSDAOMockRecorder struct {
+	mock *MockAsyncSMSDAO
+}
+
+// NewMockAsyncSMSDAO creates a new mock instance.
+func NewMockAsyncSMSDAO(ctrl *gomock.Controller) *MockAsyncSMSDAO {
+	mock := &MockAsyncSMSDAO{ctrl: ctrl}
+	mock.recorder = &MockAsyncSMSDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAsyncSMSDAO) EXPECT() *MockAsyncSMSDAOMockRecorder {
+	return m.recorder
+}
+
+// FindDue mocks base method.
+func (m *MockAsyncSMSDAO) FindDue(ctx context.Context, now int64, limit int) ([]dao.AsyncSMS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", ctx, now, limit)
+	ret0, _ := ret[0].([]dao.AsyncSMS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockAsyncSMSDAOMockRecorder) FindDue(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockAsyncSMSDAO)(nil).FindDue), ctx, now, limit)
+}
+
+// Insert mocks base method.
+func (m *MockAsyncSMSDAO) Insert(ctx context.Context, s dao.AsyncSMS) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockAsyncSMSDAOMockRecorder) Insert(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockAsyncSMSDAO)(nil).Insert), ctx, s)
+}
+
+// Update mocks base method.
+func (m *MockAsyncSMSDAO) Update(ctx context.Context, id int64, status uint8, retryCnt int, nextTime int64, lastErr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, status, retryCnt, nextTime, lastErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAsyncSMSDAOMockRecorder) Update(ctx, id, status, retryCnt, nextTime, lastErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAsyncSMSDAO)(nil).Update), ctx, id, status, retryCnt, nextTime, lastErr)
+}
@@ -41,6 +41,20 @@ func (m *MockUserDAO) EXPECT() *MockUserDAOMockRecorder {
 	return m.recorder
 }
 
+// Anonymize mocks base method.
+func (m *MockUserDAO) Anonymize(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockUserDAOMockRecorder) Anonymize(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockUserDAO)(nil).Anonymize), ctx, id)
+}
+
 // FindByEmail mocks base method.
 func (m *MockUserDAO) FindByEmail(ctx context.Context, email string) (dao.User, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +70,21 @@ func (mr *MockUserDAOMockRecorder) FindByEmail(ctx, email any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserDAO)(nil).FindByEmail), ctx, email)
 }
 
+// FindByHandle mocks base method.
+func (m *MockUserDAO) FindByHandle(ctx context.Context, handle string) (dao.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHandle", ctx, handle)
+	ret0, _ := ret[0].(dao.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHandle indicates an expected call of FindByHandle.
+func (mr *MockUserDAOMockRecorder) FindByHandle(ctx, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHandle", reflect.TypeOf((*MockUserDAO)(nil).FindByHandle), ctx, handle)
+}
+
 // FindById mocks base method.
 func (m *MockUserDAO) FindById(ctx context.Context, id int64) (dao.User, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +129,35 @@ func (mr *MockUserDAOMockRecorder) Insert(ctx, u any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockUserDAO)(nil).Insert), ctx, u)
 }
 
+// Search mocks base method.
+func (m *MockUserDAO) Search(ctx context.Context, keyword string, offset, limit int) ([]dao.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, keyword, offset, limit)
+	ret0, _ := ret[0].([]dao.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockUserDAOMockRecorder) Search(ctx, keyword, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUserDAO)(nil).Search), ctx, keyword, offset, limit)
+}
+
+// UpdateBanned mocks base method.
+func (m *MockUserDAO) UpdateBanned(ctx context.Context, id int64, banned bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBanned", ctx, id, banned)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBanned indicates an expected call of UpdateBanned.
+func (mr *MockUserDAOMockRecorder) UpdateBanned(ctx, id, banned any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBanned", reflect.TypeOf((*MockUserDAO)(nil).UpdateBanned), ctx, id, banned)
+}
+
 // UpdateNonZeroFields mocks base method.
 func (m *MockUserDAO) UpdateNonZeroFields(ctx context.Context, u dao.User) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+// PATDAO 个人访问令牌
+type PATDAO interface {
+	Insert(ctx context.Context, t PersonalAccessToken) (int64, error)
+	FindByHash(ctx context.Context, hash string) (PersonalAccessToken, error)
+	ListByUid(ctx context.Context, uid int64) ([]PersonalAccessToken, error)
+	CountByUid(ctx context.Context, uid int64) (int64, error)
+	// Delete 只能删除自己的令牌，不存在的时候返回 ErrDataNotFound
+	Delete(ctx context.Context, uid, id int64) error
+}
+
+type GORMPATDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMPATDAO(db *gorm.DB) PATDAO {
+	return &GORMPATDAO{db: db}
+}
+
+func (dao *GORMPATDAO) Insert(ctx context.Context, t PersonalAccessToken) (int64, error) {
+	t.Ctime = time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Create(&t).Error
+	return t.Id, err
+}
+
+func (dao *GORMPATDAO) FindByHash(ctx context.Context, hash string) (PersonalAccessToken, error) {
+	var t PersonalAccessToken
+	err := dao.db.WithContext(ctx).Where("token_hash = ?", hash).First(&t).Error
+	return t, err
+}
+
+func (dao *GORMPATDAO) ListByUid(ctx context.Context, uid int64) ([]PersonalAccessToken, error) {
+	var res []PersonalAccessToken
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("id DESC").Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMPATDAO) CountByUid(ctx context.Context, uid int64) (int64, error) {
+	var cnt int64
+	err := dao.db.WithContext(ctx).Model(&PersonalAccessToken{}).
+		Where("uid = ?", uid).Count(&cnt).Error
+	return cnt, err
+}
+
+func (dao *GORMPATDAO) Delete(ctx context.Context, uid, id int64) error {
+	res := dao.db.WithContext(ctx).
+		Where("id = ? AND uid = ?", id, uid).
+		Delete(&PersonalAccessToken{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrDataNotFound
+	}
+	return nil
+}
+
+type PersonalAccessToken struct {
+	Id   int64  `gorm:"primaryKey,autoIncrement"`
+	Uid  int64  `gorm:"index"`
+	Name string `gorm:"type:varchar(64)"`
+	// 令牌的 SHA256，十六进制
+	TokenHash string `gorm:"type:char(64);unique"`
+	// 逗号分隔的权限范围
+	Scopes string `gorm:"type:varchar(256)"`
+	// 过期时间，毫秒数
+	ExpireAt int64
+	Ctime    int64
+}
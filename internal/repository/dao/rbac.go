@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// RBACDAO 用户和角色的对应关系，以及管理员操作的审计日志
+type RBACDAO interface {
+	FindRoles(ctx context.Context, uid int64) ([]UserRole, error)
+	// InsertRole 给用户添加角色，已经有这个角色的时候什么也不做
+	// 返回值表示是不是真的插入了
+	InsertRole(ctx context.Context, r UserRole) (bool, error)
+	DeleteRole(ctx context.Context, uid int64, role string) error
+
+	InsertAuditLog(ctx context.Context, l AuditLog) error
+	ListAuditLogs(ctx context.Context, offset, limit int) ([]AuditLog, error)
+}
+
+type GORMRBACDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMRBACDAO(db *gorm.DB) RBACDAO {
+	return &GORMRBACDAO{db: db}
+}
+
+func (dao *GORMRBACDAO) FindRoles(ctx context.Context, uid int64) ([]UserRole, error) {
+	var res []UserRole
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMRBACDAO) InsertRole(ctx context.Context, r UserRole) (bool, error) {
+	r.Ctime = time.Now().UnixMilli()
+	res := dao.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&r)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (dao *GORMRBACDAO) DeleteRole(ctx context.Context, uid int64, role string) error {
+	return dao.db.WithContext(ctx).
+		Where("uid = ? AND role = ?", uid, role).
+		Delete(&UserRole{}).Error
+}
+
+func (dao *GORMRBACDAO) InsertAuditLog(ctx context.Context, l AuditLog) error {
+	l.Ctime = time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Create(&l).Error
+}
+
+func (dao *GORMRBACDAO) ListAuditLogs(ctx context.Context, offset, limit int) ([]AuditLog, error) {
+	var res []AuditLog
+	err := dao.db.WithContext(ctx).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// UserRole 用户拥有的角色
+type UserRole struct {
+	Id   int64  `gorm:"primaryKey,autoIncrement"`
+	Uid  int64  `gorm:"uniqueIndex:uid_role"`
+	Role string `gorm:"type:varchar(64);uniqueIndex:uid_role"`
+	// 谁授予的，系统初始化的是 0
+	Operator int64
+	Ctime    int64
+}
+
+// AuditLog 管理员操作的审计日志，只插入，不修改
+type AuditLog struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	OperatorId int64  `gorm:"index"`
+	Action     string `gorm:"type:varchar(64)"`
+	TargetId   int64  `gorm:"index"`
+	Detail     string `gorm:"type:varchar(1024)"`
+	Ctime      int64
+}
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+// SMSRecordDAO 短信的发送记录
+type SMSRecordDAO interface {
+	Insert(ctx context.Context, rs []SMSRecord) error
+	// UpdateReport 根据服务商的状态报告更新记录
+	UpdateReport(ctx context.Context, provider string, messageId string,
+		status uint8, code string, msg string, reportTime int64) error
+	// FindByNumberHash 某个号码的发送记录，最新的在前面
+	FindByNumberHash(ctx context.Context, hash string, offset, limit int) ([]SMSRecord, error)
+}
+
+type GORMSMSRecordDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMSMSRecordDAO(db *gorm.DB) SMSRecordDAO {
+	return &GORMSMSRecordDAO{db: db}
+}
+
+func (dao *GORMSMSRecordDAO) Insert(ctx context.Context, rs []SMSRecord) error {
+	now := time.Now().UnixMilli()
+	for i := range rs {
+		rs[i].Ctime = now
+		rs[i].Utime = now
+	}
+	return dao.db.WithContext(ctx).Create(&rs).Error
+}
+
+func (dao *GORMSMSRecordDAO) UpdateReport(ctx context.Context, provider string, messageId string,
+	status uint8, code string, msg string, reportTime int64) error {
+	return dao.db.WithContext(ctx).Model(&SMSRecord{}).
+		Where("provider = ? AND message_id = ?", provider, messageId).
+		Updates(map[string]any{
+			"status":      status,
+			"report_code": code,
+			"report_msg":  msg,
+			"report_time": reportTime,
+			"utime":       time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *GORMSMSRecordDAO) FindByNumberHash(ctx context.Context, hash string, offset, limit int) ([]SMSRecord, error) {
+	var res []SMSRecord
+	err := dao.db.WithContext(ctx).
+		Where("number_hash = ?", hash).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// SMSRecord 不保存完整的号码，只保存打码之后的号码和号码的 HMAC，
+// 客服按照号码查询的时候用 HMAC 匹配
+type SMSRecord struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	Provider   string `gorm:"type:varchar(32);index:idx_provider_message_id"`
+	MessageId  string `gorm:"type:varchar(128);index:idx_provider_message_id"`
+	TplId      string `gorm:"type:varchar(128)"`
+	Number     string `gorm:"type:varchar(32)"`
+	NumberHash string `gorm:"type:varchar(64);index"`
+	// Latency 毫秒
+	Latency    int64
+	Status     uint8
+	Err        string `gorm:"type:varchar(1024)"`
+	ReportCode string `gorm:"type:varchar(64)"`
+	ReportMsg  string `gorm:"type:varchar(256)"`
+	ReportTime int64
+	Ctime      int64
+	Utime      int64
+}
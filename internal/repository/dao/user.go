@@ -4,14 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
 var (
 	// ErrUserDuplicate 表示用户邮箱或者手机号冲突错误
 	ErrUserDuplicate = errors.New("用户邮箱或者手机号冲突")
+	// 具体是哪个唯一索引冲突了，都包装了 ErrUserDuplicate，
+	// 所以只关心有没有冲突的地方依旧可以用 errors.Is(err, ErrUserDuplicate)
+	ErrUserDuplicateEmail  = fmt.Errorf("%w：邮箱已经被使用", ErrUserDuplicate)
+	ErrUserDuplicatePhone  = fmt.Errorf("%w：手机号已经被使用", ErrUserDuplicate)
+	ErrUserDuplicateHandle = fmt.Errorf("%w：用户名已经被使用", ErrUserDuplicate)
 
 	// ErrDataNotFound 通用的数据没找到错误（即Gorm的记录未找到）
 	ErrDataNotFound = gorm.ErrRecordNotFound
@@ -25,7 +32,34 @@ type UserDAO interface {
 	FindByPhone(ctx context.Context, phone string) (User, error)
 	FindByEmail(ctx context.Context, email string) (User, error)
 	FindById(ctx context.Context, id int64) (User, error)
+	FindByHandle(ctx context.Context, handle string) (User, error)
 	UpdateNonZeroFields(ctx context.Context, u User) error
+	// Anonymize 抹掉用户的个人信息，用于注销账号
+	Anonymize(ctx context.Context, id int64) error
+	// UpdateBanned 封禁或者解封，false 是零值，所以不能用 UpdateNonZeroFields
+	UpdateBanned(ctx context.Context, id int64, banned bool) error
+	// Search 管理后台搜索用户，邮箱、手机号、用户名精确匹配，昵称前缀匹配
+	Search(ctx context.Context, keyword string, offset, limit int) ([]User, error)
+}
+
+// duplicateErr 根据冲突的索引名字区分是哪个字段冲突了
+// MySQL 的错误信息是 Duplicate entry 'xxx' for key 'users.uni_users_phone' 这种格式，
+// 索引的名字是 GORM 根据字段名生成的，不认识的索引返回 ErrUserDuplicate
+func duplicateErr(me *mysql.MySQLError) error {
+	_, key, found := strings.Cut(me.Message, "for key ")
+	if !found {
+		return ErrUserDuplicate
+	}
+	switch {
+	case strings.Contains(key, "phone"):
+		return ErrUserDuplicatePhone
+	case strings.Contains(key, "email"):
+		return ErrUserDuplicateEmail
+	case strings.Contains(key, "handle"):
+		return ErrUserDuplicateHandle
+	default:
+		return ErrUserDuplicate
+	}
 }
 
 // GormUserDAO 是与用户相关的数据访问对象，它封装了与用户数据表交互的所有操作
@@ -55,7 +89,7 @@ func (ud *GormUserDAO) Insert(ctx context.Context, u User) error {
 		const uniqueIndexErrNo uint16 = 1062 // 唯一索引冲突错误码
 		if me.Number == uniqueIndexErrNo {
 			// 如果是唯一索引冲突，返回自定义的 ErrUserDuplicate 错误
-			return ErrUserDuplicate
+			return duplicateErr(me)
 		}
 	}
 	return err // 如果是其他错误，直接返回
@@ -73,6 +107,12 @@ func (ud *GormUserDAO) FindByPhone(ctx context.Context, phone string) (User, err
 	return u, err
 }
 
+func (ud *GormUserDAO) FindByHandle(ctx context.Context, handle string) (User, error) {
+	var u User
+	err := ud.db.WithContext(ctx).First(&u, "handle = ?", handle).Error
+	return u, err
+}
+
 func (ud *GormUserDAO) FindById(ctx context.Context, id int64) (User, error) {
 	var u User
 	err := ud.db.WithContext(ctx).First(&u, "id = ?", id).Error
@@ -85,7 +125,54 @@ func (ud *GormUserDAO) UpdateNonZeroFields(ctx context.Context, u User) error {
 	// 会使用非零值来更新
 	// 另外一种做法是显式指定只更新必要的字段，
 	// 那么这意味着 DAO 和 service 中非敏感字段语义耦合了
-	return ud.db.Updates(&u).Error
+	u.Utime = time.Now().UnixMilli()
+	err := ud.db.WithContext(ctx).Updates(&u).Error
+	var me *mysql.MySQLError
+	if errors.As(err, &me) {
+		const uniqueIndexErrNo uint16 = 1062
+		if me.Number == uniqueIndexErrNo {
+			// 换绑手机号或者邮箱的时候，新的手机号或者邮箱已经被别人用了
+			return duplicateErr(me)
+		}
+	}
+	return err
+}
+
+func (ud *GormUserDAO) Anonymize(ctx context.Context, id int64) error {
+	// 邮箱和手机号置为 NULL，这样别人还可以用它们重新注册
+	return ud.db.WithContext(ctx).Model(&User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"email":    nil,
+			"phone":    nil,
+			"password": "",
+			"nickname": "已注销用户",
+			"about_me": "",
+			"birthday": nil,
+			"handle":   nil,
+			"avatar":   "",
+			"utime":    time.Now().UnixMilli(),
+		}).Error
+}
+
+func (ud *GormUserDAO) UpdateBanned(ctx context.Context, id int64, banned bool) error {
+	return ud.db.WithContext(ctx).Model(&User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"banned": banned,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+func (ud *GormUserDAO) Search(ctx context.Context, keyword string, offset, limit int) ([]User, error) {
+	var res []User
+	db := ud.db.WithContext(ctx).Order("id DESC").Offset(offset).Limit(limit)
+	if keyword != "" {
+		db = db.Where("email = ? OR phone = ? OR handle = ? OR nickname LIKE ?",
+			keyword, keyword, keyword, keyword+"%")
+	}
+	err := db.Find(&res).Error
+	return res, err
 }
 
 // User 表示用户的数据模型，映射到数据库中的用户表
@@ -114,6 +201,16 @@ type User struct {
 	// 因此你可以看到在 web 里面有这个校验
 	AboutMe sql.NullString `gorm:"type=varchar(1024)"`
 
+	// 用户名，唯一索引，统一存小写
+	// 没有设置过用户名的是 NULL，所以也不会冲突
+	Handle sql.NullString `gorm:"unique"`
+	// 上一次修改用户名的时间，毫秒数
+	HandleUtime int64
+	// 头像的 URL
+	Avatar string `gorm:"type:varchar(1024)"`
+	// 是否被管理员封禁了
+	Banned bool
+
 	// 创建时间戳字段
 	Ctime int64 // 创建时间
 	// 更新时间戳字段
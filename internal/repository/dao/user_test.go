@@ -48,6 +48,32 @@ func TestGORMUserDAO_Insert(t *testing.T) {
 			ctx:     context.Background(),
 			wantErr: ErrUserDuplicate,
 		},
+		{
+			name: "插入失败-手机号冲突",
+			sqlmock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				assert.NoError(t, err)
+				mock.ExpectExec("INSERT INTO `users` .*").
+					WillReturnError(&mysqlDriver.MySQLError{Number: 1062,
+						Message: "Duplicate entry '+8613812345678' for key 'users.uni_users_phone'"})
+				return db
+			},
+			ctx:     context.Background(),
+			wantErr: ErrUserDuplicatePhone,
+		},
+		{
+			name: "插入失败-按照索引名区分邮箱冲突",
+			sqlmock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				assert.NoError(t, err)
+				mock.ExpectExec("INSERT INTO `users` .*").
+					WillReturnError(&mysqlDriver.MySQLError{Number: 1062,
+						Message: "Duplicate entry 'a@qq.com' for key 'email'"})
+				return db
+			},
+			ctx:     context.Background(),
+			wantErr: ErrUserDuplicateEmail,
+		},
 		{
 			name: "插入失败",
 			sqlmock: func(t *testing.T) *sql.DB {
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+)
+
+// FeedRepository 关注流的存储，收件箱和发件箱都放在 Redis 里面
+type FeedRepository interface {
+	// PushToInboxes 推模式，写到粉丝们的收件箱
+	PushToInboxes(ctx context.Context, uids []int64, item domain.FeedItem) error
+	// AddToOutbox 写到作者自己的发件箱，拉模式的时候从这里读
+	AddToOutbox(ctx context.Context, item domain.FeedItem) error
+	FindInbox(ctx context.Context, uid int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error)
+	FindOutbox(ctx context.Context, author int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error)
+	SetBigAuthor(ctx context.Context, author int64, big bool) error
+	FindBigAuthors(ctx context.Context, authors []int64) ([]int64, error)
+}
+
+type CachedFeedRepository struct {
+	cache cache.FeedCache
+}
+
+func NewCachedFeedRepository(c cache.FeedCache) FeedRepository {
+	return &CachedFeedRepository{
+		cache: c,
+	}
+}
+
+func (repo *CachedFeedRepository) PushToInboxes(ctx context.Context, uids []int64, item domain.FeedItem) error {
+	return repo.cache.AddToInboxes(ctx, uids, item)
+}
+
+func (repo *CachedFeedRepository) AddToOutbox(ctx context.Context, item domain.FeedItem) error {
+	return repo.cache.AddToOutbox(ctx, item)
+}
+
+func (repo *CachedFeedRepository) FindInbox(ctx context.Context, uid int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error) {
+	return repo.cache.Inbox(ctx, uid, before, limit)
+}
+
+func (repo *CachedFeedRepository) FindOutbox(ctx context.Context, author int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error) {
+	return repo.cache.Outbox(ctx, author, before, limit)
+}
+
+func (repo *CachedFeedRepository) SetBigAuthor(ctx context.Context, author int64, big bool) error {
+	return repo.cache.SetBigAuthor(ctx, author, big)
+}
+
+func (repo *CachedFeedRepository) FindBigAuthors(ctx context.Context, authors []int64) ([]int64, error) {
+	return repo.cache.BigAuthors(ctx, authors)
+}
@@ -0,0 +1,174 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/account.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/account.go -package=repomocks -destination=./internal/repository/mocks/account.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountRepository is a mock of AccountRepository interface.
+type MockAccountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountRepositoryMockRecorder is the mock recorder for MockAccountRepository.
+type MockAccountRepositoryMockRecorder struct {
+	mock *MockAccountRepository
+}
+
+// NewMockAccountRepository creates a new mock instance.
+func NewMockAccountRepository(ctrl *gomock.Controller) *MockAccountRepository {
+	mock := &MockAccountRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountRepository) EXPECT() *MockAccountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelDeletion mocks base method.
+func (m *MockAccountRepository) CancelDeletion(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelDeletion", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelDeletion indicates an expected call of CancelDeletion.
+func (mr *MockAccountRepositoryMockRecorder) CancelDeletion(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelDeletion", reflect.TypeOf((*MockAccountRepository)(nil).CancelDeletion), ctx, uid)
+}
+
+// CreateExport mocks base method.
+func (m *MockAccountRepository) CreateExport(ctx context.Context, e domain.DataExport) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExport", ctx, e)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExport indicates an expected call of CreateExport.
+func (mr *MockAccountRepositoryMockRecorder) CreateExport(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExport", reflect.TypeOf((*MockAccountRepository)(nil).CreateExport), ctx, e)
+}
+
+// FindDeletion mocks base method.
+func (m *MockAccountRepository) FindDeletion(ctx context.Context, uid int64) (domain.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletion", ctx, uid)
+	ret0, _ := ret[0].(domain.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletion indicates an expected call of FindDeletion.
+func (mr *MockAccountRepositoryMockRecorder) FindDeletion(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletion", reflect.TypeOf((*MockAccountRepository)(nil).FindDeletion), ctx, uid)
+}
+
+// FindDueDeletions mocks base method.
+func (m *MockAccountRepository) FindDueDeletions(ctx context.Context, now time.Time, limit int) ([]domain.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDueDeletions", ctx, now, limit)
+	ret0, _ := ret[0].([]domain.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDueDeletions indicates an expected call of FindDueDeletions.
+func (mr *MockAccountRepositoryMockRecorder) FindDueDeletions(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDueDeletions", reflect.TypeOf((*MockAccountRepository)(nil).FindDueDeletions), ctx, now, limit)
+}
+
+// FindExport mocks base method.
+func (m *MockAccountRepository) FindExport(ctx context.Context, id int64) (domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExport", ctx, id)
+	ret0, _ := ret[0].(domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExport indicates an expected call of FindExport.
+func (mr *MockAccountRepositoryMockRecorder) FindExport(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExport", reflect.TypeOf((*MockAccountRepository)(nil).FindExport), ctx, id)
+}
+
+// FindPendingExports mocks base method.
+func (m *MockAccountRepository) FindPendingExports(ctx context.Context, limit int) ([]domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingExports", ctx, limit)
+	ret0, _ := ret[0].([]domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingExports indicates an expected call of FindPendingExports.
+func (mr *MockAccountRepositoryMockRecorder) FindPendingExports(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingExports", reflect.TypeOf((*MockAccountRepository)(nil).FindPendingExports), ctx, limit)
+}
+
+// SaveDeletion mocks base method.
+func (m *MockAccountRepository) SaveDeletion(ctx context.Context, d domain.AccountDeletion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeletion", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeletion indicates an expected call of SaveDeletion.
+func (mr *MockAccountRepositoryMockRecorder) SaveDeletion(ctx, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeletion", reflect.TypeOf((*MockAccountRepository)(nil).SaveDeletion), ctx, d)
+}
+
+// UpdateDeletionStatus mocks base method.
+func (m *MockAccountRepository) UpdateDeletionStatus(ctx context.Context, uid int64, status domain.AccountDeletionStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDeletionStatus", ctx, uid, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDeletionStatus indicates an expected call of UpdateDeletionStatus.
+func (mr *MockAccountRepositoryMockRecorder) UpdateDeletionStatus(ctx, uid, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeletionStatus", reflect.TypeOf((*MockAccountRepository)(nil).UpdateDeletionStatus), ctx, uid, status)
+}
+
+// UpdateExport mocks base method.
+func (m *MockAccountRepository) UpdateExport(ctx context.Context, id int64, status domain.DataExportStatus, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExport", ctx, id, status, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateExport indicates an expected call of UpdateExport.
+func (mr *MockAccountRepositoryMockRecorder) UpdateExport(ctx, id, status, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExport", reflect.TypeOf((*MockAccountRepository)(nil).UpdateExport), ctx, id, status, path)
+}
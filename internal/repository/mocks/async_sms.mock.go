@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/async_sms.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/async_sms.go -package=repomocks -destination=./internal/repository/mocks/async_sms.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAsyncSMSRepository is a mock of AsyncSMSRepository interface.
+type MockAsyncSMSRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAsyncSMSRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAsyncSMSRepositoryMockRecorder is the mock recorder for MockAsyncSMSRepository.
+type MockAsyncSMSRepositoryMockRecorder struct {
+	mock *MockAsyncSMSRepository
+}
+
+// NewMockAsyncSMSRepository creates a new mock instance.
+func NewMockAsyncSMSRepository(ctrl *gomock.Controller) *MockAsyncSMSRepository {
+	mock := &MockAsyncSMSRepository{ctrl: ctrl}
+	mock.recorder = &MockAsyncSMSRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAsyncSMSRepository) EXPECT() *MockAsyncSMSRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockAsyncSMSRepository) Add(ctx context.Context, s domain.AsyncSMS) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockAsyncSMSRepositoryMockRecorder) Add(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAsyncSMSRepository)(nil).Add), ctx, s)
+}
+
+// FindDue mocks base method.
+func (m *MockAsyncSMSRepository) FindDue(ctx context.Context, limit int) ([]domain.AsyncSMS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", ctx, limit)
+	ret0, _ := ret[0].([]domain.AsyncSMS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockAsyncSMSRepositoryMockRecorder) FindDue(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockAsyncSMSRepository)(nil).FindDue), ctx, limit)
+}
+
+// ReportFailed mocks base method.
+func (m *MockAsyncSMSRepository) ReportFailed(ctx context.Context, id int64, retryCnt int, lastErr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportFailed", ctx, id, retryCnt, lastErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportFailed indicates an expected call of ReportFailed.
+func (mr *MockAsyncSMSRepositoryMockRecorder) ReportFailed(ctx, id, retryCnt, lastErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportFailed", reflect.TypeOf((*MockAsyncSMSRepository)(nil).ReportFailed), ctx, id, retryCnt, lastErr)
+}
+
+// ReportRetry mocks base method.
+func (m *MockAsyncSMSRepository) ReportRetry(ctx context.Context, id int64, retryCnt int, nextTime time.Time, lastErr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportRetry", ctx, id, retryCnt, nextTime, lastErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportRetry indicates an expected call of ReportRetry.
+func (mr *MockAsyncSMSRepositoryMockRecorder) ReportRetry(ctx, id, retryCnt, nextTime, lastErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportRetry", reflect.TypeOf((*MockAsyncSMSRepository)(nil).ReportRetry), ctx, id, retryCnt, nextTime, lastErr)
+}
+
+// ReportSuccess mocks base method.
+func (m *MockAsyncSMSRepository) ReportSuccess(ctx context.Context, id int64, retryCnt int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportSuccess", ctx, id, retryCnt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportSuccess indicates an expected call of ReportSuccess.
+func (mr *MockAsyncSMSRepositoryMockRecorder) ReportSuccess(ctx, id, retryCnt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportSuccess", reflect.TypeOf((*MockAsyncSMSRepository)(nil).ReportSuccess), ctx, id, retryCnt)
+}
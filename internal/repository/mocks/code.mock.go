@@ -12,6 +12,7 @@ package repomocks
 import (
 	context "context"
 	reflect "reflect"
+	repository "webook/internal/repository"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,30 +42,30 @@ func (m *MockCodeRepository) EXPECT() *MockCodeRepositoryMockRecorder {
 }
 
 // Store mocks base method.
-func (m *MockCodeRepository) Store(ctx context.Context, biz, phone, code string) error {
+func (m *MockCodeRepository) Store(ctx context.Context, biz, target, code string, setting repository.CodeSetting) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Store", ctx, biz, phone, code)
+	ret := m.ctrl.Call(m, "Store", ctx, biz, target, code, setting)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Store indicates an expected call of Store.
-func (mr *MockCodeRepositoryMockRecorder) Store(ctx, biz, phone, code any) *gomock.Call {
+func (mr *MockCodeRepositoryMockRecorder) Store(ctx, biz, target, code, setting any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockCodeRepository)(nil).Store), ctx, biz, phone, code)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockCodeRepository)(nil).Store), ctx, biz, target, code, setting)
 }
 
 // Verify mocks base method.
-func (m *MockCodeRepository) Verify(ctx context.Context, biz, phone, inputCode string) (bool, error) {
+func (m *MockCodeRepository) Verify(ctx context.Context, biz, target, inputCode string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Verify", ctx, biz, phone, inputCode)
+	ret := m.ctrl.Call(m, "Verify", ctx, biz, target, inputCode)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Verify indicates an expected call of Verify.
-func (mr *MockCodeRepositoryMockRecorder) Verify(ctx, biz, phone, inputCode any) *gomock.Call {
+func (mr *MockCodeRepositoryMockRecorder) Verify(ctx, biz, target, inputCode any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockCodeRepository)(nil).Verify), ctx, biz, phone, inputCode)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockCodeRepository)(nil).Verify), ctx, biz, target, inputCode)
 }
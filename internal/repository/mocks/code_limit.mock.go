@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/code_limit.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/code_limit.go -package=repomocks -destination=./internal/repository/mocks/code_limit.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	repository "webook/internal/repository"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCodeLimitRepository is a mock of CodeLimitRepository interface.
+type MockCodeLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeLimitRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCodeLimitRepositoryMockRecorder is the mock recorder for MockCodeLimitRepository.
+type MockCodeLimitRepositoryMockRecorder struct {
+	mock *MockCodeLimitRepository
+}
+
+// NewMockCodeLimitRepository creates a new mock instance.
+func NewMockCodeLimitRepository(ctrl *gomock.Controller) *MockCodeLimitRepository {
+	mock := &MockCodeLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockCodeLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeLimitRepository) EXPECT() *MockCodeLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Counts mocks base method.
+func (m *MockCodeLimitRepository) Counts(ctx context.Context, keys []string) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Counts", ctx, keys)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Counts indicates an expected call of Counts.
+func (mr *MockCodeLimitRepositoryMockRecorder) Counts(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Counts", reflect.TypeOf((*MockCodeLimitRepository)(nil).Counts), ctx, keys)
+}
+
+// Incr mocks base method.
+func (m *MockCodeLimitRepository) Incr(ctx context.Context, counters []repository.CodeLimitCounter) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, counters)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr.
+func (mr *MockCodeLimitRepositoryMockRecorder) Incr(ctx, counters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockCodeLimitRepository)(nil).Incr), ctx, counters)
+}
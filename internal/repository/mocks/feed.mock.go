@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/feed.go -package=repomocks -destination=./internal/repository/mocks/feed.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedRepository is a mock of FeedRepository interface.
+type MockFeedRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedRepositoryMockRecorder is the mock recorder for MockFeedRepository.
+type MockFeedRepositoryMockRecorder struct {
+	mock *MockFeedRepository
+}
+
+// NewMockFeedRepository creates a new mock instance.
+func NewMockFeedRepository(ctrl *gomock.Controller) *MockFeedRepository {
+	mock := &MockFeedRepository{ctrl: ctrl}
+	mock.recorder = &MockFeedRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedRepository) EXPECT() *MockFeedRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToOutbox mocks base method.
+func (m *MockFeedRepository) AddToOutbox(ctx context.Context, item domain.FeedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToOutbox", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToOutbox indicates an expected call of AddToOutbox.
+func (mr *MockFeedRepositoryMockRecorder) AddToOutbox(ctx, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToOutbox", reflect.TypeOf((*MockFeedRepository)(nil).AddToOutbox), ctx, item)
+}
+
+// FindBigAuthors mocks base method.
+func (m *MockFeedRepository) FindBigAuthors(ctx context.Context, authors []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBigAuthors", ctx, authors)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBigAuthors indicates an expected call of FindBigAuthors.
+func (mr *MockFeedRepositoryMockRecorder) FindBigAuthors(ctx, authors any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBigAuthors", reflect.TypeOf((*MockFeedRepository)(nil).FindBigAuthors), ctx, authors)
+}
+
+// FindInbox mocks base method.
+func (m *MockFeedRepository) FindInbox(ctx context.Context, uid int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInbox", ctx, uid, before, limit)
+	ret0, _ := ret[0].([]domain.FeedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInbox indicates an expected call of FindInbox.
+func (mr *MockFeedRepositoryMockRecorder) FindInbox(ctx, uid, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInbox", reflect.TypeOf((*MockFeedRepository)(nil).FindInbox), ctx, uid, before, limit)
+}
+
+// FindOutbox mocks base method.
+func (m *MockFeedRepository) FindOutbox(ctx context.Context, author int64, before domain.FeedCursor, limit int64) ([]domain.FeedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOutbox", ctx, author, before, limit)
+	ret0, _ := ret[0].([]domain.FeedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOutbox indicates an expected call of FindOutbox.
+func (mr *MockFeedRepositoryMockRecorder) FindOutbox(ctx, author, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOutbox", reflect.TypeOf((*MockFeedRepository)(nil).FindOutbox), ctx, author, before, limit)
+}
+
+// PushToInboxes mocks base method.
+func (m *MockFeedRepository) PushToInboxes(ctx context.Context, uids []int64, item domain.FeedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushToInboxes", ctx, uids, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushToInboxes indicates an expected call of PushToInboxes.
+func (mr *MockFeedRepositoryMockRecorder) PushToInboxes(ctx, uids, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushToInboxes", reflect.TypeOf((*MockFeedRepository)(nil).PushToInboxes), ctx, uids, item)
+}
+
+// SetBigAuthor mocks base method.
+func (m *MockFeedRepository) SetBigAuthor(ctx context.Context, author int64, big bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBigAuthor", ctx, author, big)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBigAuthor indicates an expected call of SetBigAuthor.
+func (mr *MockFeedRepositoryMockRecorder) SetBigAuthor(ctx, author, big any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBigAuthor", reflect.TypeOf((*MockFeedRepository)(nil).SetBigAuthor), ctx, author, big)
+}
@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/pat.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/pat.go -package=repomocks -destination=./internal/repository/mocks/pat.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPATRepository is a mock of PATRepository interface.
+type MockPATRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPATRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPATRepositoryMockRecorder is the mock recorder for MockPATRepository.
+type MockPATRepositoryMockRecorder struct {
+	mock *MockPATRepository
+}
+
+// NewMockPATRepository creates a new mock instance.
+func NewMockPATRepository(ctrl *gomock.Controller) *MockPATRepository {
+	mock := &MockPATRepository{ctrl: ctrl}
+	mock.recorder = &MockPATRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPATRepository) EXPECT() *MockPATRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method.
+func (m *MockPATRepository) Count(ctx context.Context, uid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockPATRepositoryMockRecorder) Count(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockPATRepository)(nil).Count), ctx, uid)
+}
+
+// Create mocks base method.
+func (m *MockPATRepository) Create(ctx context.Context, t domain.PersonalAccessToken, hash string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t, hash)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPATRepositoryMockRecorder) Create(ctx, t, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPATRepository)(nil).Create), ctx, t, hash)
+}
+
+// Delete mocks base method.
+func (m *MockPATRepository) Delete(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPATRepositoryMockRecorder) Delete(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPATRepository)(nil).Delete), ctx, uid, id)
+}
+
+// FindByHash mocks base method.
+func (m *MockPATRepository) FindByHash(ctx context.Context, hash string) (domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHash", ctx, hash)
+	ret0, _ := ret[0].(domain.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHash indicates an expected call of FindByHash.
+func (mr *MockPATRepositoryMockRecorder) FindByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHash", reflect.TypeOf((*MockPATRepository)(nil).FindByHash), ctx, hash)
+}
+
+// List mocks base method.
+func (m *MockPATRepository) List(ctx context.Context, uid int64) ([]domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, uid)
+	ret0, _ := ret[0].([]domain.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPATRepositoryMockRecorder) List(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPATRepository)(nil).List), ctx, uid)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/qr_login.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/qr_login.go -package=repomocks -destination=./internal/repository/mocks/qr_login.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQRLoginRepository is a mock of QRLoginRepository interface.
+type MockQRLoginRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQRLoginRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQRLoginRepositoryMockRecorder is the mock recorder for MockQRLoginRepository.
+type MockQRLoginRepositoryMockRecorder struct {
+	mock *MockQRLoginRepository
+}
+
+// NewMockQRLoginRepository creates a new mock instance.
+func NewMockQRLoginRepository(ctrl *gomock.Controller) *MockQRLoginRepository {
+	mock := &MockQRLoginRepository{ctrl: ctrl}
+	mock.recorder = &MockQRLoginRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQRLoginRepository) EXPECT() *MockQRLoginRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockQRLoginRepository) Consume(ctx context.Context, ticket string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, ticket)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockQRLoginRepositoryMockRecorder) Consume(ctx, ticket any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockQRLoginRepository)(nil).Consume), ctx, ticket)
+}
+
+// Create mocks base method.
+func (m *MockQRLoginRepository) Create(ctx context.Context, t domain.QRLoginTicket, expiration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQRLoginRepositoryMockRecorder) Create(ctx, t, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQRLoginRepository)(nil).Create), ctx, t, expiration)
+}
+
+// Find mocks base method.
+func (m *MockQRLoginRepository) Find(ctx context.Context, ticket string) (domain.QRLoginTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, ticket)
+	ret0, _ := ret[0].(domain.QRLoginTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockQRLoginRepositoryMockRecorder) Find(ctx, ticket any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockQRLoginRepository)(nil).Find), ctx, ticket)
+}
+
+// Transit mocks base method.
+func (m *MockQRLoginRepository) Transit(ctx context.Context, ticket string, from, to domain.QRLoginStatus, uid int64, bind bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transit", ctx, ticket, from, to, uid, bind)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transit indicates an expected call of Transit.
+func (mr *MockQRLoginRepositoryMockRecorder) Transit(ctx, ticket, from, to, uid, bind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transit", reflect.TypeOf((*MockQRLoginRepository)(nil).Transit), ctx, ticket, from, to, uid, bind)
+}
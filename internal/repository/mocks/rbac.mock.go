@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/rbac.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/rbac.go -package=repomocks -destination=./internal/repository/mocks/rbac.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRBACRepository is a mock of RBACRepository interface.
+type MockRBACRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRBACRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRBACRepositoryMockRecorder is the mock recorder for MockRBACRepository.
+type MockRBACRepositoryMockRecorder struct {
+	mock *MockRBACRepository
+}
+
+// NewMockRBACRepository creates a new mock instance.
+func NewMockRBACRepository(ctrl *gomock.Controller) *MockRBACRepository {
+	mock := &MockRBACRepository{ctrl: ctrl}
+	mock.recorder = &MockRBACRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRBACRepository) EXPECT() *MockRBACRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddAuditLog mocks base method.
+func (m *MockRBACRepository) AddAuditLog(ctx context.Context, l domain.AuditLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAuditLog", ctx, l)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAuditLog indicates an expected call of AddAuditLog.
+func (mr *MockRBACRepositoryMockRecorder) AddAuditLog(ctx, l any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAuditLog", reflect.TypeOf((*MockRBACRepository)(nil).AddAuditLog), ctx, l)
+}
+
+// AddRole mocks base method.
+func (m *MockRBACRepository) AddRole(ctx context.Context, operator, uid int64, role domain.Role) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRole", ctx, operator, uid, role)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRole indicates an expected call of AddRole.
+func (mr *MockRBACRepositoryMockRecorder) AddRole(ctx, operator, uid, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRole", reflect.TypeOf((*MockRBACRepository)(nil).AddRole), ctx, operator, uid, role)
+}
+
+// FindRoles mocks base method.
+func (m *MockRBACRepository) FindRoles(ctx context.Context, uid int64) ([]domain.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRoles", ctx, uid)
+	ret0, _ := ret[0].([]domain.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRoles indicates an expected call of FindRoles.
+func (mr *MockRBACRepositoryMockRecorder) FindRoles(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRoles", reflect.TypeOf((*MockRBACRepository)(nil).FindRoles), ctx, uid)
+}
+
+// ListAuditLogs mocks base method.
+func (m *MockRBACRepository) ListAuditLogs(ctx context.Context, offset, limit int) ([]domain.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogs", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogs indicates an expected call of ListAuditLogs.
+func (mr *MockRBACRepositoryMockRecorder) ListAuditLogs(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogs", reflect.TypeOf((*MockRBACRepository)(nil).ListAuditLogs), ctx, offset, limit)
+}
+
+// RemoveRole mocks base method.
+func (m *MockRBACRepository) RemoveRole(ctx context.Context, uid int64, role domain.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRole", ctx, uid, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRole indicates an expected call of RemoveRole.
+func (mr *MockRBACRepositoryMockRecorder) RemoveRole(ctx, uid, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRole", reflect.TypeOf((*MockRBACRepository)(nil).RemoveRole), ctx, uid, role)
+}
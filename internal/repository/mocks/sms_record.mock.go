@@ -0,0 +1,90 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/sms_record.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/sms_record.go -package=repomocks -destination=internal/repository/mocks/sms_record.mock.go SMSRecordRepository
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSMSRecordRepository is a mock of SMSRecordRepository interface.
+type MockSMSRecordRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSMSRecordRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSMSRecordRepositoryMockRecorder is the mock recorder for MockSMSRecordRepository.
+type MockSMSRecordRepositoryMockRecorder struct {
+	mock *MockSMSRecordRepository
+}
+
+// NewMockSMSRecordRepository creates a new mock instance.
+func NewMockSMSRecordRepository(ctrl *gomock.Controller) *MockSMSRecordRepository {
+	mock := &MockSMSRecordRepository{ctrl: ctrl}
+	mock.recorder = &MockSMSRecordRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSMSRecordRepository) EXPECT() *MockSMSRecordRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockSMSRecordRepository) Add(ctx context.Context, rs ...domain.SMSRecord) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range rs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Add", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockSMSRecordRepositoryMockRecorder) Add(ctx any, rs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, rs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockSMSRecordRepository)(nil).Add), varargs...)
+}
+
+// FindByNumber mocks base method.
+func (m *MockSMSRecordRepository) FindByNumber(ctx context.Context, number string, offset, limit int) ([]domain.SMSRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByNumber", ctx, number, offset, limit)
+	ret0, _ := ret[0].([]domain.SMSRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNumber indicates an expected call of FindByNumber.
+func (mr *MockSMSRecordRepositoryMockRecorder) FindByNumber(ctx, number, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNumber", reflect.TypeOf((*MockSMSRecordRepository)(nil).FindByNumber), ctx, number, offset, limit)
+}
+
+// UpdateReport mocks base method.
+func (m *MockSMSRecordRepository) UpdateReport(ctx context.Context, provider string, r domain.SMSReceipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReport", ctx, provider, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReport indicates an expected call of UpdateReport.
+func (mr *MockSMSRecordRepositoryMockRecorder) UpdateReport(ctx, provider, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReport", reflect.TypeOf((*MockSMSRecordRepository)(nil).UpdateReport), ctx, provider, r)
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+// ErrPATNotFound 访问令牌不存在
+var ErrPATNotFound = dao.ErrDataNotFound
+
+//go:generate mockgen -source=./pat.go -package=repomocks -destination=mocks/pat.mock.go PATRepository
+type PATRepository interface {
+	// Create hash 是令牌的哈希值，令牌本身不落库
+	Create(ctx context.Context, t domain.PersonalAccessToken, hash string) (int64, error)
+	FindByHash(ctx context.Context, hash string) (domain.PersonalAccessToken, error)
+	List(ctx context.Context, uid int64) ([]domain.PersonalAccessToken, error)
+	Count(ctx context.Context, uid int64) (int64, error)
+	Delete(ctx context.Context, uid, id int64) error
+}
+
+type patRepository struct {
+	dao dao.PATDAO
+}
+
+func NewPATRepository(dao dao.PATDAO) PATRepository {
+	return &patRepository{dao: dao}
+}
+
+func (repo *patRepository) Create(ctx context.Context, t domain.PersonalAccessToken, hash string) (int64, error) {
+	return repo.dao.Insert(ctx, dao.PersonalAccessToken{
+		Uid:       t.Uid,
+		Name:      t.Name,
+		TokenHash: hash,
+		Scopes: strings.Join(slice.Map(t.Scopes, func(idx int, src domain.Scope) string {
+			return string(src)
+		}), ","),
+		ExpireAt: t.Expiration.UnixMilli(),
+	})
+}
+
+func (repo *patRepository) FindByHash(ctx context.Context, hash string) (domain.PersonalAccessToken, error) {
+	t, err := repo.dao.FindByHash(ctx, hash)
+	if err != nil {
+		return domain.PersonalAccessToken{}, err
+	}
+	return repo.toDomain(t), nil
+}
+
+func (repo *patRepository) List(ctx context.Context, uid int64) ([]domain.PersonalAccessToken, error) {
+	ts, err := repo.dao.ListByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ts, func(idx int, src dao.PersonalAccessToken) domain.PersonalAccessToken {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *patRepository) Count(ctx context.Context, uid int64) (int64, error) {
+	return repo.dao.CountByUid(ctx, uid)
+}
+
+func (repo *patRepository) Delete(ctx context.Context, uid, id int64) error {
+	return repo.dao.Delete(ctx, uid, id)
+}
+
+func (repo *patRepository) toDomain(t dao.PersonalAccessToken) domain.PersonalAccessToken {
+	var scopes []domain.Scope
+	if t.Scopes != "" {
+		scopes = slice.Map(strings.Split(t.Scopes, ","), func(idx int, src string) domain.Scope {
+			return domain.Scope(src)
+		})
+	}
+	return domain.PersonalAccessToken{
+		Id:         t.Id,
+		Uid:        t.Uid,
+		Name:       t.Name,
+		Scopes:     scopes,
+		Expiration: time.UnixMilli(t.ExpireAt),
+		Ctime:      time.UnixMilli(t.Ctime),
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+)
+
+var (
+	// ErrQRLoginTicketNotFound 票据不存在或者已经过期
+	ErrQRLoginTicketNotFound = errors.New("扫码登录的票据不存在或者已经过期")
+	ErrQRLoginStatusMismatch = cache.ErrQRLoginStatusMismatch
+)
+
+//go:generate mockgen -source=./qr_login.go -package=repomocks -destination=mocks/qr_login.mock.go QRLoginRepository
+type QRLoginRepository interface {
+	Create(ctx context.Context, t domain.QRLoginTicket, expiration time.Duration) error
+	Find(ctx context.Context, ticket string) (domain.QRLoginTicket, error)
+	Transit(ctx context.Context, ticket string, from, to domain.QRLoginStatus, uid int64, bind bool) error
+	Consume(ctx context.Context, ticket string) (int64, error)
+}
+
+type CachedQRLoginRepository struct {
+	cache cache.QRLoginCache
+}
+
+func NewCachedQRLoginRepository(c cache.QRLoginCache) QRLoginRepository {
+	return &CachedQRLoginRepository{cache: c}
+}
+
+func (repo *CachedQRLoginRepository) Create(ctx context.Context, t domain.QRLoginTicket, expiration time.Duration) error {
+	return repo.cache.Set(ctx, t, expiration)
+}
+
+func (repo *CachedQRLoginRepository) Find(ctx context.Context, ticket string) (domain.QRLoginTicket, error) {
+	t, err := repo.cache.Get(ctx, ticket)
+	if errors.Is(err, cache.ErrKeyNotExist) {
+		return domain.QRLoginTicket{}, ErrQRLoginTicketNotFound
+	}
+	return t, err
+}
+
+func (repo *CachedQRLoginRepository) Transit(ctx context.Context, ticket string,
+	from, to domain.QRLoginStatus, uid int64, bind bool) error {
+	err := repo.cache.Transit(ctx, ticket, from, to, uid, bind)
+	if errors.Is(err, cache.ErrKeyNotExist) {
+		return ErrQRLoginTicketNotFound
+	}
+	return err
+}
+
+func (repo *CachedQRLoginRepository) Consume(ctx context.Context, ticket string) (int64, error) {
+	uid, err := repo.cache.Consume(ctx, ticket)
+	if errors.Is(err, cache.ErrKeyNotExist) {
+		return 0, ErrQRLoginTicketNotFound
+	}
+	return uid, err
+}
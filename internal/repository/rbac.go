@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+//go:generate mockgen -source=./rbac.go -package=repomocks -destination=mocks/rbac.mock.go RBACRepository
+type RBACRepository interface {
+	FindRoles(ctx context.Context, uid int64) ([]domain.Role, error)
+	// AddRole 返回值表示是不是新授予的角色，用户已经有这个角色的时候返回 false
+	AddRole(ctx context.Context, operator, uid int64, role domain.Role) (bool, error)
+	RemoveRole(ctx context.Context, uid int64, role domain.Role) error
+
+	AddAuditLog(ctx context.Context, l domain.AuditLog) error
+	ListAuditLogs(ctx context.Context, offset, limit int) ([]domain.AuditLog, error)
+}
+
+type rbacRepository struct {
+	dao dao.RBACDAO
+}
+
+func NewRBACRepository(dao dao.RBACDAO) RBACRepository {
+	return &rbacRepository{dao: dao}
+}
+
+func (repo *rbacRepository) FindRoles(ctx context.Context, uid int64) ([]domain.Role, error) {
+	rs, err := repo.dao.FindRoles(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(rs, func(idx int, src dao.UserRole) domain.Role {
+		return domain.Role(src.Role)
+	}), nil
+}
+
+func (repo *rbacRepository) AddRole(ctx context.Context, operator, uid int64, role domain.Role) (bool, error) {
+	return repo.dao.InsertRole(ctx, dao.UserRole{
+		Uid:      uid,
+		Role:     string(role),
+		Operator: operator,
+	})
+}
+
+func (repo *rbacRepository) RemoveRole(ctx context.Context, uid int64, role domain.Role) error {
+	return repo.dao.DeleteRole(ctx, uid, string(role))
+}
+
+func (repo *rbacRepository) AddAuditLog(ctx context.Context, l domain.AuditLog) error {
+	return repo.dao.InsertAuditLog(ctx, dao.AuditLog{
+		OperatorId: l.OperatorId,
+		Action:     string(l.Action),
+		TargetId:   l.TargetId,
+		Detail:     l.Detail,
+	})
+}
+
+func (repo *rbacRepository) ListAuditLogs(ctx context.Context, offset, limit int) ([]domain.AuditLog, error) {
+	ls, err := repo.dao.ListAuditLogs(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ls, func(idx int, src dao.AuditLog) domain.AuditLog {
+		return domain.AuditLog{
+			Id:         src.Id,
+			OperatorId: src.OperatorId,
+			Action:     domain.AuditAction(src.Action),
+			TargetId:   src.TargetId,
+			Detail:     src.Detail,
+			Ctime:      time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/ecodeclub/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+	"webook/pkg/phonex"
+)
+
+//go:generate mockgen -source=./sms_record.go -package=repomocks -destination=mocks/sms_record.mock.go SMSRecordRepository
+type SMSRecordRepository interface {
+	// Add 记录里面的 Number 是完整的号码，保存的时候会打码
+	Add(ctx context.Context, rs ...domain.SMSRecord) error
+	// UpdateReport 根据状态报告更新 provider 发出去的短信
+	UpdateReport(ctx context.Context, provider string, r domain.SMSReceipt) error
+	// FindByNumber number 是 E.164 格式的完整号码，查出来的记录里面是打码之后的号码
+	FindByNumber(ctx context.Context, number string, offset, limit int) ([]domain.SMSRecord, error)
+}
+
+type smsRecordRepository struct {
+	dao dao.SMSRecordDAO
+	// hashKey 计算号码 HMAC 的密钥，手机号的空间太小了，不加密钥的哈希很容易被穷举出来
+	hashKey []byte
+}
+
+func NewSMSRecordRepository(dao dao.SMSRecordDAO, hashKey []byte) SMSRecordRepository {
+	return &smsRecordRepository{dao: dao, hashKey: hashKey}
+}
+
+func (repo *smsRecordRepository) Add(ctx context.Context, rs ...domain.SMSRecord) error {
+	if len(rs) == 0 {
+		return nil
+	}
+	return repo.dao.Insert(ctx, slice.Map(rs, func(idx int, src domain.SMSRecord) dao.SMSRecord {
+		return dao.SMSRecord{
+			Provider:   src.Provider,
+			MessageId:  src.MessageId,
+			TplId:      src.TplId,
+			Number:     phonex.Mask(src.Number),
+			NumberHash: repo.hash(src.Number),
+			Latency:    src.Latency.Milliseconds(),
+			Status:     src.Status.ToUint8(),
+			Err:        src.Err,
+		}
+	}))
+}
+
+func (repo *smsRecordRepository) UpdateReport(ctx context.Context, provider string, r domain.SMSReceipt) error {
+	status := domain.SMSRecordStatusUndelivered
+	if r.Delivered {
+		status = domain.SMSRecordStatusDelivered
+	}
+	var reportTime int64
+	if !r.ReportTime.IsZero() {
+		reportTime = r.ReportTime.UnixMilli()
+	}
+	return repo.dao.UpdateReport(ctx, provider, r.MessageId, status.ToUint8(), r.Code, r.Msg, reportTime)
+}
+
+func (repo *smsRecordRepository) FindByNumber(ctx context.Context, number string,
+	offset, limit int) ([]domain.SMSRecord, error) {
+	rs, err := repo.dao.FindByNumberHash(ctx, repo.hash(number), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(rs, func(idx int, src dao.SMSRecord) domain.SMSRecord {
+		return repo.toDomain(src)
+	}), nil
+}
+
+func (repo *smsRecordRepository) hash(number string) string {
+	h := hmac.New(sha256.New, repo.hashKey)
+	h.Write([]byte(number))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (repo *smsRecordRepository) toDomain(r dao.SMSRecord) domain.SMSRecord {
+	res := domain.SMSRecord{
+		Id:         r.Id,
+		Provider:   r.Provider,
+		TplId:      r.TplId,
+		Number:     r.Number,
+		MessageId:  r.MessageId,
+		Latency:    time.Duration(r.Latency) * time.Millisecond,
+		Status:     domain.SMSRecordStatus(r.Status),
+		Err:        r.Err,
+		ReportCode: r.ReportCode,
+		ReportMsg:  r.ReportMsg,
+		Ctime:      time.UnixMilli(r.Ctime),
+		Utime:      time.UnixMilli(r.Utime),
+	}
+	if r.ReportTime > 0 {
+		res.ReportTime = time.UnixMilli(r.ReportTime)
+	}
+	return res
+}
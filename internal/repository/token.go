@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/repository/cache"
+)
+
+// ErrTokenNotFound 令牌不存在、已经过期或者已经被使用过了
+var ErrTokenNotFound = errors.New("令牌不存在或者已经失效")
+
+// TokenRepository 一次性令牌的存储
+type TokenRepository interface {
+	// Store 存储令牌，以及令牌关联的数据
+	Store(ctx context.Context, biz string, token string, val string, expiration time.Duration) error
+	// Consume 使用令牌，返回令牌关联的数据。令牌使用之后就失效了
+	Consume(ctx context.Context, biz string, token string) (string, error)
+}
+
+// CachedTokenRepository 实现 TokenRepository 接口
+type CachedTokenRepository struct {
+	cache cache.TokenCache
+}
+
+func NewCachedTokenRepository(c cache.TokenCache) TokenRepository {
+	return &CachedTokenRepository{
+		cache: c,
+	}
+}
+
+func (repo *CachedTokenRepository) Store(ctx context.Context, biz string, token string, val string, expiration time.Duration) error {
+	return repo.cache.Set(ctx, biz, token, val, expiration)
+}
+
+func (repo *CachedTokenRepository) Consume(ctx context.Context, biz string, token string) (string, error) {
+	val, err := repo.cache.GetDel(ctx, biz, token)
+	if errors.Is(err, cache.ErrKeyNotExist) {
+		return "", ErrTokenNotFound
+	}
+	return val, err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"github.com/ecodeclub/ekit/slice"
 	"time"
 	"webook/internal/domain" // 引入domain包，定义了User等业务模型
 	"webook/internal/repository/cache"
@@ -11,8 +12,11 @@ import (
 )
 
 var (
-	ErrUserDuplicate = dao.ErrUserDuplicate
-	ErrUserNotFound  = dao.ErrDataNotFound
+	ErrUserDuplicate       = dao.ErrUserDuplicate
+	ErrUserDuplicateEmail  = dao.ErrUserDuplicateEmail
+	ErrUserDuplicatePhone  = dao.ErrUserDuplicatePhone
+	ErrUserDuplicateHandle = dao.ErrUserDuplicateHandle
+	ErrUserNotFound        = dao.ErrDataNotFound
 )
 
 // UserRepository 是一个用于操作用户数据的接口，主要包括用户的创建、查找等操作
@@ -22,8 +26,13 @@ type UserRepository interface {
 	FindByPhone(ctx context.Context, phone string) (domain.User, error)
 	FindByEmail(ctx context.Context, email string) (domain.User, error)
 	FindById(ctx context.Context, id int64) (domain.User, error)
+	FindByHandle(ctx context.Context, handle string) (domain.User, error)
 	// Update 更新数据，只有非 0 值才会更新
 	Update(ctx context.Context, u domain.User) error
+	// Anonymize 抹掉用户的个人信息，注销账号的时候使用
+	Anonymize(ctx context.Context, id int64) error
+	UpdateBanned(ctx context.Context, id int64, banned bool) error
+	Search(ctx context.Context, keyword string, offset, limit int) ([]domain.User, error)
 }
 
 // CachedUserRepository 实现 UserRepository 接口
@@ -65,6 +74,11 @@ func (ur *CachedUserRepository) FindByEmail(ctx context.Context,
 	return ur.entityToDomain(u), err
 }
 
+func (ur *CachedUserRepository) FindByHandle(ctx context.Context, handle string) (domain.User, error) {
+	u, err := ur.dao.FindByHandle(ctx, handle)
+	return ur.entityToDomain(u), err
+}
+
 func (ur *CachedUserRepository) FindById(ctx context.Context, id int64) (domain.User, error) {
 	// 首先尝试从缓存中获取用户数据
 	u, err := ur.cache.Get(ctx, id)
@@ -101,6 +115,32 @@ func (ur *CachedUserRepository) Update(ctx context.Context, u domain.User) error
 	return ur.cache.Delete(ctx, u.Id)
 }
 
+func (ur *CachedUserRepository) Anonymize(ctx context.Context, id int64) error {
+	err := ur.dao.Anonymize(ctx, id)
+	if err != nil {
+		return err
+	}
+	return ur.cache.Delete(ctx, id)
+}
+
+func (ur *CachedUserRepository) UpdateBanned(ctx context.Context, id int64, banned bool) error {
+	err := ur.dao.UpdateBanned(ctx, id, banned)
+	if err != nil {
+		return err
+	}
+	return ur.cache.Delete(ctx, id)
+}
+
+func (ur *CachedUserRepository) Search(ctx context.Context, keyword string, offset, limit int) ([]domain.User, error) {
+	us, err := ur.dao.Search(ctx, keyword, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(us, func(idx int, src dao.User) domain.User {
+		return ur.entityToDomain(src)
+	}), nil
+}
+
 // domainToEntity 将领域模型（domain.User）转换为数据库实体（dao.User）
 func (ur *CachedUserRepository) domainToEntity(u domain.User) dao.User {
 	return dao.User{
@@ -125,8 +165,22 @@ func (ur *CachedUserRepository) domainToEntity(u domain.User) dao.User {
 			String: u.AboutMe,
 			Valid:  u.AboutMe != "",
 		},
-		Password: u.Password,
+		Handle: sql.NullString{
+			String: u.Handle,
+			Valid:  u.Handle != "",
+		},
+		HandleUtime: ur.toMilli(u.HandleUtime),
+		Avatar:      u.Avatar,
+		Password:    u.Password,
+	}
+}
+
+// toMilli 零值的时间转成 0，这样更新的时候会被忽略
+func (ur *CachedUserRepository) toMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
 	}
+	return t.UnixMilli()
 }
 
 // entityToDomain 将数据库实体（dao.User）转换为领域模型（domain.User）
@@ -138,6 +192,10 @@ func (ur *CachedUserRepository) entityToDomain(ue dao.User) domain.User {
 	if ue.Birthday.Valid {
 		birthday = time.UnixMilli(ue.Birthday.Int64)
 	}
+	var handleUtime time.Time
+	if ue.HandleUtime > 0 {
+		handleUtime = time.UnixMilli(ue.HandleUtime)
+	}
 	return domain.User{
 		Id:       ue.Id,           // 用户 ID
 		Email:    ue.Email.String, // 用户邮箱（确保处理数据库 NULL 值）
@@ -147,5 +205,10 @@ func (ur *CachedUserRepository) entityToDomain(ue dao.User) domain.User {
 		AboutMe:  ue.AboutMe.String,
 		Birthday: birthday,
 		Ctime:    time.UnixMilli(ue.Ctime),
+
+		Handle:      ue.Handle.String,
+		HandleUtime: handleUtime,
+		Avatar:      ue.Avatar,
+		Banned:      ue.Banned,
 	}
 }
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+var (
+	ErrAccountDeletionNotFound = errors.New("没有处于冷静期的注销申请")
+	ErrDataExportNotFound      = errors.New("导出任务不存在")
+)
+
+const (
+	// deletionCoolOff 注销申请的冷静期
+	deletionCoolOff = time.Hour * 24 * 7
+	// accountBatchSize 定时任务每一批处理的数量
+	accountBatchSize = 100
+)
+
+// SessionRevoker 让用户的登录会话失效
+// 注销账号的时候需要把用户踢下线，ijwt.Handler 就满足这个接口
+type SessionRevoker interface {
+	RevokeSessions(ctx context.Context, uid int64, exceptSsid string) error
+}
+
+//go:generate mockgen -source=./account.go -package=svcmocks -destination=mocks/account.mock.go AccountService
+type AccountService interface {
+	// RequestDeletion 申请注销账号，冷静期结束之后才会真的注销
+	// 冷静期内重复申请，冷静期会重新计算
+	RequestDeletion(ctx context.Context, uid int64) (domain.AccountDeletion, error)
+	// CancelDeletion 在冷静期内撤销注销申请
+	CancelDeletion(ctx context.Context, uid int64) error
+	// ExecuteDueDeletions 注销所有冷静期已经结束的账号，由定时任务调用
+	ExecuteDueDeletions(ctx context.Context) error
+
+	// RequestExport 申请导出个人数据，返回导出任务的 ID
+	// 导出本身是异步的，由定时任务来完成
+	RequestExport(ctx context.Context, uid int64) (int64, error)
+	// GetExport 查询导出任务，只能查询自己的
+	GetExport(ctx context.Context, uid, id int64) (domain.DataExport, error)
+	// ExecutePendingExports 执行所有等待中的导出任务，由定时任务调用
+	ExecutePendingExports(ctx context.Context) error
+}
+
+type accountService struct {
+	repo     repository.AccountRepository
+	userRepo repository.UserRepository
+	artRepo  repository.ArticleRepository
+	intrSvc  intrv1.InteractiveServiceClient
+	revoker  SessionRevoker
+	l        logger.Logger
+	// 导出的压缩包存放的目录
+	exportDir string
+	coolOff   time.Duration
+}
+
+func NewAccountService(repo repository.AccountRepository,
+	userRepo repository.UserRepository,
+	artRepo repository.ArticleRepository,
+	intrSvc intrv1.InteractiveServiceClient,
+	revoker SessionRevoker,
+	l logger.Logger, exportDir string) AccountService {
+	return &accountService{
+		repo:      repo,
+		userRepo:  userRepo,
+		artRepo:   artRepo,
+		intrSvc:   intrSvc,
+		revoker:   revoker,
+		l:         l,
+		exportDir: exportDir,
+		coolOff:   deletionCoolOff,
+	}
+}
+
+func (svc *accountService) RequestDeletion(ctx context.Context, uid int64) (domain.AccountDeletion, error) {
+	d := domain.AccountDeletion{
+		Uid:       uid,
+		Status:    domain.AccountDeletionStatusPending,
+		ExecuteAt: time.Now().Add(svc.coolOff),
+	}
+	err := svc.repo.SaveDeletion(ctx, d)
+	return d, err
+}
+
+func (svc *accountService) CancelDeletion(ctx context.Context, uid int64) error {
+	err := svc.repo.CancelDeletion(ctx, uid)
+	if errors.Is(err, repository.ErrAccountRecordNotFound) {
+		return ErrAccountDeletionNotFound
+	}
+	return err
+}
+
+func (svc *accountService) ExecuteDueDeletions(ctx context.Context) error {
+	for {
+		ds, err := svc.repo.FindDueDeletions(ctx, time.Now(), accountBatchSize)
+		if err != nil {
+			return err
+		}
+		done := 0
+		for _, d := range ds {
+			// 某个用户失败了不影响别的用户，它依旧是冷静期状态，下一次调度的时候会重试
+			err = svc.executeDeletion(ctx, d.Uid)
+			if err != nil {
+				svc.l.Error("注销账号失败", logger.Int64("uid", d.Uid), logger.Error(err))
+				continue
+			}
+			done++
+		}
+		// 最后一批了，或者这一批全部失败了，避免死循环
+		if len(ds) < accountBatchSize || done == 0 {
+			return nil
+		}
+	}
+}
+
+// executeDeletion 注销一个账号，每一个步骤都是幂等的，所以失败了可以整体重试
+func (svc *accountService) executeDeletion(ctx context.Context, uid int64) error {
+	err := svc.userRepo.Anonymize(ctx, uid)
+	if err != nil {
+		return fmt.Errorf("抹除用户信息失败 %w", err)
+	}
+	err = svc.revoker.RevokeSessions(ctx, uid, "")
+	if err != nil {
+		return fmt.Errorf("踢出登录会话失败 %w", err)
+	}
+	err = svc.artRepo.SyncStatusByAuthor(ctx, uid, domain.ArticleStatusPrivate)
+	if err != nil {
+		return fmt.Errorf("撤回文章失败 %w", err)
+	}
+	_, err = svc.intrSvc.DeleteUserData(ctx, &intrv1.DeleteUserDataRequest{Uid: uid})
+	if err != nil {
+		return fmt.Errorf("清理点赞收藏失败 %w", err)
+	}
+	return svc.repo.UpdateDeletionStatus(ctx, uid, domain.AccountDeletionStatusDone)
+}
+
+func (svc *accountService) RequestExport(ctx context.Context, uid int64) (int64, error) {
+	return svc.repo.CreateExport(ctx, domain.DataExport{
+		Uid:    uid,
+		Status: domain.DataExportStatusPending,
+	})
+}
+
+func (svc *accountService) GetExport(ctx context.Context, uid, id int64) (domain.DataExport, error) {
+	e, err := svc.repo.FindExport(ctx, id)
+	if errors.Is(err, repository.ErrAccountRecordNotFound) {
+		return domain.DataExport{}, ErrDataExportNotFound
+	}
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	// 不是自己的导出任务，当成不存在来处理，避免泄露别人的导出任务 ID
+	if e.Uid != uid {
+		return domain.DataExport{}, ErrDataExportNotFound
+	}
+	return e, nil
+}
+
+func (svc *accountService) ExecutePendingExports(ctx context.Context) error {
+	for {
+		es, err := svc.repo.FindPendingExports(ctx, accountBatchSize)
+		if err != nil {
+			return err
+		}
+		advanced := 0
+		for _, e := range es {
+			status := domain.DataExportStatusDone
+			path, err := svc.export(ctx, e)
+			if err != nil {
+				svc.l.Error("导出个人数据失败",
+					logger.Int64("id", e.Id),
+					logger.Int64("uid", e.Uid),
+					logger.Error(err))
+				status = domain.DataExportStatusFailed
+			}
+			err = svc.repo.UpdateExport(ctx, e.Id, status, path)
+			if err != nil {
+				// 状态没有更新成功，那么下一次还会重新导出一遍，覆盖掉原本的文件
+				svc.l.Error("更新导出任务状态失败", logger.Int64("id", e.Id), logger.Error(err))
+				continue
+			}
+			advanced++
+		}
+		// 最后一批了，或者这一批的状态一个都没有更新成功，再查还是同一批，避免死循环
+		if len(es) < accountBatchSize || advanced == 0 {
+			return nil
+		}
+	}
+}
+
+// export 把用户的个人数据打包成一个 zip 文件，返回文件路径
+func (svc *accountService) export(ctx context.Context, e domain.DataExport) (string, error) {
+	data, err := svc.collect(ctx, e.Uid)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(svc.exportDir, 0o755)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(svc.exportDir, fmt.Sprintf("%d_%d.zip", e.Uid, e.Id))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for _, entry := range data {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			return "", err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(entry.val); err != nil {
+			return "", err
+		}
+	}
+	return path, zw.Close()
+}
+
+type exportEntry struct {
+	name string
+	val  any
+}
+
+// collect 收集需要导出的数据，每一项会是压缩包里面的一个 JSON 文件
+func (svc *accountService) collect(ctx context.Context, uid int64) ([]exportEntry, error) {
+	u, err := svc.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	arts, err := svc.listArticles(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	likes, err := svc.listUserRecords(func(offset, limit int64) ([]*intrv1.UserBizRecord, error) {
+		resp, err := svc.intrSvc.GetUserLikes(ctx, &intrv1.GetUserLikesRequest{
+			Uid: uid, Offset: offset, Limit: limit,
+		})
+		return resp.GetRecords(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	collects, err := svc.listUserRecords(func(offset, limit int64) ([]*intrv1.UserBizRecord, error) {
+		resp, err := svc.intrSvc.GetUserCollects(ctx, &intrv1.GetUserCollectsRequest{
+			Uid: uid, Offset: offset, Limit: limit,
+		})
+		return resp.GetRecords(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []exportEntry{
+		// 注意不能把密码导出去
+		{name: "profile.json", val: exportProfile{
+			Id:       u.Id,
+			Email:    u.Email,
+			Phone:    u.Phone,
+			Nickname: u.Nickname,
+			AboutMe:  u.AboutMe,
+			Birthday: u.Birthday.Format(time.DateOnly),
+			Ctime:    u.Ctime.Format(time.DateTime),
+		}},
+		{name: "articles.json", val: arts},
+		{name: "likes.json", val: likes},
+		{name: "collections.json", val: collects},
+	}, nil
+}
+
+func (svc *accountService) listArticles(ctx context.Context, uid int64) ([]exportArticle, error) {
+	res := make([]exportArticle, 0, accountBatchSize)
+	for offset := 0; ; offset += accountBatchSize {
+		arts, err := svc.artRepo.List(ctx, uid, offset, accountBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, art := range arts {
+			res = append(res, exportArticle{
+				Id:      art.Id,
+				Title:   art.Title,
+				Content: art.Content,
+				Status:  art.Status.ToUint8(),
+				Ctime:   art.Ctime.Format(time.DateTime),
+				Utime:   art.Utime.Format(time.DateTime),
+			})
+		}
+		if len(arts) < accountBatchSize {
+			return res, nil
+		}
+	}
+}
+
+func (svc *accountService) listUserRecords(fetch func(offset, limit int64) ([]*intrv1.UserBizRecord, error)) ([]exportBizRecord, error) {
+	res := make([]exportBizRecord, 0, accountBatchSize)
+	for offset := int64(0); ; offset += accountBatchSize {
+		records, err := fetch(offset, accountBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			res = append(res, exportBizRecord{
+				Biz:   r.GetBiz(),
+				BizId: r.GetBizId(),
+				Cid:   r.GetCid(),
+				Ctime: time.UnixMilli(r.GetCtime()).Format(time.DateTime),
+			})
+		}
+		if len(records) < accountBatchSize {
+			return res, nil
+		}
+	}
+}
+
+type exportProfile struct {
+	Id       int64  `json:"id"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Nickname string `json:"nickname"`
+	AboutMe  string `json:"about_me"`
+	Birthday string `json:"birthday"`
+	Ctime    string `json:"ctime"`
+}
+
+type exportArticle struct {
+	Id      int64  `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Status  uint8  `json:"status"`
+	Ctime   string `json:"ctime"`
+	Utime   string `json:"utime"`
+}
+
+type exportBizRecord struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"biz_id"`
+	// 收藏夹 ID，只有收藏记录才有
+	Cid   int64  `json:"cid,omitempty"`
+	Ctime string `json:"ctime"`
+}
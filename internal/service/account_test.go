@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	intrmocks "webook/api/proto/gen/intr/v1/mocks"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+func TestAccountService_ExecuteDueDeletions(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (repository.AccountRepository,
+			repository.UserRepository, repository.ArticleRepository,
+			intrv1.InteractiveServiceClient, SessionRevoker)
+
+		wantErr error
+	}{
+		{
+			name: "注销成功",
+			mock: func(ctrl *gomock.Controller) (repository.AccountRepository,
+				repository.UserRepository, repository.ArticleRepository,
+				intrv1.InteractiveServiceClient, SessionRevoker) {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				intrSvc := intrmocks.NewMockInteractiveServiceClient(ctrl)
+				revoker := svcmocks.NewMockSessionRevoker(ctrl)
+				repo.EXPECT().FindDueDeletions(gomock.Any(), gomock.Any(), accountBatchSize).
+					Return([]domain.AccountDeletion{{Uid: 1}}, nil)
+				userRepo.EXPECT().Anonymize(gomock.Any(), int64(1)).Return(nil)
+				revoker.EXPECT().RevokeSessions(gomock.Any(), int64(1), "").Return(nil)
+				artRepo.EXPECT().SyncStatusByAuthor(gomock.Any(), int64(1), domain.ArticleStatusPrivate).
+					Return(nil)
+				intrSvc.EXPECT().DeleteUserData(gomock.Any(), &intrv1.DeleteUserDataRequest{Uid: 1}).
+					Return(&intrv1.DeleteUserDataResponse{}, nil)
+				repo.EXPECT().UpdateDeletionStatus(gomock.Any(), int64(1), domain.AccountDeletionStatusDone).
+					Return(nil)
+				return repo, userRepo, artRepo, intrSvc, revoker
+			},
+		},
+		{
+			name: "部分用户注销失败",
+			mock: func(ctrl *gomock.Controller) (repository.AccountRepository,
+				repository.UserRepository, repository.ArticleRepository,
+				intrv1.InteractiveServiceClient, SessionRevoker) {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				intrSvc := intrmocks.NewMockInteractiveServiceClient(ctrl)
+				revoker := svcmocks.NewMockSessionRevoker(ctrl)
+				repo.EXPECT().FindDueDeletions(gomock.Any(), gomock.Any(), accountBatchSize).
+					Return([]domain.AccountDeletion{{Uid: 1}, {Uid: 2}}, nil)
+				// 1 清理点赞收藏失败，保持冷静期状态，下一次重试
+				userRepo.EXPECT().Anonymize(gomock.Any(), int64(1)).Return(nil)
+				revoker.EXPECT().RevokeSessions(gomock.Any(), int64(1), "").Return(nil)
+				artRepo.EXPECT().SyncStatusByAuthor(gomock.Any(), int64(1), domain.ArticleStatusPrivate).
+					Return(nil)
+				intrSvc.EXPECT().DeleteUserData(gomock.Any(), &intrv1.DeleteUserDataRequest{Uid: 1}).
+					Return(nil, errors.New("rpc 错误"))
+
+				userRepo.EXPECT().Anonymize(gomock.Any(), int64(2)).Return(nil)
+				revoker.EXPECT().RevokeSessions(gomock.Any(), int64(2), "").Return(nil)
+				artRepo.EXPECT().SyncStatusByAuthor(gomock.Any(), int64(2), domain.ArticleStatusPrivate).
+					Return(nil)
+				intrSvc.EXPECT().DeleteUserData(gomock.Any(), &intrv1.DeleteUserDataRequest{Uid: 2}).
+					Return(&intrv1.DeleteUserDataResponse{}, nil)
+				repo.EXPECT().UpdateDeletionStatus(gomock.Any(), int64(2), domain.AccountDeletionStatusDone).
+					Return(nil)
+				return repo, userRepo, artRepo, intrSvc, revoker
+			},
+		},
+		{
+			name: "查询注销申请失败",
+			mock: func(ctrl *gomock.Controller) (repository.AccountRepository,
+				repository.UserRepository, repository.ArticleRepository,
+				intrv1.InteractiveServiceClient, SessionRevoker) {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				repo.EXPECT().FindDueDeletions(gomock.Any(), gomock.Any(), accountBatchSize).
+					Return(nil, errors.New("mock db 错误"))
+				return repo, repomocks.NewMockUserRepository(ctrl),
+					repomocks.NewMockArticleRepository(ctrl),
+					intrmocks.NewMockInteractiveServiceClient(ctrl),
+					svcmocks.NewMockSessionRevoker(ctrl)
+			},
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, userRepo, artRepo, intrSvc, revoker := tc.mock(ctrl)
+			svc := NewAccountService(repo, userRepo, artRepo, intrSvc, revoker,
+				logger.NewZapLogger(zap.NewNop()), t.TempDir())
+			err := svc.ExecuteDueDeletions(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestAccountService_ExecutePendingExports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockAccountRepository(ctrl)
+	userRepo := repomocks.NewMockUserRepository(ctrl)
+	es := make([]domain.DataExport, 0, accountBatchSize)
+	for i := 0; i < accountBatchSize; i++ {
+		es = append(es, domain.DataExport{Id: int64(i + 1), Uid: int64(i + 1)})
+	}
+	// 满满的一批，但是状态一个都没有更新成功，只能处理一次，不然会一直重复导出同一批
+	repo.EXPECT().FindPendingExports(gomock.Any(), accountBatchSize).Return(es, nil).Times(1)
+	userRepo.EXPECT().FindById(gomock.Any(), gomock.Any()).
+		Return(domain.User{}, errors.New("mock db 错误")).Times(accountBatchSize)
+	repo.EXPECT().UpdateExport(gomock.Any(), gomock.Any(), domain.DataExportStatusFailed, "").
+		Return(errors.New("mock db 错误")).Times(accountBatchSize)
+	svc := NewAccountService(repo, userRepo, repomocks.NewMockArticleRepository(ctrl),
+		intrmocks.NewMockInteractiveServiceClient(ctrl), svcmocks.NewMockSessionRevoker(ctrl),
+		logger.NewZapLogger(zap.NewNop()), t.TempDir())
+	assert.NoError(t, svc.ExecutePendingExports(context.Background()))
+}
+
+func TestAccountService_GetExport(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) repository.AccountRepository
+
+		uid int64
+		id  int64
+
+		wantErr    error
+		wantExport domain.DataExport
+	}{
+		{
+			name: "查询成功",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				repo.EXPECT().FindExport(gomock.Any(), int64(2)).
+					Return(domain.DataExport{Id: 2, Uid: 1, Status: domain.DataExportStatusDone}, nil)
+				return repo
+			},
+			uid:        1,
+			id:         2,
+			wantExport: domain.DataExport{Id: 2, Uid: 1, Status: domain.DataExportStatusDone},
+		},
+		{
+			name: "别人的导出任务",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				repo.EXPECT().FindExport(gomock.Any(), int64(2)).
+					Return(domain.DataExport{Id: 2, Uid: 3, Ctime: time.Now()}, nil)
+				return repo
+			},
+			uid:     1,
+			id:      2,
+			wantErr: ErrDataExportNotFound,
+		},
+		{
+			name: "导出任务不存在",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				repo.EXPECT().FindExport(gomock.Any(), int64(2)).
+					Return(domain.DataExport{}, repository.ErrAccountRecordNotFound)
+				return repo
+			},
+			uid:     1,
+			id:      2,
+			wantErr: ErrDataExportNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewAccountService(tc.mock(ctrl), nil, nil, nil, nil,
+				logger.NewZapLogger(zap.NewNop()), t.TempDir())
+			e, err := svc.GetExport(context.Background(), tc.uid, tc.id)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantExport, e)
+		})
+	}
+}
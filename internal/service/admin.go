@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+	"webook/pkg/phonex"
+)
+
+// AdminService 管理后台的操作，所有修改类的操作都会记录审计日志
+// 权限校验在 web 层的中间件里面完成，这里不再校验
+//
+//go:generate mockgen -source=./admin.go -package=svcmocks -destination=mocks/admin.mock.go AdminService
+type AdminService interface {
+	SearchUsers(ctx context.Context, keyword string, offset, limit int) ([]domain.User, error)
+	// BanUser 封禁用户，用户所有的登录会话都会失效，并且不能再登录
+	BanUser(ctx context.Context, operator, uid int64, reason string) error
+	UnbanUser(ctx context.Context, operator, uid int64, reason string) error
+	// UnpublishArticle 强制下架文章，文章变成仅作者可见
+	UnpublishArticle(ctx context.Context, operator, aid int64, reason string) error
+	ListAuditLogs(ctx context.Context, offset, limit int) ([]domain.AuditLog, error)
+}
+
+type adminService struct {
+	userRepo repository.UserRepository
+	artRepo  repository.ArticleRepository
+	rbacRepo repository.RBACRepository
+	revoker  SessionRevoker
+	l        logger.Logger
+}
+
+func NewAdminService(userRepo repository.UserRepository,
+	artRepo repository.ArticleRepository,
+	rbacRepo repository.RBACRepository,
+	revoker SessionRevoker,
+	l logger.Logger) AdminService {
+	return &adminService{
+		userRepo: userRepo,
+		artRepo:  artRepo,
+		rbacRepo: rbacRepo,
+		revoker:  revoker,
+		l:        l,
+	}
+}
+
+func (svc *adminService) SearchUsers(ctx context.Context, keyword string, offset, limit int) ([]domain.User, error) {
+	// 手机号保存的是 E.164 格式，客服输入的一般是不带区号的号码
+	if phone, err := phonex.Normalize(keyword, domain.DefaultPhoneRegion); err == nil {
+		keyword = phone
+	}
+	return svc.userRepo.Search(ctx, keyword, offset, limit)
+}
+
+func (svc *adminService) BanUser(ctx context.Context, operator, uid int64, reason string) error {
+	// 确认用户存在
+	_, err := svc.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	err = svc.userRepo.UpdateBanned(ctx, uid, true)
+	if err != nil {
+		return err
+	}
+	// 踢下线
+	err = svc.revoker.RevokeSessions(ctx, uid, "")
+	if err != nil {
+		return err
+	}
+	recordAudit(ctx, svc.rbacRepo, svc.l, domain.AuditLog{
+		OperatorId: operator,
+		Action:     domain.AuditActionBanUser,
+		TargetId:   uid,
+		Detail:     reason,
+	})
+	return nil
+}
+
+func (svc *adminService) UnbanUser(ctx context.Context, operator, uid int64, reason string) error {
+	err := svc.userRepo.UpdateBanned(ctx, uid, false)
+	if err != nil {
+		return err
+	}
+	recordAudit(ctx, svc.rbacRepo, svc.l, domain.AuditLog{
+		OperatorId: operator,
+		Action:     domain.AuditActionUnbanUser,
+		TargetId:   uid,
+		Detail:     reason,
+	})
+	return nil
+}
+
+func (svc *adminService) UnpublishArticle(ctx context.Context, operator, aid int64, reason string) error {
+	art, err := svc.artRepo.GetPublishedById(ctx, aid)
+	if err != nil {
+		return err
+	}
+	err = svc.artRepo.SyncStatus(ctx, art.Author.Id, aid, domain.ArticleStatusPrivate)
+	if err != nil {
+		return err
+	}
+	recordAudit(ctx, svc.rbacRepo, svc.l, domain.AuditLog{
+		OperatorId: operator,
+		Action:     domain.AuditActionUnpublishArticle,
+		TargetId:   aid,
+		Detail:     reason,
+	})
+	return nil
+}
+
+func (svc *adminService) ListAuditLogs(ctx context.Context, offset, limit int) ([]domain.AuditLog, error) {
+	return svc.rbacRepo.ListAuditLogs(ctx, offset, limit)
+}
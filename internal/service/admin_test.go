@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+func TestAdminService_BanUser(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (repository.UserRepository,
+			repository.RBACRepository, SessionRevoker)
+
+		operator int64
+		uid      int64
+		reason   string
+
+		wantErr error
+	}{
+		{
+			name: "封禁成功",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.RBACRepository, SessionRevoker) {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				rbacRepo := repomocks.NewMockRBACRepository(ctrl)
+				revoker := svcmocks.NewMockSessionRevoker(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).
+					Return(domain.User{Id: 2}, nil)
+				userRepo.EXPECT().UpdateBanned(gomock.Any(), int64(2), true).Return(nil)
+				revoker.EXPECT().RevokeSessions(gomock.Any(), int64(2), "").Return(nil)
+				rbacRepo.EXPECT().AddAuditLog(gomock.Any(), domain.AuditLog{
+					OperatorId: 1,
+					Action:     domain.AuditActionBanUser,
+					TargetId:   2,
+					Detail:     "发广告",
+				}).Return(nil)
+				return userRepo, rbacRepo, revoker
+			},
+			operator: 1,
+			uid:      2,
+			reason:   "发广告",
+		},
+		{
+			name: "审计日志失败不影响封禁",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.RBACRepository, SessionRevoker) {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				rbacRepo := repomocks.NewMockRBACRepository(ctrl)
+				revoker := svcmocks.NewMockSessionRevoker(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).
+					Return(domain.User{Id: 2}, nil)
+				userRepo.EXPECT().UpdateBanned(gomock.Any(), int64(2), true).Return(nil)
+				revoker.EXPECT().RevokeSessions(gomock.Any(), int64(2), "").Return(nil)
+				rbacRepo.EXPECT().AddAuditLog(gomock.Any(), gomock.Any()).
+					Return(errors.New("mock db 错误"))
+				return userRepo, rbacRepo, revoker
+			},
+			operator: 1,
+			uid:      2,
+			reason:   "发广告",
+		},
+		{
+			name: "用户不存在",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository,
+				repository.RBACRepository, SessionRevoker) {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).
+					Return(domain.User{}, repository.ErrUserNotFound)
+				return userRepo, repomocks.NewMockRBACRepository(ctrl),
+					svcmocks.NewMockSessionRevoker(ctrl)
+			},
+			operator: 1,
+			uid:      2,
+			wantErr:  repository.ErrUserNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			userRepo, rbacRepo, revoker := tc.mock(ctrl)
+			svc := NewAdminService(userRepo, nil, rbacRepo, revoker,
+				logger.NewZapLogger(zap.NewNop()))
+			err := svc.BanUser(context.Background(), tc.operator, tc.uid, tc.reason)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
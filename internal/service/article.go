@@ -9,6 +9,9 @@ import (
 	"webook/pkg/logger"
 )
 
+// ErrArticleNotPublished 文章不存在，或者已经被撤回、下线
+var ErrArticleNotPublished = repository.ErrArticleNotPublished
+
 //go:generate mockgen -source=./article.go -package=svcmocks -destination=mocks/article.mock.go ArticleService
 type ArticleService interface {
 	Save(ctx context.Context, art domain.Article) (int64, error)
@@ -22,9 +25,13 @@ type ArticleService interface {
 	// 正常来说在微服务架构下，读者服务和创作者服务会是两个独立的服务
 	// 单体应用下可以混在一起
 	GetPublishedById(ctx context.Context, id int64, uid int64) (domain.Article, error)
+	// GetPublishedAuthor 查询已经发表的文章的作者，和 GetPublishedById 不同，不会产生阅读事件
+	GetPublishedAuthor(ctx context.Context, id int64) (domain.Author, error)
 
 	// ListPub 根据更新时间来分页，更新时间必须小于 startTime
 	ListPub(ctx context.Context, startTime time.Time, offset, limit int) ([]domain.Article, error)
+	// ListPubByAuthor 作者已经发表的文章，按照更新时间倒序
+	ListPubByAuthor(ctx context.Context, author int64, offset, limit int) ([]domain.Article, error)
 }
 
 type articleService struct {
@@ -45,6 +52,10 @@ func (svc *articleService) ListPub(ctx context.Context, startTime time.Time, off
 	return svc.repo.ListPub(ctx, startTime, offset, limit)
 }
 
+func (svc *articleService) ListPubByAuthor(ctx context.Context, author int64, offset, limit int) ([]domain.Article, error) {
+	return svc.repo.ListPubByAuthor(ctx, author, offset, limit)
+}
+
 // GetPublishedById 获取已发布的文章信息，并发送阅读事件
 //
 //	id: 文章 ID
@@ -73,6 +84,14 @@ func (svc *articleService) GetPublishedById(ctx context.Context, id int64, uid i
 	return res, err
 }
 
+func (svc *articleService) GetPublishedAuthor(ctx context.Context, id int64) (domain.Author, error) {
+	art, err := svc.repo.GetPublishedById(ctx, id)
+	if err != nil {
+		return domain.Author{}, err
+	}
+	return art.Author, nil
+}
+
 func (svc *articleService) GetById(ctx context.Context, id int64) (domain.Article, error) {
 	return svc.repo.GetById(ctx, id)
 }
@@ -87,7 +106,23 @@ func (svc *articleService) Withdraw(ctx context.Context, uid, id int64) error {
 
 func (svc *articleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
 	art.Status = domain.ArticleStatusPublished
-	return svc.repo.Sync(ctx, art)
+	id, err := svc.repo.Sync(ctx, art)
+	if err != nil {
+		return id, err
+	}
+	// 关注流之类的下游业务通过消费发表事件来处理，这里发送失败不影响发表本身
+	er := svc.producer.ProducePublishEvent(ctx, eventsArticle.PublishEvent{
+		Aid:   id,
+		Uid:   art.Author.Id,
+		Ctime: time.Now().UnixMilli(),
+	})
+	if er != nil {
+		svc.logger.Error("发送文章发表事件失败",
+			logger.Int64("aid", id),
+			logger.Int64("uid", art.Author.Id),
+			logger.Error(er))
+	}
+	return id, nil
 }
 
 func (svc *articleService) Save(ctx context.Context, art domain.Article) (int64, error) {
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/captcha"
+)
+
+// ErrCaptchaIncorrect 答案不对，或者验证码已经过期、已经校验过了
+// 不管是哪一种，前端都需要重新获取验证码
+var ErrCaptchaIncorrect = errors.New("图形验证码错误或者已经过期")
+
+const (
+	// bizCaptcha 图形验证码的答案
+	bizCaptcha = "captcha"
+	// bizCaptchaPass 通过图形验证码之后拿到的凭证
+	bizCaptchaPass = "captcha_pass"
+)
+
+//go:generate mockgen -source=./captcha.go -package=svcmocks -destination=mocks/captcha.mock.go CaptchaService
+type CaptchaService interface {
+	// Generate 生成新的图形验证码，答案保存在服务端
+	Generate(ctx context.Context) (domain.Captcha, error)
+	// Verify 校验答案，通过了就返回一个凭证，凭证可以用在需要图形验证码的接口上
+	// 不管答案对不对，验证码都只能校验一次
+	Verify(ctx context.Context, id string, answer string) (string, error)
+	// VerifyPass 校验通过图形验证码之后拿到的凭证，凭证只能使用一次
+	VerifyPass(ctx context.Context, token string) (bool, error)
+}
+
+type captchaService struct {
+	tokenRepo repository.TokenRepository
+	gen       captcha.Generator
+	// 答案的有效期
+	expiration time.Duration
+	// 凭证的有效期
+	passExpiration time.Duration
+}
+
+func NewCaptchaService(tokenRepo repository.TokenRepository, gen captcha.Generator) CaptchaService {
+	return &captchaService{
+		tokenRepo:      tokenRepo,
+		gen:            gen,
+		expiration:     time.Minute * 5,
+		passExpiration: time.Minute * 5,
+	}
+}
+
+func (svc *captchaService) Generate(ctx context.Context) (domain.Captcha, error) {
+	answer, img, err := svc.gen.Generate()
+	if err != nil {
+		return domain.Captcha{}, err
+	}
+	id, err := svc.randomToken()
+	if err != nil {
+		return domain.Captcha{}, err
+	}
+	err = svc.tokenRepo.Store(ctx, bizCaptcha, id, answer, svc.expiration)
+	if err != nil {
+		return domain.Captcha{}, err
+	}
+	return domain.Captcha{Id: id, Image: img}, nil
+}
+
+func (svc *captchaService) Verify(ctx context.Context, id string, answer string) (string, error) {
+	// 先删除答案再比较，答错了也不能再试，避免暴力尝试
+	expected, err := svc.tokenRepo.Consume(ctx, bizCaptcha, id)
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		return "", ErrCaptchaIncorrect
+	}
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(strings.TrimSpace(answer), expected) {
+		return "", ErrCaptchaIncorrect
+	}
+	token, err := svc.randomToken()
+	if err != nil {
+		return "", err
+	}
+	err = svc.tokenRepo.Store(ctx, bizCaptchaPass, token, id, svc.passExpiration)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (svc *captchaService) VerifyPass(ctx context.Context, token string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	_, err := svc.tokenRepo.Consume(ctx, bizCaptchaPass, token)
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// randomToken 验证码的 ID 和凭证都是 16 字节的随机数
+func (svc *captchaService) randomToken() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
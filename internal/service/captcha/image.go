@@ -0,0 +1,187 @@
+package captcha
+
+import (
+	"bytes"
+	"crypto/rand"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	mrand "math/rand/v2"
+)
+
+// digits 5x7 的点阵字体，每一行是一个字符串，1 表示有笔画
+// 只用数字，避免 0 和 O、1 和 l 这种分不清楚的情况
+var digits = [10][7]string{
+	{"01110", "10001", "10011", "10101", "11001", "10001", "01110"},
+	{"00100", "01100", "00100", "00100", "00100", "00100", "01110"},
+	{"01110", "10001", "00001", "00010", "00100", "01000", "11111"},
+	{"11110", "00001", "00001", "01110", "00001", "00001", "11110"},
+	{"00010", "00110", "01010", "10010", "11111", "00010", "00010"},
+	{"11111", "10000", "11110", "00001", "00001", "10001", "01110"},
+	{"00110", "01000", "10000", "11110", "10001", "10001", "01110"},
+	{"11111", "00001", "00010", "00100", "01000", "01000", "01000"},
+	{"01110", "10001", "10001", "01110", "10001", "10001", "01110"},
+	{"01110", "10001", "10001", "01111", "00001", "00010", "01100"},
+}
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// palette 调色板的第一个是背景色，后面的用于字符和干扰线
+var palette = color.Palette{
+	color.RGBA{R: 0xf5, G: 0xf5, B: 0xf0, A: 0xff},
+	color.RGBA{R: 0x2b, G: 0x3a, B: 0x67, A: 0xff},
+	color.RGBA{R: 0x8c, G: 0x1c, B: 0x13, A: 0xff},
+	color.RGBA{R: 0x1d, G: 0x5c, B: 0x3a, A: 0xff},
+	color.RGBA{R: 0x5b, G: 0x2a, B: 0x86, A: 0xff},
+	color.RGBA{R: 0x6b, G: 0x4f, B: 0x1d, A: 0xff},
+	color.RGBA{R: 0xa0, G: 0xa0, B: 0xa8, A: 0xff},
+}
+
+// ImageGenerator 只依赖标准库的 image 包生成扭曲的数字图片
+// 每个字符单独缩放、倾斜和上下错开，整张图再按照正弦波扭曲，最后加上噪点和干扰线
+type ImageGenerator struct {
+	width  int
+	height int
+	length int
+}
+
+func NewImageGenerator() *ImageGenerator {
+	return &ImageGenerator{
+		width:  120,
+		height: 40,
+		length: 5,
+	}
+}
+
+// Size 图片的宽度和高度
+func (g *ImageGenerator) Size(width, height int) *ImageGenerator {
+	g.width = width
+	g.height = height
+	return g
+}
+
+// Length 答案的长度
+func (g *ImageGenerator) Length(n int) *ImageGenerator {
+	g.length = n
+	return g
+}
+
+func (g *ImageGenerator) Generate() (string, []byte, error) {
+	answer, err := g.answer()
+	if err != nil {
+		return "", nil, err
+	}
+	img := g.draw(answer)
+	var buf bytes.Buffer
+	err = png.Encode(&buf, img)
+	if err != nil {
+		return "", nil, err
+	}
+	return answer, buf.Bytes(), nil
+}
+
+// answer 答案用 crypto/rand 生成，图片上的干扰用 math/rand 就够了
+func (g *ImageGenerator) answer() (string, error) {
+	res := make([]byte, 0, g.length)
+	b := make([]byte, 1)
+	for len(res) < g.length {
+		_, err := rand.Read(b)
+		if err != nil {
+			return "", err
+		}
+		// 丢掉 250 及以上的，保证每个数字的概率是一样的
+		if b[0] >= 250 {
+			continue
+		}
+		res = append(res, '0'+b[0]%10)
+	}
+	return string(res), nil
+}
+
+// glyph 一个字符在图片上的位置和变形
+type glyph struct {
+	bits [7]string
+	// 左上角
+	x, y float64
+	// 每个点阵格子的大小
+	scale float64
+	// 倾斜的程度，字符越往下越往右偏
+	shear float64
+	// 在调色板里面的下标
+	color uint8
+}
+
+// ink 图片上的 (x, y) 有没有落在这个字符的笔画上
+func (gl glyph) ink(x, y float64) bool {
+	dy := y - gl.y
+	dx := x - gl.x - gl.shear*(dy-glyphHeight*gl.scale/2)
+	col, row := int(math.Floor(dx/gl.scale)), int(math.Floor(dy/gl.scale))
+	if col < 0 || col >= glyphWidth || row < 0 || row >= glyphHeight {
+		return false
+	}
+	return gl.bits[row][col] == '1'
+}
+
+func (g *ImageGenerator) draw(answer string) *image.Paletted {
+	w, h := float64(g.width), float64(g.height)
+	cell := w / float64(len(answer))
+	glyphs := make([]glyph, 0, len(answer))
+	for i, c := range answer {
+		// 字符的高度占图片的 55% 到 70%
+		scale := h * (0.55 + mrand.Float64()*0.15) / glyphHeight
+		scale = math.Min(scale, cell*0.9/glyphWidth)
+		glyphs = append(glyphs, glyph{
+			bits:  digits[c-'0'],
+			x:     float64(i)*cell + (cell-glyphWidth*scale)*mrand.Float64(),
+			y:     (h - glyphHeight*scale) * mrand.Float64(),
+			scale: scale,
+			shear: mrand.Float64()*0.7 - 0.35,
+			color: uint8(1 + mrand.IntN(len(palette)-2)),
+		})
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, g.width, g.height), palette)
+	// 正弦波扭曲，每个像素到扭曲之前的位置上取值
+	ampX, ampY := 1+mrand.Float64()*1.5, 2+mrand.Float64()*2
+	periodX, periodY := h*(0.8+mrand.Float64()*0.4), w*(0.5+mrand.Float64()*0.3)
+	phaseX, phaseY := mrand.Float64()*2*math.Pi, mrand.Float64()*2*math.Pi
+	for py := 0; py < g.height; py++ {
+		for px := 0; px < g.width; px++ {
+			sx := float64(px) + ampX*math.Sin(2*math.Pi*float64(py)/periodX+phaseX)
+			sy := float64(py) + ampY*math.Sin(2*math.Pi*float64(px)/periodY+phaseY)
+			for _, gl := range glyphs {
+				if gl.ink(sx, sy) {
+					img.SetColorIndex(px, py, gl.color)
+					break
+				}
+			}
+		}
+	}
+
+	g.noise(img)
+	return img
+}
+
+// noise 噪点和穿过字符的干扰线
+func (g *ImageGenerator) noise(img *image.Paletted) {
+	dots := g.width * g.height / 12
+	for i := 0; i < dots; i++ {
+		img.SetColorIndex(mrand.IntN(g.width), mrand.IntN(g.height), uint8(1+mrand.IntN(len(palette)-1)))
+	}
+	w, h := float64(g.width), float64(g.height)
+	for i := 0; i < 3; i++ {
+		// 干扰线是一段正弦曲线，颜色和字符一样，没办法简单地按颜色过滤掉
+		c := uint8(1 + mrand.IntN(len(palette)-2))
+		base, amp := h*(0.25+mrand.Float64()*0.5), h*(0.1+mrand.Float64()*0.2)
+		period, phase := w*(0.6+mrand.Float64()*0.8), mrand.Float64()*2*math.Pi
+		for px := 0; px < g.width; px++ {
+			py := int(base + amp*math.Sin(2*math.Pi*float64(px)/period+phase))
+			img.SetColorIndex(px, py, c)
+			img.SetColorIndex(px, py+1, c)
+		}
+	}
+}
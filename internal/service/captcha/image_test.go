@@ -0,0 +1,72 @@
+package captcha
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image/png"
+	"testing"
+)
+
+func TestImageGenerator_Generate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		gen    *ImageGenerator
+		width  int
+		height int
+		length int
+	}{
+		{
+			name:   "默认大小",
+			gen:    NewImageGenerator(),
+			width:  120,
+			height: 40,
+			length: 5,
+		},
+		{
+			name:   "自定义大小和长度",
+			gen:    NewImageGenerator().Size(200, 60).Length(6),
+			width:  200,
+			height: 60,
+			length: 6,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			answer, data, err := tc.gen.Generate()
+			require.NoError(t, err)
+			assert.Len(t, answer, tc.length)
+			for _, c := range answer {
+				assert.True(t, c >= '0' && c <= '9')
+			}
+			img, err := png.Decode(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, tc.width, img.Bounds().Dx())
+			assert.Equal(t, tc.height, img.Bounds().Dy())
+
+			// 图片上要真的画了东西，不能只有背景
+			var inked int
+			bg := palette[0]
+			for y := 0; y < tc.height; y++ {
+				for x := 0; x < tc.width; x++ {
+					if img.At(x, y) != bg {
+						inked++
+					}
+				}
+			}
+			assert.Greater(t, inked, tc.width*tc.height/10)
+		})
+	}
+}
+
+func TestImageGenerator_Answer(t *testing.T) {
+	gen := NewImageGenerator()
+	seen := make(map[string]struct{}, 100)
+	for i := 0; i < 100; i++ {
+		answer, err := gen.answer()
+		require.NoError(t, err)
+		seen[answer] = struct{}{}
+	}
+	// 十万分之一的概率重复，100 次里面基本不会重复
+	assert.Greater(t, len(seen), 95)
+}
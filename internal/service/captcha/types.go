@@ -0,0 +1,11 @@
+package captcha
+
+//go:generate mockgen -source=./types.go -package=captchamocks -destination=mocks/generator.mock.go Generator
+
+// Generator 生成图形验证码
+// 和 sms.Service、email.Service 一样，目的是屏蔽具体的实现，
+// 以后要换成滑块、点选之类的验证码也不需要修改业务代码
+type Generator interface {
+	// Generate 返回答案以及 PNG 格式的图片
+	Generate() (answer string, image []byte, err error)
+}
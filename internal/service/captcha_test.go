@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	captchamocks "webook/internal/service/captcha/mocks"
+)
+
+func TestCaptchaService_Generate(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.TokenRepository, *captchamocks.MockGenerator)
+
+		wantImage []byte
+		wantErr   error
+	}{
+		{
+			name: "生成成功",
+			mock: func(ctrl *gomock.Controller) (repository.TokenRepository, *captchamocks.MockGenerator) {
+				tokenRepo := repomocks.NewMockTokenRepository(ctrl)
+				gen := captchamocks.NewMockGenerator(ctrl)
+				gen.EXPECT().Generate().Return("12345", []byte("png"), nil)
+				tokenRepo.EXPECT().Store(gomock.Any(), bizCaptcha, gomock.Any(), "12345", gomock.Any()).
+					Return(nil)
+				return tokenRepo, gen
+			},
+			wantImage: []byte("png"),
+		},
+		{
+			name: "保存答案失败",
+			mock: func(ctrl *gomock.Controller) (repository.TokenRepository, *captchamocks.MockGenerator) {
+				tokenRepo := repomocks.NewMockTokenRepository(ctrl)
+				gen := captchamocks.NewMockGenerator(ctrl)
+				gen.EXPECT().Generate().Return("12345", []byte("png"), nil)
+				tokenRepo.EXPECT().Store(gomock.Any(), bizCaptcha, gomock.Any(), "12345", gomock.Any()).
+					Return(errors.New("redis 错误"))
+				return tokenRepo, gen
+			},
+			wantErr: errors.New("redis 错误"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tokenRepo, gen := tc.mock(ctrl)
+			svc := NewCaptchaService(tokenRepo, gen)
+			c, err := svc.Generate(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.NotEmpty(t, c.Id)
+			assert.Equal(t, tc.wantImage, c.Image)
+		})
+	}
+}
+
+func TestCaptchaService_Verify(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) repository.TokenRepository
+		answer string
+
+		wantToken bool
+		wantErr   error
+	}{
+		{
+			name: "答案正确，拿到凭证",
+			mock: func(ctrl *gomock.Controller) repository.TokenRepository {
+				tokenRepo := repomocks.NewMockTokenRepository(ctrl)
+				tokenRepo.EXPECT().Consume(gomock.Any(), bizCaptcha, "cid").Return("12345", nil)
+				tokenRepo.EXPECT().Store(gomock.Any(), bizCaptchaPass, gomock.Any(), "cid", gomock.Any()).
+					Return(nil)
+				return tokenRepo
+			},
+			answer:    " 12345 ",
+			wantToken: true,
+		},
+		{
+			name: "答案错误",
+			mock: func(ctrl *gomock.Controller) repository.TokenRepository {
+				tokenRepo := repomocks.NewMockTokenRepository(ctrl)
+				tokenRepo.EXPECT().Consume(gomock.Any(), bizCaptcha, "cid").Return("12345", nil)
+				return tokenRepo
+			},
+			answer:  "54321",
+			wantErr: ErrCaptchaIncorrect,
+		},
+		{
+			name: "过期或者已经校验过了",
+			mock: func(ctrl *gomock.Controller) repository.TokenRepository {
+				tokenRepo := repomocks.NewMockTokenRepository(ctrl)
+				tokenRepo.EXPECT().Consume(gomock.Any(), bizCaptcha, "cid").
+					Return("", repository.ErrTokenNotFound)
+				return tokenRepo
+			},
+			answer:  "12345",
+			wantErr: ErrCaptchaIncorrect,
+		},
+		{
+			name: "保存凭证失败",
+			mock: func(ctrl *gomock.Controller) repository.TokenRepository {
+				tokenRepo := repomocks.NewMockTokenRepository(ctrl)
+				tokenRepo.EXPECT().Consume(gomock.Any(), bizCaptcha, "cid").Return("12345", nil)
+				tokenRepo.EXPECT().Store(gomock.Any(), bizCaptchaPass, gomock.Any(), "cid", gomock.Any()).
+					Return(errors.New("redis 错误"))
+				return tokenRepo
+			},
+			answer:  "12345",
+			wantErr: errors.New("redis 错误"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCaptchaService(tc.mock(ctrl), captchamocks.NewMockGenerator(ctrl))
+			token, err := svc.Verify(context.Background(), "cid", tc.answer)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantToken, token != "")
+		})
+	}
+}
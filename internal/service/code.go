@@ -2,9 +2,15 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"math/rand"
+	"slices"
+	"time"
+	"webook/internal/domain"
 	"webook/internal/repository"
 	"webook/internal/service/sms"
 	"webook/pkg/logger"
@@ -12,9 +18,77 @@ import (
 
 var (
 	ErrCodeSendTooMany = repository.ErrCodeSendTooMany
+	// ErrCodeSendLimited 手机号、IP 或者设备的发送次数达到了上限
+	ErrCodeSendLimited = errors.New("验证码发送次数太多")
+	// ErrCaptchaRequired 发送次数超过了软限制，需要先通过图形验证码
+	ErrCaptchaRequired = errors.New("需要图形验证码")
+	// ErrCodeChannelUnsupported 没有这个渠道，或者这种业务不允许使用这个渠道
+	ErrCodeChannelUnsupported = errors.New("不支持的验证码发送渠道")
+	// ErrCodeUnsupportedRegion 没有短信服务商能发到这个国家或地区的手机号
+	ErrCodeUnsupportedRegion = sms.ErrUnsupportedRegion
+	// ErrCodeSendDeferred 验证码已经生成了，但是短信没有马上发出去，会在有效期内异步重试
+	ErrCodeSendDeferred = sms.ErrDeferred
 )
 
-const codeTplId = "2320764" // 短信模板 ID，用于发送验证码短信
+// CodeLimitRule 一个维度的发送次数限制，Hard 为 0 表示不限制
+type CodeLimitRule struct {
+	Window time.Duration
+	// Soft 超过之后需要图形验证码，为 0 表示不需要
+	Soft int64
+	// Hard 达到之后不允许发送
+	Hard int64
+}
+
+// CodeLimits 一种业务的验证码的发送次数限制
+type CodeLimits struct {
+	// Target 按照接收的手机号、邮箱计数，不同的渠道分开计数
+	Target CodeLimitRule
+	IP     CodeLimitRule
+	Device CodeLimitRule
+}
+
+// CodeConfig 一种业务的验证码配置，没有设置的字段使用 defaultCodeConfig 的值
+type CodeConfig struct {
+	// Length 验证码的位数
+	Length int
+	// TTL 验证码的有效期
+	TTL time.Duration
+	// Interval 同一个目标两次发送之间的最短间隔
+	Interval time.Duration
+	// MaxAttempts 一个验证码最多可以验证几次
+	MaxAttempts int
+	// Channels 允许使用的渠道，为空表示都可以
+	Channels []domain.CodeChannel
+	Limits   CodeLimits
+}
+
+// defaultCodeConfig 和以前固定在 lua 脚本里面的一样
+var defaultCodeConfig = CodeConfig{
+	Length:      6,
+	TTL:         time.Minute * 10,
+	Interval:    time.Minute,
+	MaxAttempts: 3,
+}
+
+func (cfg CodeConfig) withDefaults() CodeConfig {
+	if cfg.Length <= 0 {
+		cfg.Length = defaultCodeConfig.Length
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultCodeConfig.TTL
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultCodeConfig.Interval
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultCodeConfig.MaxAttempts
+	}
+	return cfg
+}
+
+func (cfg CodeConfig) allow(channel domain.CodeChannel) bool {
+	return len(cfg.Channels) == 0 || slices.Contains(cfg.Channels, channel)
+}
 
 // CodeService 是处理验证码相关业务逻辑的接口
 // 提供了发送验证码和验证验证码的功能
@@ -24,67 +98,101 @@ type CodeService interface {
 	// 参数:
 	//   - ctx: 上下文，用于控制请求的生命周期
 	//   - biz: 验证码的业务类型，区分不同的业务场景，如登录、注册等
-	//   - phone: 用户的手机号码，接收验证码的对象
+	//   - target: 接收验证码的渠道和地址，例如短信发到某个手机号
+	//   - client: 发起请求的客户端，用来按照 IP 和设备限制发送次数
 	// 返回:
 	//   - error: 如果发送成功返回 nil；如果发送失败（如验证码发送失败、发送频率过高等），则返回相应的错误
-	Send(ctx context.Context, biz string, phone string) error
+	//     发送次数超过软限制并且没有有效的图形验证码凭证的时候，返回 ErrCaptchaRequired
+	//     业务不允许使用这个渠道的时候，返回 ErrCodeChannelUnsupported
+	Send(ctx context.Context, biz string, target domain.CodeTarget, client domain.CodeClient) error
 
 	// Verify 用于验证用户输入的验证码
 	// 参数:
 	//   - ctx: 上下文，用于控制请求的生命周期
 	//   - biz: 验证码的业务类型，用于区分不同场景的验证码
-	//   - phone: 用户的手机号码，验证码验证的对象
+	//   - target: 接收验证码的渠道和地址，要和发送的时候一样
 	//   - inputCode: 用户输入的验证码
 	// 返回:
 	//   - bool: 返回是否验证成功。如果验证码正确，返回 true；否则返回 false
 	//   - error: 如果验证过程中发生错误，返回错误信息
-	Verify(ctx context.Context, biz string, phone string, inputCode string) (bool, error)
+	Verify(ctx context.Context, biz string, target domain.CodeTarget, inputCode string) (bool, error)
 }
 
-// SMSCodeService 负责处理验证码的相关业务逻辑：生成验证码、存储验证码、发送短信以及验证验证码
-type SMSCodeService struct {
-	sms    sms.Service               // 短信服务接口，用于发送验证码短信
-	repo   repository.CodeRepository // 数据库操作对象，用于存储和验证验证码
-	logger logger.Logger
+// MultiChannelCodeService 负责处理验证码的相关业务逻辑：生成验证码、存储验证码、通过不同的渠道发送以及验证验证码
+// Redis 里面只保存验证码的 HMAC，拿到 Redis 的数据也没办法直接用里面的验证码登录
+type MultiChannelCodeService struct {
+	senders   map[domain.CodeChannel]CodeSender // 每个渠道发送验证码的方式
+	repo      repository.CodeRepository         // 数据库操作对象，用于存储和验证验证码
+	limitRepo repository.CodeLimitRepository
+	captcha   CaptchaService
+	// configs 每种业务的验证码配置，没有配置的业务使用 defaultCodeConfig
+	configs map[string]CodeConfig
+	// hashKey 计算验证码 HMAC 的密钥
+	hashKey []byte
+	logger  logger.Logger
 }
 
-// NewSMSCodeService 实现 CodeService 接口
-func NewSMSCodeService(svc sms.Service, repo repository.CodeRepository, l logger.Logger) CodeService {
-	return &SMSCodeService{
-		sms:    svc,
-		repo:   repo,
-		logger: l,
+// NewCodeService 实现 CodeService 接口
+func NewCodeService(senders map[domain.CodeChannel]CodeSender, repo repository.CodeRepository,
+	limitRepo repository.CodeLimitRepository, captcha CaptchaService,
+	configs map[string]CodeConfig, hashKey []byte, l logger.Logger) CodeService {
+	res := make(map[string]CodeConfig, len(configs))
+	for biz, cfg := range configs {
+		res[biz] = cfg.withDefaults()
+	}
+	return &MultiChannelCodeService{
+		senders:   senders,
+		repo:      repo,
+		limitRepo: limitRepo,
+		captcha:   captcha,
+		configs:   res,
+		hashKey:   hashKey,
+		logger:    l,
 	}
 }
 
-func (c *SMSCodeService) Send(ctx context.Context, biz string, phone string) error {
-	code := c.generate() // 生成一个随机验证码
-	// 存储验证码到缓存中
-	err := c.repo.Store(ctx, biz, phone, code)
+func (c *MultiChannelCodeService) Send(ctx context.Context, biz string, target domain.CodeTarget, client domain.CodeClient) error {
+	cfg := c.config(biz)
+	sender, ok := c.senders[target.Channel]
+	if !ok || !cfg.allow(target.Channel) {
+		return ErrCodeChannelUnsupported
+	}
+	err := c.checkLimits(ctx, biz, cfg.Limits, target, client)
+	if err != nil {
+		return err
+	}
+	code, err := c.generate(cfg.Length) // 生成一个随机验证码
+	if err != nil {
+		return err
+	}
+	// 存储验证码的哈希值到缓存中
+	err = c.repo.Store(ctx, biz, target.String(), c.hash(biz, target, code), repository.CodeSetting{
+		TTL:         cfg.TTL,
+		Interval:    cfg.Interval,
+		MaxAttempts: cfg.MaxAttempts,
+	})
 	if err != nil {
 		return err // 存储失败，返回错误
 	}
-	// 发送验证码短信
-	err = c.sms.Send(ctx, codeTplId, []string{code}, phone)
+	// 发送验证码
+	err = sender.Send(ctx, biz, target.Address, code, cfg.TTL)
 	// TODO 这里考虑返回 err 之后是否要删除 redis 里边的验证码
 	if err != nil {
-		c.logger.Warn("发送验证码短信失败: ", logger.Field{
-			Key:   "SMSCodeService",
-			Value: err.Error(),
-		})
+		c.logger.Warn("发送验证码失败: ", logger.String("channel", string(target.Channel)),
+			logger.Error(err))
 	}
-	return err // 返回发送短信的错误
+	return err // 返回发送的错误
 }
 
-func (c *SMSCodeService) Verify(ctx context.Context, biz string, phone string, inputCode string) (bool, error) {
-	// 调用 repository 层的 Verify 方法验证验证码
-	ok, err := c.repo.Verify(ctx, biz, phone, inputCode)
+func (c *MultiChannelCodeService) Verify(ctx context.Context, biz string, target domain.CodeTarget, inputCode string) (bool, error) {
+	// 调用 repository 层的 Verify 方法验证验证码，比较的是哈希值
+	ok, err := c.repo.Verify(ctx, biz, target.String(), c.hash(biz, target, inputCode))
 	// 处理特殊的错误：验证码验证次数超限
 	if errors.Is(err, repository.ErrCodeVerifyTooManyTimes) {
 		// 如果验证次数超过限制，表示可能存在异常行为（例如恶意攻击）
 		// 在接入告警系统后，可以在这里进行告警处理
 		c.logger.Error("验证次数超过限制: ", logger.Field{
-			Key:   "SMSCodeService",
+			Key:   "MultiChannelCodeService",
 			Value: err.Error(),
 		})
 		return false, nil // 返回 false，表示验证失败
@@ -93,10 +201,108 @@ func (c *SMSCodeService) Verify(ctx context.Context, biz string, phone string, i
 	return ok, err
 }
 
-// generate 生成一个随机的 6 位验证码
-// 使用随机数生成一个介于 0 到 999999 之间的验证码
-func (c *SMSCodeService) generate() string {
-	num := rand.Intn(999999) // 生成随机数
-	// 将随机数格式化为 6 位字符串，前面补零
-	return fmt.Sprintf("%06d", num)
+// checkLimits 检查目标、IP 和设备的发送次数
+// 超过软限制的需要图形验证码，达到硬限制的直接拒绝
+func (c *MultiChannelCodeService) checkLimits(ctx context.Context, biz string, limits CodeLimits,
+	target domain.CodeTarget, client domain.CodeClient) error {
+	counters, softs := c.counters(biz, limits, target, client)
+	if len(counters) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(counters))
+	for _, cnt := range counters {
+		keys = append(keys, cnt.Key)
+	}
+	cnts, err := c.limitRepo.Counts(ctx, keys)
+	if err != nil {
+		return err
+	}
+	needCaptcha := false
+	for i, cnt := range cnts {
+		if cnt >= counters[i].Limit {
+			return ErrCodeSendLimited
+		}
+		if softs[i] > 0 && cnt >= softs[i] {
+			needCaptcha = true
+		}
+	}
+	if needCaptcha {
+		ok, err := c.captcha.VerifyPass(ctx, client.CaptchaToken)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrCaptchaRequired
+		}
+	}
+	// 检查和计数之间有并发的请求也没关系，计数的时候会再检查一遍硬限制
+	ok, err := c.limitRepo.Incr(ctx, counters)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCodeSendLimited
+	}
+	return nil
+}
+
+// counters 返回需要检查的计数器，以及每个计数器的软限制
+func (c *MultiChannelCodeService) counters(biz string, limits CodeLimits,
+	target domain.CodeTarget, client domain.CodeClient) ([]repository.CodeLimitCounter, []int64) {
+	var (
+		counters []repository.CodeLimitCounter
+		softs    []int64
+	)
+	add := func(dimension, val string, rule CodeLimitRule) {
+		// 拿不到的维度，例如客户端没有带上设备标识，就不限制
+		if val == "" || rule.Hard <= 0 {
+			return
+		}
+		counters = append(counters, repository.CodeLimitCounter{
+			Key:    fmt.Sprintf("%s:%s:%s", biz, dimension, val),
+			Window: rule.Window,
+			Limit:  rule.Hard,
+		})
+		softs = append(softs, rule.Soft)
+	}
+	// 目标按照渠道区分，短信和语音验证码分开计数
+	add(string(target.Channel), target.Address, limits.Target)
+	add("ip", client.IP, limits.IP)
+	add("device", client.Device, limits.Device)
+	return counters, softs
+}
+
+// generate 生成一个 length 位的随机数字验证码
+// 验证码相当于一次性的密码，所以用 crypto/rand
+func (c *MultiChannelCodeService) generate(length int) (string, error) {
+	res := make([]byte, length)
+	b := make([]byte, 1)
+	for i := 0; i < length; {
+		_, err := rand.Read(b)
+		if err != nil {
+			return "", err
+		}
+		// 丢掉 250 及以上的，保证每个数字的概率是一样的
+		if b[0] >= 250 {
+			continue
+		}
+		res[i] = '0' + b[0]%10
+		i++
+	}
+	return string(res), nil
+}
+
+// hash 验证码的 HMAC，业务和目标也算进去，同一个验证码在不同的地方哈希值也不一样
+func (c *MultiChannelCodeService) hash(biz string, target domain.CodeTarget, code string) string {
+	mac := hmac.New(sha256.New, c.hashKey)
+	mac.Write([]byte(biz + ":" + target.String() + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *MultiChannelCodeService) config(biz string) CodeConfig {
+	cfg, ok := c.configs[biz]
+	if !ok {
+		return defaultCodeConfig
+	}
+	return cfg
 }
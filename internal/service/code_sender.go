@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"webook/internal/service/email"
+	"webook/internal/service/sms"
+)
+
+//go:generate mockgen -source=./code_sender.go -package=svcmocks -destination=mocks/code_sender.mock.go CodeSender
+type CodeSender interface {
+	// Send 把验证码发送给 address，ttl 是验证码的有效期，可以告诉用户
+	Send(ctx context.Context, biz string, address string, code string, ttl time.Duration) error
+}
+
+// codeTplName 验证码短信的模板名字，每种业务都有自己的模板，例如 login_code
+// 具体服务商的模板 ID 配置在 sms.templates 里面
+func codeTplName(biz string) string {
+	return biz + "_code"
+}
+
+// voiceCodeTplName 语音验证码的模板名字，例如 login_voice_code
+func voiceCodeTplName(biz string) string {
+	return biz + "_voice_code"
+}
+
+// SMSCodeSender 通过短信发送验证码
+type SMSCodeSender struct {
+	svc sms.Service
+}
+
+func NewSMSCodeSender(svc sms.Service) *SMSCodeSender {
+	return &SMSCodeSender{svc: svc}
+}
+
+func (s *SMSCodeSender) Send(ctx context.Context, biz string, address string, code string, ttl time.Duration) error {
+	// 验证码过期之后，异步重试的短信就不要再发了
+	ctx = sms.WithDeadline(ctx, time.Now().Add(ttl))
+	return s.svc.Send(ctx, codeTplName(biz), []string{code}, address)
+}
+
+// VoiceCodeSender 通过语音电话把验证码念给用户听，收不到短信的用户可以用
+// 服务商的语音验证码接口和短信一样是模板、参数加号码，所以直接复用 sms.Service 的抽象，
+// 模板同样配置在 sms.templates 里面
+type VoiceCodeSender struct {
+	svc sms.Service
+}
+
+func NewVoiceCodeSender(svc sms.Service) *VoiceCodeSender {
+	return &VoiceCodeSender{svc: svc}
+}
+
+func (s *VoiceCodeSender) Send(ctx context.Context, biz string, address string, code string, ttl time.Duration) error {
+	ctx = sms.WithDeadline(ctx, time.Now().Add(ttl))
+	return s.svc.Send(ctx, voiceCodeTplName(biz), []string{code}, address)
+}
+
+// EmailCodeSender 通过邮件发送验证码
+type EmailCodeSender struct {
+	svc email.Service
+}
+
+func NewEmailCodeSender(svc email.Service) *EmailCodeSender {
+	return &EmailCodeSender{svc: svc}
+}
+
+func (s *EmailCodeSender) Send(ctx context.Context, biz string, address string, code string, ttl time.Duration) error {
+	content := fmt.Sprintf(`<p>你的 webook 验证码是 <b>%s</b>，%d 分钟内有效。</p>
+<p>如果不是你本人操作，请忽略这封邮件。</p>`, code, int(ttl.Minutes()))
+	return s.svc.Send(ctx, "webook 验证码", content, address)
+}
@@ -1,10 +1,224 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
 	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
 )
 
 func TestFormat(t *testing.T) {
 	fmt.Printf("%06d", 123)
 }
+
+func TestMultiChannelCodeService_Send(t *testing.T) {
+	configs := map[string]CodeConfig{
+		"login": {
+			Channels: []domain.CodeChannel{domain.CodeChannelSMS, domain.CodeChannelVoice},
+			Limits: CodeLimits{
+				Target: CodeLimitRule{Window: time.Hour * 24, Soft: 3, Hard: 10},
+				IP:     CodeLimitRule{Window: time.Hour, Soft: 5, Hard: 30},
+				// 没有设备的限制
+			},
+		},
+	}
+	counters := []repository.CodeLimitCounter{
+		{Key: "login:sms:152xxx", Window: time.Hour * 24, Limit: 10},
+		{Key: "login:ip:127.0.0.1", Window: time.Hour, Limit: 30},
+	}
+	keys := []string{"login:sms:152xxx", "login:ip:127.0.0.1"}
+	// 没有配置的业务使用默认的设置
+	setting := repository.CodeSetting{TTL: time.Minute * 10, Interval: time.Minute, MaxAttempts: 3}
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (CodeSender, repository.CodeRepository,
+			repository.CodeLimitRepository, CaptchaService)
+
+		biz     string
+		channel domain.CodeChannel
+		client  domain.CodeClient
+
+		wantErr error
+	}{
+		{
+			name: "发送成功",
+			mock: func(ctrl *gomock.Controller) (CodeSender, repository.CodeRepository,
+				repository.CodeLimitRepository, CaptchaService) {
+				sender := svcmocks.NewMockCodeSender(ctrl)
+				codeRepo := repomocks.NewMockCodeRepository(ctrl)
+				limitRepo := repomocks.NewMockCodeLimitRepository(ctrl)
+				limitRepo.EXPECT().Counts(gomock.Any(), keys).Return([]int64{1, 1}, nil)
+				limitRepo.EXPECT().Incr(gomock.Any(), counters).Return(true, nil)
+				codeRepo.EXPECT().Store(gomock.Any(), "login", "sms:152xxx", gomock.Any(), setting).Return(nil)
+				sender.EXPECT().Send(gomock.Any(), "login", "152xxx", gomock.Any(), time.Minute*10).Return(nil)
+				return sender, codeRepo, limitRepo, svcmocks.NewMockCaptchaService(ctrl)
+			},
+			biz:    "login",
+			client: domain.CodeClient{IP: "127.0.0.1"},
+		},
+		{
+			name: "超过软限制，需要图形验证码",
+			mock: func(ctrl *gomock.Controller) (CodeSender, repository.CodeRepository,
+				repository.CodeLimitRepository, CaptchaService) {
+				limitRepo := repomocks.NewMockCodeLimitRepository(ctrl)
+				limitRepo.EXPECT().Counts(gomock.Any(), keys).Return([]int64{3, 1}, nil)
+				captcha := svcmocks.NewMockCaptchaService(ctrl)
+				captcha.EXPECT().VerifyPass(gomock.Any(), "").Return(false, nil)
+				return svcmocks.NewMockCodeSender(ctrl), repomocks.NewMockCodeRepository(ctrl),
+					limitRepo, captcha
+			},
+			biz:     "login",
+			client:  domain.CodeClient{IP: "127.0.0.1"},
+			wantErr: ErrCaptchaRequired,
+		},
+		{
+			name: "超过软限制，通过了图形验证码",
+			mock: func(ctrl *gomock.Controller) (CodeSender, repository.CodeRepository,
+				repository.CodeLimitRepository, CaptchaService) {
+				sender := svcmocks.NewMockCodeSender(ctrl)
+				codeRepo := repomocks.NewMockCodeRepository(ctrl)
+				limitRepo := repomocks.NewMockCodeLimitRepository(ctrl)
+				limitRepo.EXPECT().Counts(gomock.Any(), keys).Return([]int64{1, 5}, nil)
+				captcha := svcmocks.NewMockCaptchaService(ctrl)
+				captcha.EXPECT().VerifyPass(gomock.Any(), "pass").Return(true, nil)
+				limitRepo.EXPECT().Incr(gomock.Any(), counters).Return(true, nil)
+				codeRepo.EXPECT().Store(gomock.Any(), "login", "sms:152xxx", gomock.Any(), setting).Return(nil)
+				sender.EXPECT().Send(gomock.Any(), "login", "152xxx", gomock.Any(), time.Minute*10).Return(nil)
+				return sender, codeRepo, limitRepo, captcha
+			},
+			biz:    "login",
+			client: domain.CodeClient{IP: "127.0.0.1", CaptchaToken: "pass"},
+		},
+		{
+			name: "达到硬限制",
+			mock: func(ctrl *gomock.Controller) (CodeSender, repository.CodeRepository,
+				repository.CodeLimitRepository, CaptchaService) {
+				limitRepo := repomocks.NewMockCodeLimitRepository(ctrl)
+				limitRepo.EXPECT().Counts(gomock.Any(), keys).Return([]int64{10, 1}, nil)
+				return svcmocks.NewMockCodeSender(ctrl), repomocks.NewMockCodeRepository(ctrl),
+					limitRepo, svcmocks.NewMockCaptchaService(ctrl)
+			},
+			biz:     "login",
+			client:  domain.CodeClient{IP: "127.0.0.1", CaptchaToken: "pass"},
+			wantErr: ErrCodeSendLimited,
+		},
+		{
+			name: "并发的请求先达到了硬限制",
+			mock: func(ctrl *gomock.Controller) (CodeSender, repository.CodeRepository,
+				repository.CodeLimitRepository, CaptchaService) {
+				limitRepo := repomocks.NewMockCodeLimitRepository(ctrl)
+				limitRepo.EXPECT().Counts(gomock.Any(), keys).Return([]int64{1, 1}, nil)
+				limitRepo.EXPECT().Incr(gomock.Any(), counters).Return(false, nil)
+				return svcmocks.NewMockCodeSender(ctrl), repomocks.NewMockCodeRepository(ctrl),
+					limitRepo, svcmocks.NewMockCaptchaService(ctrl)
+			},
+			biz:     "login",
+			client:  domain.CodeClient{IP: "127.0.0.1"},
+			wantErr: ErrCodeSendLimited,
+		},
+		{
+			name: "没有配置限制的业务",
+			mock: func(ctrl *gomock.Controller) (CodeSender, repository.CodeRepository,
+				repository.CodeLimitRepository, CaptchaService) {
+				sender := svcmocks.NewMockCodeSender(ctrl)
+				codeRepo := repomocks.NewMockCodeRepository(ctrl)
+				codeRepo.EXPECT().Store(gomock.Any(), "bind_phone", "sms:152xxx", gomock.Any(), setting).Return(nil)
+				sender.EXPECT().Send(gomock.Any(), "bind_phone", "152xxx", gomock.Any(), time.Minute*10).Return(nil)
+				return sender, codeRepo, repomocks.NewMockCodeLimitRepository(ctrl),
+					svcmocks.NewMockCaptchaService(ctrl)
+			},
+			biz:    "bind_phone",
+			client: domain.CodeClient{IP: "127.0.0.1"},
+		},
+		{
+			name: "业务不允许这个渠道",
+			mock: func(ctrl *gomock.Controller) (CodeSender, repository.CodeRepository,
+				repository.CodeLimitRepository, CaptchaService) {
+				return svcmocks.NewMockCodeSender(ctrl), repomocks.NewMockCodeRepository(ctrl),
+					repomocks.NewMockCodeLimitRepository(ctrl), svcmocks.NewMockCaptchaService(ctrl)
+			},
+			biz:     "login",
+			channel: domain.CodeChannelEmail,
+			wantErr: ErrCodeChannelUnsupported,
+		},
+		{
+			name: "业务允许但是没有这个渠道",
+			mock: func(ctrl *gomock.Controller) (CodeSender, repository.CodeRepository,
+				repository.CodeLimitRepository, CaptchaService) {
+				return svcmocks.NewMockCodeSender(ctrl), repomocks.NewMockCodeRepository(ctrl),
+					repomocks.NewMockCodeLimitRepository(ctrl), svcmocks.NewMockCaptchaService(ctrl)
+			},
+			biz:     "login",
+			channel: domain.CodeChannelVoice,
+			wantErr: ErrCodeChannelUnsupported,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			sender, codeRepo, limitRepo, captcha := tc.mock(ctrl)
+			svc := NewCodeService(map[domain.CodeChannel]CodeSender{domain.CodeChannelSMS: sender},
+				codeRepo, limitRepo, captcha, configs, []byte("key"), logger.NewZapLogger(zap.NewNop()))
+			target := domain.CodeTarget{Channel: tc.channel, Address: "152xxx"}
+			if tc.channel == "" {
+				target.Channel = domain.CodeChannelSMS
+			}
+			err := svc.Send(context.Background(), tc.biz, target, tc.client)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestMultiChannelCodeService_Verify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// 发送的时候存下来的哈希值，校验的时候输入同样的验证码要得到同样的哈希值
+	var stored string
+	codeRepo := repomocks.NewMockCodeRepository(ctrl)
+	sender := svcmocks.NewMockCodeSender(ctrl)
+	codeRepo.EXPECT().Store(gomock.Any(), "login", "email:a@qq.com", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, biz, target, code string, setting repository.CodeSetting) error {
+			stored = code
+			assert.Equal(t, repository.CodeSetting{TTL: time.Minute * 5, Interval: time.Minute, MaxAttempts: 5}, setting)
+			return nil
+		})
+	var sent string
+	sender.EXPECT().Send(gomock.Any(), "login", "a@qq.com", gomock.Any(), time.Minute*5).
+		DoAndReturn(func(ctx context.Context, biz, address, code string, ttl time.Duration) error {
+			sent = code
+			return nil
+		})
+	codeRepo.EXPECT().Verify(gomock.Any(), "login", "email:a@qq.com", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, biz, target, inputCode string) (bool, error) {
+			return inputCode == stored, nil
+		}).Times(2)
+
+	svc := NewCodeService(map[domain.CodeChannel]CodeSender{domain.CodeChannelEmail: sender},
+		codeRepo, repomocks.NewMockCodeLimitRepository(ctrl), svcmocks.NewMockCaptchaService(ctrl),
+		map[string]CodeConfig{"login": {Length: 8, TTL: time.Minute * 5, MaxAttempts: 5}},
+		[]byte("key"), logger.NewZapLogger(zap.NewNop()))
+	target := domain.CodeTarget{Channel: domain.CodeChannelEmail, Address: "a@qq.com"}
+	err := svc.Send(context.Background(), "login", target, domain.CodeClient{})
+	assert.NoError(t, err)
+	assert.Len(t, sent, 8)
+	// Redis 里面不能是明文
+	assert.NotContains(t, stored, sent)
+
+	ok, err := svc.Verify(context.Background(), "login", target, sent)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = svc.Verify(context.Background(), "login", target, "00000000")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
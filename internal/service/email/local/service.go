@@ -0,0 +1,26 @@
+package local
+
+import (
+	"context"
+	"strings"
+	"webook/pkg/logger"
+)
+
+// Service 本地开发用的邮件服务，并不会真的发送邮件，只是把邮件内容打印到日志里面
+type Service struct {
+	l logger.Logger
+}
+
+func NewService(l logger.Logger) *Service {
+	return &Service{
+		l: l,
+	}
+}
+
+func (s *Service) Send(ctx context.Context, subject, content string, to ...string) error {
+	s.l.Info("模拟发送邮件",
+		logger.String("to", strings.Join(to, ",")),
+		logger.String("subject", subject),
+		logger.String("content", content))
+	return nil
+}
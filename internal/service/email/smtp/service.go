@@ -0,0 +1,44 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// Service 基于标准库 net/smtp 实现的邮件发送
+type Service struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewService 创建一个 SMTP 邮件服务
+// host 和 port 是 SMTP 服务器的地址，username 和 password 用于认证，from 是发件人
+func NewService(host string, port int, username, password, from string) *Service {
+	return &Service{
+		addr: net.JoinHostPort(host, fmt.Sprint(port)),
+		auth: smtp.PlainAuth("", username, password, host),
+		from: from,
+	}
+}
+
+func (s *Service) Send(ctx context.Context, subject, content string, to ...string) error {
+	if len(to) == 0 {
+		return fmt.Errorf("没有指定收件人")
+	}
+	// net/smtp 不支持 context，所以这里只能在发送之前检查一下
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var msg strings.Builder
+	msg.WriteString("From: " + s.from + "\r\n")
+	msg.WriteString("To: " + strings.Join(to, ",") + "\r\n")
+	msg.WriteString("Subject: " + subject + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+	msg.WriteString(content)
+	return smtp.SendMail(s.addr, s.auth, s.from, to, []byte(msg.String()))
+}
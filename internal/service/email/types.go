@@ -0,0 +1,14 @@
+package email
+
+import "context"
+
+// Service 发送邮件的抽象接口
+// 和 sms.Service 一样，目的是屏蔽不同的邮件发送方式，
+// 例如 SMTP、云厂商的邮件推送服务等
+type Service interface {
+	// Send 发送邮件
+	// subject: 邮件标题
+	// content: 邮件正文，按照 HTML 处理
+	// to: 收件人，可以有多个
+	Send(ctx context.Context, subject, content string, to ...string) error
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"golang.org/x/sync/errgroup"
+	"math"
+	"sort"
+	"sync"
+	followv1 "webook/api/proto/gen/follow/v1"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+//go:generate mockgen -source=./feed.go -package=svcmocks -destination=mocks/feed.mock.go FeedService
+type FeedService interface {
+	// HandlePublish 处理文章发表事件
+	// 粉丝少的作者直接推送到粉丝的收件箱，粉丝多的作者只写自己的发件箱，读的时候再拉
+	HandlePublish(ctx context.Context, item domain.FeedItem) error
+	// GetFeed 查询关注流，cursor 是上一页返回的 Next，第一页传零值
+	// 撤回、下线或者作者已经注销的文章会被过滤掉，所以一页可能不满 limit 条
+	GetFeed(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int64) (domain.FeedPage, error)
+}
+
+type feedService struct {
+	repo      repository.FeedRepository
+	artRepo   repository.ArticleRepository
+	followSvc followv1.FollowServiceClient
+	// 粉丝数达到这个数量的作者就不再推送，改为拉模式
+	pushThreshold int64
+	// 分批查询关注关系的批次大小
+	batchSize int64
+	// 最多考虑多少个关注的人，超过的部分在拉模式下会被忽略
+	maxFollowees int64
+	// 一页里面被过滤掉的太多的时候，最多往后再查几次
+	maxRounds int
+}
+
+func NewFeedService(repo repository.FeedRepository, artRepo repository.ArticleRepository,
+	followSvc followv1.FollowServiceClient) FeedService {
+	return &feedService{
+		repo:          repo,
+		artRepo:       artRepo,
+		followSvc:     followSvc,
+		pushThreshold: 1000,
+		batchSize:     100,
+		maxFollowees:  2000,
+		maxRounds:     3,
+	}
+}
+
+func (f *feedService) HandlePublish(ctx context.Context, item domain.FeedItem) error {
+	// 不管推还是拉，发件箱都要写，这样作者从小 V 变成大 V 的时候也不会丢数据
+	err := f.repo.AddToOutbox(ctx, item)
+	if err != nil {
+		return err
+	}
+	resp, err := f.followSvc.GetFollowStatics(ctx, &followv1.GetFollowStaticsRequest{
+		Uid: item.AuthorId,
+	})
+	if err != nil {
+		return err
+	}
+	big := resp.GetStatics().GetFollowers() >= f.pushThreshold
+	err = f.repo.SetBigAuthor(ctx, item.AuthorId, big)
+	if err != nil || big {
+		return err
+	}
+	// 推模式，分批查询粉丝，分批推送
+	var offset int64
+	for {
+		followers, err := f.followSvc.GetFollower(ctx, &followv1.GetFollowerRequest{
+			Followee: item.AuthorId,
+			Offset:   offset,
+			Limit:    f.batchSize,
+		})
+		if err != nil {
+			return err
+		}
+		relations := followers.GetFollowRelations()
+		uids := slice.Map(relations, func(idx int, src *followv1.FollowRelation) int64 {
+			return src.GetFollower()
+		})
+		err = f.repo.PushToInboxes(ctx, uids, item)
+		if err != nil {
+			return err
+		}
+		if int64(len(relations)) < f.batchSize {
+			return nil
+		}
+		offset += f.batchSize
+	}
+}
+
+func (f *feedService) GetFeed(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int64) (domain.FeedPage, error) {
+	if cursor.Ctime <= 0 {
+		cursor = domain.FeedCursor{Ctime: math.MaxInt64}
+	}
+	followees, err := f.followees(ctx, uid)
+	if err != nil {
+		return domain.FeedPage{}, err
+	}
+	// 屏蔽了的作者，即便关注了也不出现在关注流里面
+	followees, err = f.excludeMuted(ctx, uid, followees)
+	if err != nil {
+		return domain.FeedPage{}, err
+	}
+	bigAuthors, err := f.repo.FindBigAuthors(ctx, followees)
+	if err != nil {
+		return domain.FeedPage{}, err
+	}
+
+	page := domain.FeedPage{Items: make([]domain.FeedItem, 0, limit)}
+	for round := 0; round < f.maxRounds; round++ {
+		candidates, err := f.candidates(ctx, uid, followees, bigAuthors, cursor, limit)
+		if err != nil {
+			return domain.FeedPage{}, err
+		}
+		published, err := f.published(ctx, candidates)
+		if err != nil {
+			return domain.FeedPage{}, err
+		}
+		for _, item := range candidates {
+			cursor = item.Cursor()
+			// 收件箱和发件箱里面还有撤回、下线或者作者注销之前的文章
+			art, ok := published[item.Aid]
+			if !ok {
+				continue
+			}
+			item.Article = art
+			page.Items = append(page.Items, item)
+			if int64(len(page.Items)) == limit {
+				page.Next = cursor
+				return page, nil
+			}
+		}
+		if int64(len(candidates)) < limit {
+			// 没有更多了
+			return page, nil
+		}
+	}
+	// 过滤掉的太多了，先返回这些，下一页从最后查过的位置继续
+	page.Next = cursor
+	return page, nil
+}
+
+// published 查询还处于发表状态的文章
+func (f *feedService) published(ctx context.Context, items []domain.FeedItem) (map[int64]domain.Article, error) {
+	arts, err := f.artRepo.ListPubByIds(ctx, slice.Map(items, func(idx int, src domain.FeedItem) int64 {
+		return src.Aid
+	}))
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		res[art.Id] = art
+	}
+	return res, nil
+}
+
+// candidates 从收件箱和大 V 的发件箱里面取出 cursor 之后的 limit 条，还没有过滤文章的状态
+func (f *feedService) candidates(ctx context.Context, uid int64, followees, bigAuthors []int64,
+	cursor domain.FeedCursor, limit int64) ([]domain.FeedItem, error) {
+	var (
+		eg    errgroup.Group
+		mu    sync.Mutex
+		items = make([]domain.FeedItem, 0, limit)
+	)
+	collect := func(res []domain.FeedItem) {
+		mu.Lock()
+		items = append(items, res...)
+		mu.Unlock()
+	}
+	// 推过来的
+	eg.Go(func() error {
+		res, er := f.repo.FindInbox(ctx, uid, cursor, limit)
+		collect(res)
+		return er
+	})
+	// 大 V 的，读的时候拉
+	for _, author := range bigAuthors {
+		author := author
+		eg.Go(func() error {
+			res, er := f.repo.FindOutbox(ctx, author, cursor, limit)
+			collect(res)
+			return er
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return f.merge(items, followees, limit), nil
+}
+
+// merge 去重、过滤掉已经取消关注的作者，然后按照发表时间和文章 ID 倒序取前 limit 条
+func (f *feedService) merge(items []domain.FeedItem, followees []int64, limit int64) []domain.FeedItem {
+	following := make(map[int64]struct{}, len(followees))
+	for _, id := range followees {
+		following[id] = struct{}{}
+	}
+	seen := make(map[int64]struct{}, len(items))
+	res := make([]domain.FeedItem, 0, len(items))
+	for _, item := range items {
+		// 收件箱中可能还有取消关注之前推送过来的
+		if _, ok := following[item.AuthorId]; !ok {
+			continue
+		}
+		// 作者从小 V 变成大 V 之后，收件箱和发件箱可能会有同一篇文章
+		if _, ok := seen[item.Aid]; ok {
+			continue
+		}
+		seen[item.Aid] = struct{}{}
+		res = append(res, item)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Newer(res[j])
+	})
+	if int64(len(res)) > limit {
+		res = res[:limit]
+	}
+	return res
+}
+
+func (f *feedService) excludeMuted(ctx context.Context, uid int64, followees []int64) ([]int64, error) {
+	resp, err := f.followSvc.GetBlockList(ctx, &followv1.GetBlockListRequest{
+		Uid:  uid,
+		Type: followv1.BlockType_BLOCK_TYPE_MUTE,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.GetBlockRelations()) == 0 {
+		return followees, nil
+	}
+	muted := make(map[int64]struct{}, len(resp.GetBlockRelations()))
+	for _, br := range resp.GetBlockRelations() {
+		muted[br.GetTarget()] = struct{}{}
+	}
+	res := make([]int64, 0, len(followees))
+	for _, id := range followees {
+		if _, ok := muted[id]; !ok {
+			res = append(res, id)
+		}
+	}
+	return res, nil
+}
+
+// followees 分批查询关注的人
+func (f *feedService) followees(ctx context.Context, uid int64) ([]int64, error) {
+	res := make([]int64, 0, f.batchSize)
+	for offset := int64(0); offset < f.maxFollowees; offset += f.batchSize {
+		resp, err := f.followSvc.GetFollowee(ctx, &followv1.GetFolloweeRequest{
+			Follower: uid,
+			Offset:   offset,
+			Limit:    f.batchSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		relations := resp.GetFollowRelations()
+		for _, r := range relations {
+			res = append(res, r.GetFollowee())
+		}
+		if int64(len(relations)) < f.batchSize {
+			break
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	followv1 "webook/api/proto/gen/follow/v1"
+	followmocks "webook/api/proto/gen/follow/v1/mocks"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+)
+
+func TestFeedService_GetFeed(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (repository.FeedRepository,
+			repository.ArticleRepository, followv1.FollowServiceClient)
+
+		cursor domain.FeedCursor
+		limit  int64
+
+		wantErr  error
+		wantPage domain.FeedPage
+	}{
+		{
+			name: "推拉结合",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository,
+				repository.ArticleRepository, followv1.FollowServiceClient) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+				followSvc.EXPECT().GetFollowee(gomock.Any(), gomock.Any()).
+					Return(&followv1.GetFolloweeResponse{
+						FollowRelations: []*followv1.FollowRelation{
+							{Follower: 1, Followee: 2},
+							{Follower: 1, Followee: 3},
+							{Follower: 1, Followee: 5},
+						},
+					}, nil)
+				// 5 被屏蔽了
+				followSvc.EXPECT().GetBlockList(gomock.Any(), gomock.Any()).
+					Return(&followv1.GetBlockListResponse{
+						BlockRelations: []*followv1.BlockRelation{
+							{Uid: 1, Target: 5, Type: followv1.BlockType_BLOCK_TYPE_MUTE},
+						},
+					}, nil)
+				repo.EXPECT().FindBigAuthors(gomock.Any(), []int64{2, 3}).
+					Return([]int64{3}, nil)
+				repo.EXPECT().FindInbox(gomock.Any(), int64(1), domain.FeedCursor{Ctime: 1000}, int64(3)).
+					Return([]domain.FeedItem{
+						{Aid: 11, AuthorId: 2, Ctime: 900},
+						// 已经取消关注了
+						{Aid: 12, AuthorId: 4, Ctime: 800},
+						{Aid: 16, AuthorId: 5, Ctime: 750},
+						// 作者变成了大 V，收件箱里面还有
+						{Aid: 13, AuthorId: 3, Ctime: 700},
+						{Aid: 14, AuthorId: 2, Ctime: 100},
+					}, nil)
+				repo.EXPECT().FindOutbox(gomock.Any(), int64(3), domain.FeedCursor{Ctime: 1000}, int64(3)).
+					Return([]domain.FeedItem{
+						{Aid: 15, AuthorId: 3, Ctime: 950},
+						{Aid: 13, AuthorId: 3, Ctime: 700},
+					}, nil)
+				artRepo.EXPECT().ListPubByIds(gomock.Any(), []int64{15, 11, 13}).
+					Return([]domain.Article{{Id: 11}, {Id: 13}, {Id: 15}}, nil)
+				return repo, artRepo, followSvc
+			},
+			cursor: domain.FeedCursor{Ctime: 1000},
+			limit:  3,
+			wantPage: domain.FeedPage{
+				Items: []domain.FeedItem{
+					{Aid: 15, AuthorId: 3, Ctime: 950, Article: domain.Article{Id: 15}},
+					{Aid: 11, AuthorId: 2, Ctime: 900, Article: domain.Article{Id: 11}},
+					{Aid: 13, AuthorId: 3, Ctime: 700, Article: domain.Article{Id: 13}},
+				},
+				Next: domain.FeedCursor{Ctime: 700, Aid: 13},
+			},
+		},
+		{
+			name: "过滤掉撤回和下线的文章，不够一页的时候继续往后查",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository,
+				repository.ArticleRepository, followv1.FollowServiceClient) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+				followSvc.EXPECT().GetFollowee(gomock.Any(), gomock.Any()).
+					Return(&followv1.GetFolloweeResponse{
+						FollowRelations: []*followv1.FollowRelation{
+							{Follower: 1, Followee: 2},
+						},
+					}, nil)
+				followSvc.EXPECT().GetBlockList(gomock.Any(), gomock.Any()).
+					Return(&followv1.GetBlockListResponse{}, nil)
+				repo.EXPECT().FindBigAuthors(gomock.Any(), []int64{2}).Return(nil, nil)
+				gomock.InOrder(
+					repo.EXPECT().FindInbox(gomock.Any(), int64(1), domain.FeedCursor{Ctime: 1000}, int64(2)).
+						Return([]domain.FeedItem{
+							{Aid: 11, AuthorId: 2, Ctime: 900},
+							{Aid: 12, AuthorId: 2, Ctime: 800},
+						}, nil),
+					repo.EXPECT().FindInbox(gomock.Any(), int64(1), domain.FeedCursor{Ctime: 800, Aid: 12}, int64(2)).
+						Return([]domain.FeedItem{
+							{Aid: 13, AuthorId: 2, Ctime: 700},
+						}, nil),
+				)
+				gomock.InOrder(
+					// 11 已经撤回了
+					artRepo.EXPECT().ListPubByIds(gomock.Any(), []int64{11, 12}).
+						Return([]domain.Article{{Id: 12}}, nil),
+					artRepo.EXPECT().ListPubByIds(gomock.Any(), []int64{13}).
+						Return([]domain.Article{{Id: 13}}, nil),
+				)
+				return repo, artRepo, followSvc
+			},
+			cursor: domain.FeedCursor{Ctime: 1000},
+			limit:  2,
+			wantPage: domain.FeedPage{
+				Items: []domain.FeedItem{
+					{Aid: 12, AuthorId: 2, Ctime: 800, Article: domain.Article{Id: 12}},
+					{Aid: 13, AuthorId: 2, Ctime: 700, Article: domain.Article{Id: 13}},
+				},
+				Next: domain.FeedCursor{Ctime: 700, Aid: 13},
+			},
+		},
+		{
+			name: "没有更多了",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository,
+				repository.ArticleRepository, followv1.FollowServiceClient) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+				followSvc.EXPECT().GetFollowee(gomock.Any(), gomock.Any()).
+					Return(&followv1.GetFolloweeResponse{
+						FollowRelations: []*followv1.FollowRelation{
+							{Follower: 1, Followee: 2},
+						},
+					}, nil)
+				followSvc.EXPECT().GetBlockList(gomock.Any(), gomock.Any()).
+					Return(&followv1.GetBlockListResponse{}, nil)
+				repo.EXPECT().FindBigAuthors(gomock.Any(), []int64{2}).Return(nil, nil)
+				repo.EXPECT().FindInbox(gomock.Any(), int64(1), domain.FeedCursor{Ctime: 1000}, int64(3)).
+					Return([]domain.FeedItem{
+						{Aid: 11, AuthorId: 2, Ctime: 900},
+						{Aid: 12, AuthorId: 2, Ctime: 800},
+					}, nil)
+				// 作者注销了，文章都下线了
+				artRepo.EXPECT().ListPubByIds(gomock.Any(), []int64{11, 12}).Return(nil, nil)
+				return repo, artRepo, followSvc
+			},
+			cursor: domain.FeedCursor{Ctime: 1000},
+			limit:  3,
+			wantPage: domain.FeedPage{
+				Items: []domain.FeedItem{},
+			},
+		},
+		{
+			name: "查询关注列表失败",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository,
+				repository.ArticleRepository, followv1.FollowServiceClient) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+				followSvc.EXPECT().GetFollowee(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("mock error"))
+				return repo, repomocks.NewMockArticleRepository(ctrl), followSvc
+			},
+			cursor:  domain.FeedCursor{Ctime: 1000},
+			limit:   3,
+			wantErr: errors.New("mock error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo, followSvc := tc.mock(ctrl)
+			svc := NewFeedService(repo, artRepo, followSvc)
+			page, err := svc.GetFeed(context.Background(), 1, tc.cursor, tc.limit)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantPage, page)
+		})
+	}
+}
+
+func TestFeedService_HandlePublish(t *testing.T) {
+	item := domain.FeedItem{Aid: 11, AuthorId: 2, Ctime: 900}
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (repository.FeedRepository, followv1.FollowServiceClient)
+
+		wantErr error
+	}{
+		{
+			name: "粉丝少，推送",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, followv1.FollowServiceClient) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+				repo.EXPECT().AddToOutbox(gomock.Any(), item).Return(nil)
+				followSvc.EXPECT().GetFollowStatics(gomock.Any(), gomock.Any()).
+					Return(&followv1.GetFollowStaticsResponse{
+						Statics: &followv1.FollowStatics{Followers: 2},
+					}, nil)
+				repo.EXPECT().SetBigAuthor(gomock.Any(), int64(2), false).Return(nil)
+				followSvc.EXPECT().GetFollower(gomock.Any(), gomock.Any()).
+					Return(&followv1.GetFollowerResponse{
+						FollowRelations: []*followv1.FollowRelation{
+							{Follower: 5, Followee: 2},
+							{Follower: 6, Followee: 2},
+						},
+					}, nil)
+				repo.EXPECT().PushToInboxes(gomock.Any(), []int64{5, 6}, item).Return(nil)
+				return repo, followSvc
+			},
+		},
+		{
+			name: "大 V，不推送",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, followv1.FollowServiceClient) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+				repo.EXPECT().AddToOutbox(gomock.Any(), item).Return(nil)
+				followSvc.EXPECT().GetFollowStatics(gomock.Any(), gomock.Any()).
+					Return(&followv1.GetFollowStaticsResponse{
+						Statics: &followv1.FollowStatics{Followers: 100000},
+					}, nil)
+				repo.EXPECT().SetBigAuthor(gomock.Any(), int64(2), true).Return(nil)
+				return repo, followSvc
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, followSvc := tc.mock(ctrl)
+			svc := NewFeedService(repo, repomocks.NewMockArticleRepository(ctrl), followSvc)
+			err := svc.HandlePublish(context.Background(), item)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/email"
+	"webook/pkg/logger"
+	"webook/pkg/ratelimit"
+)
+
+var (
+	ErrMagicLinkSendTooMany = errors.New("发送登录链接太频繁")
+	ErrInvalidMagicLink     = errors.New("登录链接无效或者已经过期")
+)
+
+const (
+	// bizMagicLink 登录链接
+	bizMagicLink = "magic_link"
+	// magicLinkPath 登录链接的路径，%s 部分是令牌
+	// 链接打开的是前端的页面，由用户点击之后再 POST 到 /users/login_link/confirm
+	// 不能直接指向后端的接口，不然邮箱的链接扫描就会把令牌用掉
+	magicLinkPath = "/login_link?token=%s"
+)
+
+//go:generate mockgen -source=./magic_link.go -package=svcmocks -destination=mocks/magic_link.mock.go MagicLinkService
+type MagicLinkService interface {
+	// Send 往邮箱发送登录链接，同一个邮箱发送的频率和短信验证码一样受到限制
+	Send(ctx context.Context, email string) error
+	// Login 使用登录链接登录，没有注册过的邮箱会直接注册
+	// 登录链接只能使用一次
+	Login(ctx context.Context, token string) (domain.User, error)
+}
+
+type magicLinkService struct {
+	tokenRepo repository.TokenRepository
+	userSvc   UserService
+	emailSvc  email.Service
+	// limiter 按照邮箱限流
+	limiter    ratelimit.Limiter
+	l          logger.Logger
+	expiration time.Duration
+	// baseURL 邮件里面的链接的前缀
+	baseURL string
+}
+
+func NewMagicLinkService(tokenRepo repository.TokenRepository, userSvc UserService,
+	emailSvc email.Service, limiter ratelimit.Limiter, baseURL string, l logger.Logger) MagicLinkService {
+	return &magicLinkService{
+		tokenRepo:  tokenRepo,
+		userSvc:    userSvc,
+		emailSvc:   emailSvc,
+		limiter:    limiter,
+		l:          l,
+		expiration: time.Minute * 15,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (svc *magicLinkService) Send(ctx context.Context, email string) error {
+	limited, err := svc.limiter.Limit(ctx, fmt.Sprintf("%s:%s", bizMagicLink, email))
+	if err != nil {
+		return err
+	}
+	if limited {
+		return ErrMagicLinkSendTooMany
+	}
+	// 令牌是 32 字节的随机数，只保存在服务端，猜不到也伪造不了
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	err = svc.tokenRepo.Store(ctx, bizMagicLink, token, email, svc.expiration)
+	if err != nil {
+		return err
+	}
+	link := svc.baseURL + fmt.Sprintf(magicLinkPath, token)
+	content := fmt.Sprintf(`<p>请在 %d 分钟内点击下面的链接登录 webook，链接只能使用一次：</p>
+<p><a href="%s">%s</a></p><p>如果不是你本人操作，请忽略这封邮件。</p>`,
+		int(svc.expiration.Minutes()), link, link)
+	return svc.emailSvc.Send(ctx, "登录 webook", content, email)
+}
+
+func (svc *magicLinkService) Login(ctx context.Context, token string) (domain.User, error) {
+	// 先删除令牌再登录，保证并发的情况下也只能用一次
+	email, err := svc.tokenRepo.Consume(ctx, bizMagicLink, token)
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		return domain.User{}, ErrInvalidMagicLink
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+	return svc.userSvc.FindOrCreateByEmail(ctx, email)
+}
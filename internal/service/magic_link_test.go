@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	emailmocks "webook/internal/service/email/mocks"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+	limitmocks "webook/pkg/ratelimit/mocks"
+)
+
+func TestMagicLinkService_Send(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.TokenRepository, *emailmocks.MockService, *limitmocks.MockLimiter)
+
+		wantErr error
+	}{
+		{
+			name: "发送成功",
+			mock: func(ctrl *gomock.Controller) (repository.TokenRepository, *emailmocks.MockService, *limitmocks.MockLimiter) {
+				tokenRepo := repomocks.NewMockTokenRepository(ctrl)
+				emailSvc := emailmocks.NewMockService(ctrl)
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				limiter.EXPECT().Limit(gomock.Any(), "magic_link:a@qq.com").Return(false, nil)
+				tokenRepo.EXPECT().Store(gomock.Any(), bizMagicLink, gomock.Any(), "a@qq.com", gomock.Any()).
+					Return(nil)
+				// 链接打开的是前端页面，不能直接指向会用掉令牌的接口
+				emailSvc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Cond(func(content string) bool {
+					return strings.Contains(content, "http://localhost:8080/login_link?token=") &&
+						!strings.Contains(content, "/users/login_link/confirm")
+				}), "a@qq.com").Return(nil)
+				return tokenRepo, emailSvc, limiter
+			},
+		},
+		{
+			name: "发送太频繁",
+			mock: func(ctrl *gomock.Controller) (repository.TokenRepository, *emailmocks.MockService, *limitmocks.MockLimiter) {
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				limiter.EXPECT().Limit(gomock.Any(), "magic_link:a@qq.com").Return(true, nil)
+				return repomocks.NewMockTokenRepository(ctrl), emailmocks.NewMockService(ctrl), limiter
+			},
+			wantErr: ErrMagicLinkSendTooMany,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tokenRepo, emailSvc, limiter := tc.mock(ctrl)
+			svc := NewMagicLinkService(tokenRepo, svcmocks.NewMockUserService(ctrl), emailSvc, limiter,
+				"http://localhost:8080", logger.NewZapLogger(zap.NewNop()))
+			err := svc.Send(context.Background(), "a@qq.com")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestMagicLinkService_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	tokenRepo := repomocks.NewMockTokenRepository(ctrl)
+	userSvc := svcmocks.NewMockUserService(ctrl)
+	tokenRepo.EXPECT().Consume(gomock.Any(), bizMagicLink, "t1").Return("a@qq.com", nil)
+	userSvc.EXPECT().FindOrCreateByEmail(gomock.Any(), "a@qq.com").
+		Return(domain.User{Id: 123, Email: "a@qq.com"}, nil)
+	// 已经用过的链接
+	tokenRepo.EXPECT().Consume(gomock.Any(), bizMagicLink, "t1").Return("", repository.ErrTokenNotFound)
+	svc := NewMagicLinkService(tokenRepo, userSvc, emailmocks.NewMockService(ctrl),
+		limitmocks.NewMockLimiter(ctrl), "http://localhost:8080", logger.NewZapLogger(zap.NewNop()))
+
+	u, err := svc.Login(context.Background(), "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123), u.Id)
+	_, err = svc.Login(context.Background(), "t1")
+	assert.Equal(t, ErrInvalidMagicLink, err)
+}
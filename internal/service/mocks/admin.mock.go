@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/admin.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/admin.go -package=svcmocks -destination=./internal/service/mocks/admin.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdminService is a mock of AdminService interface.
+type MockAdminService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAdminServiceMockRecorder is the mock recorder for MockAdminService.
+type MockAdminServiceMockRecorder struct {
+	mock *MockAdminService
+}
+
+// NewMockAdminService creates a new mock instance.
+func NewMockAdminService(ctrl *gomock.Controller) *MockAdminService {
+	mock := &MockAdminService{ctrl: ctrl}
+	mock.recorder = &MockAdminServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminService) EXPECT() *MockAdminServiceMockRecorder {
+	return m.recorder
+}
+
+// BanUser mocks base method.
+func (m *MockAdminService) BanUser(ctx context.Context, operator, uid int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanUser", ctx, operator, uid, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BanUser indicates an expected call of BanUser.
+func (mr *MockAdminServiceMockRecorder) BanUser(ctx, operator, uid, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanUser", reflect.TypeOf((*MockAdminService)(nil).BanUser), ctx, operator, uid, reason)
+}
+
+// ListAuditLogs mocks base method.
+func (m *MockAdminService) ListAuditLogs(ctx context.Context, offset, limit int) ([]domain.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogs", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogs indicates an expected call of ListAuditLogs.
+func (mr *MockAdminServiceMockRecorder) ListAuditLogs(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogs", reflect.TypeOf((*MockAdminService)(nil).ListAuditLogs), ctx, offset, limit)
+}
+
+// SearchUsers mocks base method.
+func (m *MockAdminService) SearchUsers(ctx context.Context, keyword string, offset, limit int) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", ctx, keyword, offset, limit)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockAdminServiceMockRecorder) SearchUsers(ctx, keyword, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockAdminService)(nil).SearchUsers), ctx, keyword, offset, limit)
+}
+
+// UnbanUser mocks base method.
+func (m *MockAdminService) UnbanUser(ctx context.Context, operator, uid int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbanUser", ctx, operator, uid, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnbanUser indicates an expected call of UnbanUser.
+func (mr *MockAdminServiceMockRecorder) UnbanUser(ctx, operator, uid, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbanUser", reflect.TypeOf((*MockAdminService)(nil).UnbanUser), ctx, operator, uid, reason)
+}
+
+// UnpublishArticle mocks base method.
+func (m *MockAdminService) UnpublishArticle(ctx context.Context, operator, aid int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpublishArticle", ctx, operator, aid, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpublishArticle indicates an expected call of UnpublishArticle.
+func (mr *MockAdminServiceMockRecorder) UnpublishArticle(ctx, operator, aid, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpublishArticle", reflect.TypeOf((*MockAdminService)(nil).UnpublishArticle), ctx, operator, aid, reason)
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./consumer.go
+// Source: ./article.go
 //
 // Generated by this command:
 //
-//	mockgen -source=./consumer.go -package=svcmocks -destination=mocks/article.mock.go ArticleService
+//	mockgen -source=./article.go -package=svcmocks -destination=mocks/article.mock.go ArticleService
 //
 
 // Package svcmocks is a generated GoMock package.
@@ -57,6 +57,21 @@ func (mr *MockArticleServiceMockRecorder) GetById(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockArticleService)(nil).GetById), ctx, id)
 }
 
+// GetPublishedAuthor mocks base method.
+func (m *MockArticleService) GetPublishedAuthor(ctx context.Context, id int64) (domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublishedAuthor", ctx, id)
+	ret0, _ := ret[0].(domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublishedAuthor indicates an expected call of GetPublishedAuthor.
+func (mr *MockArticleServiceMockRecorder) GetPublishedAuthor(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedAuthor", reflect.TypeOf((*MockArticleService)(nil).GetPublishedAuthor), ctx, id)
+}
+
 // GetPublishedById mocks base method.
 func (m *MockArticleService) GetPublishedById(ctx context.Context, id, uid int64) (domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -102,6 +117,21 @@ func (mr *MockArticleServiceMockRecorder) ListPub(ctx, startTime, offset, limit
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleService)(nil).ListPub), ctx, startTime, offset, limit)
 }
 
+// ListPubByAuthor mocks base method.
+func (m *MockArticleService) ListPubByAuthor(ctx context.Context, author int64, offset, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByAuthor", ctx, author, offset, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByAuthor indicates an expected call of ListPubByAuthor.
+func (mr *MockArticleServiceMockRecorder) ListPubByAuthor(ctx, author, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByAuthor", reflect.TypeOf((*MockArticleService)(nil).ListPubByAuthor), ctx, author, offset, limit)
+}
+
 // Publish mocks base method.
 func (m *MockArticleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
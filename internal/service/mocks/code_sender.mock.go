@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/code_sender.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/code_sender.go -package=svcmocks -destination=./internal/service/mocks/code_sender.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCodeSender is a mock of CodeSender interface.
+type MockCodeSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockCodeSenderMockRecorder is the mock recorder for MockCodeSender.
+type MockCodeSenderMockRecorder struct {
+	mock *MockCodeSender
+}
+
+// NewMockCodeSender creates a new mock instance.
+func NewMockCodeSender(ctrl *gomock.Controller) *MockCodeSender {
+	mock := &MockCodeSender{ctrl: ctrl}
+	mock.recorder = &MockCodeSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeSender) EXPECT() *MockCodeSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockCodeSender) Send(ctx context.Context, biz, address, code string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, biz, address, code, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockCodeSenderMockRecorder) Send(ctx, biz, address, code, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockCodeSender)(nil).Send), ctx, biz, address, code, ttl)
+}
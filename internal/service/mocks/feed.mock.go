@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/feed.go -package=svcmocks -destination=./internal/service/mocks/feed.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedService is a mock of FeedService interface.
+type MockFeedService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedServiceMockRecorder is the mock recorder for MockFeedService.
+type MockFeedServiceMockRecorder struct {
+	mock *MockFeedService
+}
+
+// NewMockFeedService creates a new mock instance.
+func NewMockFeedService(ctrl *gomock.Controller) *MockFeedService {
+	mock := &MockFeedService{ctrl: ctrl}
+	mock.recorder = &MockFeedServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedService) EXPECT() *MockFeedServiceMockRecorder {
+	return m.recorder
+}
+
+// GetFeed mocks base method.
+func (m *MockFeedService) GetFeed(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int64) (domain.FeedPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, uid, cursor, limit)
+	ret0, _ := ret[0].(domain.FeedPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockFeedServiceMockRecorder) GetFeed(ctx, uid, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockFeedService)(nil).GetFeed), ctx, uid, cursor, limit)
+}
+
+// HandlePublish mocks base method.
+func (m *MockFeedService) HandlePublish(ctx context.Context, item domain.FeedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandlePublish", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandlePublish indicates an expected call of HandlePublish.
+func (mr *MockFeedServiceMockRecorder) HandlePublish(ctx, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandlePublish", reflect.TypeOf((*MockFeedService)(nil).HandlePublish), ctx, item)
+}
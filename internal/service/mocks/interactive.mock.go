@@ -69,6 +69,20 @@ func (mr *MockInteractiveServiceMockRecorder) Collect(ctx, biz, bizId, cid, uid
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockInteractiveService)(nil).Collect), ctx, biz, bizId, cid, uid)
 }
 
+// DeleteUserData mocks base method.
+func (m *MockInteractiveService) DeleteUserData(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserData", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserData indicates an expected call of DeleteUserData.
+func (mr *MockInteractiveServiceMockRecorder) DeleteUserData(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserData", reflect.TypeOf((*MockInteractiveService)(nil).DeleteUserData), ctx, uid)
+}
+
 // Get mocks base method.
 func (m *MockInteractiveService) Get(ctx context.Context, biz string, bizId, uid int64) (domain.Interactive, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +113,36 @@ func (mr *MockInteractiveServiceMockRecorder) GetByIds(ctx, biz, bizIds any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIds", reflect.TypeOf((*MockInteractiveService)(nil).GetByIds), ctx, biz, bizIds)
 }
 
+// GetUserCollects mocks base method.
+func (m *MockInteractiveService) GetUserCollects(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCollects", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.UserBizRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCollects indicates an expected call of GetUserCollects.
+func (mr *MockInteractiveServiceMockRecorder) GetUserCollects(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCollects", reflect.TypeOf((*MockInteractiveService)(nil).GetUserCollects), ctx, uid, offset, limit)
+}
+
+// GetUserLikes mocks base method.
+func (m *MockInteractiveService) GetUserLikes(ctx context.Context, uid int64, offset, limit int) ([]domain.UserBizRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLikes", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.UserBizRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLikes indicates an expected call of GetUserLikes.
+func (mr *MockInteractiveServiceMockRecorder) GetUserLikes(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLikes", reflect.TypeOf((*MockInteractiveService)(nil).GetUserLikes), ctx, uid, offset, limit)
+}
+
 // IncrReadCnt mocks base method.
 func (m *MockInteractiveService) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/magic_link.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/magic_link.go -package=svcmocks -destination=./internal/service/mocks/magic_link.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMagicLinkService is a mock of MagicLinkService interface.
+type MockMagicLinkService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMagicLinkServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockMagicLinkServiceMockRecorder is the mock recorder for MockMagicLinkService.
+type MockMagicLinkServiceMockRecorder struct {
+	mock *MockMagicLinkService
+}
+
+// NewMockMagicLinkService creates a new mock instance.
+func NewMockMagicLinkService(ctrl *gomock.Controller) *MockMagicLinkService {
+	mock := &MockMagicLinkService{ctrl: ctrl}
+	mock.recorder = &MockMagicLinkServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMagicLinkService) EXPECT() *MockMagicLinkServiceMockRecorder {
+	return m.recorder
+}
+
+// Login mocks base method.
+func (m *MockMagicLinkService) Login(ctx context.Context, token string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, token)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockMagicLinkServiceMockRecorder) Login(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockMagicLinkService)(nil).Login), ctx, token)
+}
+
+// Send mocks base method.
+func (m *MockMagicLinkService) Send(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMagicLinkServiceMockRecorder) Send(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMagicLinkService)(nil).Send), ctx, email)
+}
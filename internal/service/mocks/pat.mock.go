@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/pat.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/pat.go -package=svcmocks -destination=./internal/service/mocks/pat.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPATService is a mock of PATService interface.
+type MockPATService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPATServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPATServiceMockRecorder is the mock recorder for MockPATService.
+type MockPATServiceMockRecorder struct {
+	mock *MockPATService
+}
+
+// NewMockPATService creates a new mock instance.
+func NewMockPATService(ctrl *gomock.Controller) *MockPATService {
+	mock := &MockPATService{ctrl: ctrl}
+	mock.recorder = &MockPATServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPATService) EXPECT() *MockPATServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPATService) Create(ctx context.Context, uid int64, name string, scopes []domain.Scope, ttl time.Duration) (string, domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, uid, name, scopes, ttl)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(domain.PersonalAccessToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPATServiceMockRecorder) Create(ctx, uid, name, scopes, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPATService)(nil).Create), ctx, uid, name, scopes, ttl)
+}
+
+// List mocks base method.
+func (m *MockPATService) List(ctx context.Context, uid int64) ([]domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, uid)
+	ret0, _ := ret[0].([]domain.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPATServiceMockRecorder) List(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPATService)(nil).List), ctx, uid)
+}
+
+// Revoke mocks base method.
+func (m *MockPATService) Revoke(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockPATServiceMockRecorder) Revoke(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockPATService)(nil).Revoke), ctx, uid, id)
+}
+
+// Verify mocks base method.
+func (m *MockPATService) Verify(ctx context.Context, token string) (domain.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, token)
+	ret0, _ := ret[0].(domain.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockPATServiceMockRecorder) Verify(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockPATService)(nil).Verify), ctx, token)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/qr_login.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/qr_login.go -package=svcmocks -destination=./internal/service/mocks/qr_login.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQRLoginService is a mock of QRLoginService interface.
+type MockQRLoginService struct {
+	ctrl     *gomock.Controller
+	recorder *MockQRLoginServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockQRLoginServiceMockRecorder is the mock recorder for MockQRLoginService.
+type MockQRLoginServiceMockRecorder struct {
+	mock *MockQRLoginService
+}
+
+// NewMockQRLoginService creates a new mock instance.
+func NewMockQRLoginService(ctrl *gomock.Controller) *MockQRLoginService {
+	mock := &MockQRLoginService{ctrl: ctrl}
+	mock.recorder = &MockQRLoginServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQRLoginService) EXPECT() *MockQRLoginServiceMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockQRLoginService) Confirm(ctx context.Context, ticket string, uid int64, approve bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, ticket, uid, approve)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockQRLoginServiceMockRecorder) Confirm(ctx, ticket, uid, approve any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockQRLoginService)(nil).Confirm), ctx, ticket, uid, approve)
+}
+
+// Create mocks base method.
+func (m *MockQRLoginService) Create(ctx context.Context, userAgent, ip string) (domain.QRLoginTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userAgent, ip)
+	ret0, _ := ret[0].(domain.QRLoginTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQRLoginServiceMockRecorder) Create(ctx, userAgent, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQRLoginService)(nil).Create), ctx, userAgent, ip)
+}
+
+// Scan mocks base method.
+func (m *MockQRLoginService) Scan(ctx context.Context, ticket string, uid int64) (domain.QRLoginTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", ctx, ticket, uid)
+	ret0, _ := ret[0].(domain.QRLoginTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockQRLoginServiceMockRecorder) Scan(ctx, ticket, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockQRLoginService)(nil).Scan), ctx, ticket, uid)
+}
+
+// Wait mocks base method.
+func (m *MockQRLoginService) Wait(ctx context.Context, ticket, secret string, known domain.QRLoginStatus, timeout time.Duration) (domain.QRLoginTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Wait", ctx, ticket, secret, known, timeout)
+	ret0, _ := ret[0].(domain.QRLoginTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Wait indicates an expected call of Wait.
+func (mr *MockQRLoginServiceMockRecorder) Wait(ctx, ticket, secret, known, timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wait", reflect.TypeOf((*MockQRLoginService)(nil).Wait), ctx, ticket, secret, known, timeout)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/service/sms_record.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/service/sms_record.go -package=svcmocks -destination=internal/service/mocks/sms_record.mock.go SMSRecordService
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSMSRecordService is a mock of SMSRecordService interface.
+type MockSMSRecordService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSMSRecordServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSMSRecordServiceMockRecorder is the mock recorder for MockSMSRecordService.
+type MockSMSRecordServiceMockRecorder struct {
+	mock *MockSMSRecordService
+}
+
+// NewMockSMSRecordService creates a new mock instance.
+func NewMockSMSRecordService(ctrl *gomock.Controller) *MockSMSRecordService {
+	mock := &MockSMSRecordService{ctrl: ctrl}
+	mock.recorder = &MockSMSRecordServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSMSRecordService) EXPECT() *MockSMSRecordServiceMockRecorder {
+	return m.recorder
+}
+
+// HandleReceipts mocks base method.
+func (m *MockSMSRecordService) HandleReceipts(ctx context.Context, provider string, receipts []domain.SMSReceipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleReceipts", ctx, provider, receipts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleReceipts indicates an expected call of HandleReceipts.
+func (mr *MockSMSRecordServiceMockRecorder) HandleReceipts(ctx, provider, receipts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleReceipts", reflect.TypeOf((*MockSMSRecordService)(nil).HandleReceipts), ctx, provider, receipts)
+}
+
+// ListByPhone mocks base method.
+func (m *MockSMSRecordService) ListByPhone(ctx context.Context, phone string, offset, limit int) ([]domain.SMSRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPhone", ctx, phone, offset, limit)
+	ret0, _ := ret[0].([]domain.SMSRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPhone indicates an expected call of ListByPhone.
+func (mr *MockSMSRecordServiceMockRecorder) ListByPhone(ctx, phone, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPhone", reflect.TypeOf((*MockSMSRecordService)(nil).ListByPhone), ctx, phone, offset, limit)
+}
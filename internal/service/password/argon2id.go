@@ -0,0 +1,114 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+// Argon2idParams argon2id 的参数
+type Argon2idParams struct {
+	// Memory 使用的内存，KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams 参考 OWASP 的推荐值
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Argon2idHasher 哈希值使用 PHC 字符串格式：
+// $argon2id$v=19$m=65536,t=3,p=2$盐$哈希，盐和哈希都是不带填充的 base64
+type Argon2idHasher struct {
+	params Argon2idParams
+}
+
+func NewArgon2idHasher(params Argon2idParams) *Argon2idHasher {
+	return &Argon2idHasher{params: params}
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations,
+		h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+func (h *Argon2idHasher) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := h.decode(encoded)
+	if err != nil {
+		return false, err
+	}
+	// 用哈希值里面记录的参数，而不是当前的参数，这样调整参数之后旧的哈希值依旧能校验
+	actual := argon2.IDKey([]byte(password), salt, params.Iterations,
+		params.Memory, params.Parallelism, params.KeyLength)
+	return subtle.ConstantTimeCompare(key, actual) == 1, nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, _, err := h.decode(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.params.Memory ||
+		params.Iterations != h.params.Iterations ||
+		params.Parallelism != h.params.Parallelism ||
+		params.KeyLength != h.params.KeyLength
+}
+
+func (h *Argon2idHasher) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+func (h *Argon2idHasher) decode(encoded string) (Argon2idParams, []byte, []byte, error) {
+	// 切分之后是 "", "argon2id", "v=19", "m=...,t=...,p=...", 盐, 哈希
+	segs := strings.Split(encoded, "$")
+	if len(segs) != 6 || segs[1] != "argon2id" {
+		return Argon2idParams{}, nil, nil, ErrUnknownAlgorithm
+	}
+	var version int
+	_, err := fmt.Sscanf(segs[2], "v=%d", &version)
+	if err != nil {
+		return Argon2idParams{}, nil, nil, err
+	}
+	if version != argon2.Version {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("不支持的 argon2 版本 %d", version)
+	}
+	var params Argon2idParams
+	_, err = fmt.Sscanf(segs[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil {
+		return Argon2idParams{}, nil, nil, err
+	}
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(segs[4])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, err
+	}
+	key, err := enc.DecodeString(segs[5])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, err
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
@@ -0,0 +1,53 @@
+package password
+
+// AlgorithmHasher 能够根据哈希值的前缀判断是不是自己的格式
+type AlgorithmHasher interface {
+	Hasher
+	Supports(encoded string) bool
+}
+
+// MultiHasher 用 primary 计算新的哈希值，校验的时候根据哈希值的前缀选择对应的算法
+// 所以从 bcrypt 切换到 argon2id 之后，老用户依旧能登录，并且登录的时候会重新计算哈希值
+type MultiHasher struct {
+	primary AlgorithmHasher
+	others  []AlgorithmHasher
+}
+
+func NewMultiHasher(primary AlgorithmHasher, others ...AlgorithmHasher) *MultiHasher {
+	return &MultiHasher{
+		primary: primary,
+		others:  others,
+	}
+}
+
+func (m *MultiHasher) Hash(password string) (string, error) {
+	return m.primary.Hash(password)
+}
+
+func (m *MultiHasher) Verify(password, encoded string) (bool, error) {
+	h, ok := m.find(encoded)
+	if !ok {
+		return false, ErrUnknownAlgorithm
+	}
+	return h.Verify(password, encoded)
+}
+
+// NeedsRehash 不是 primary 的算法，或者参数和 primary 的不一样，都需要重新计算
+func (m *MultiHasher) NeedsRehash(encoded string) bool {
+	if !m.primary.Supports(encoded) {
+		return true
+	}
+	return m.primary.NeedsRehash(encoded)
+}
+
+func (m *MultiHasher) find(encoded string) (Hasher, bool) {
+	if m.primary.Supports(encoded) {
+		return m.primary, true
+	}
+	for _, h := range m.others {
+		if h.Supports(encoded) {
+			return h, true
+		}
+	}
+	return nil, false
+}
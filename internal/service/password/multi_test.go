@@ -0,0 +1,99 @@
+package password
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+// 测试用的参数小一点，不然跑得太慢
+var testArgon2idParams = Argon2idParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestMultiHasher(t *testing.T) {
+	bc := NewBcryptHasher(bcrypt.MinCost)
+	argon := NewArgon2idHasher(testArgon2idParams)
+	bcHash, err := bc.Hash("hello#world123")
+	require.NoError(t, err)
+	argonHash, err := argon.Hash("hello#world123")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(argonHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	// 参数调整过之后的 argon2id
+	strongerHash, err := NewArgon2idHasher(Argon2idParams{
+		Memory: 2048, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32,
+	}).Hash("hello#world123")
+	require.NoError(t, err)
+
+	hasher := NewMultiHasher(argon, bc)
+	testCases := []struct {
+		name     string
+		password string
+		encoded  string
+
+		wantOk     bool
+		wantErr    error
+		wantRehash bool
+	}{
+		{
+			name:     "argon2id 密码正确",
+			password: "hello#world123",
+			encoded:  argonHash,
+			wantOk:   true,
+		},
+		{
+			name:     "argon2id 密码错误",
+			password: "hello#world",
+			encoded:  argonHash,
+		},
+		{
+			name:     "argon2id 参数过时",
+			password: "hello#world123",
+			encoded:  strongerHash,
+			wantOk:   true,
+			// 参数和现在的不一样就要重新计算，不管是变强了还是变弱了
+			wantRehash: true,
+		},
+		{
+			name:       "bcrypt 老密码",
+			password:   "hello#world123",
+			encoded:    bcHash,
+			wantOk:     true,
+			wantRehash: true,
+		},
+		{
+			name:       "bcrypt 密码错误",
+			password:   "hello#world",
+			encoded:    bcHash,
+			wantRehash: true,
+		},
+		{
+			name:       "未知的格式",
+			password:   "hello#world123",
+			encoded:    "hello#world123",
+			wantErr:    ErrUnknownAlgorithm,
+			wantRehash: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, err := hasher.Verify(tc.password, tc.encoded)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantOk, ok)
+			assert.Equal(t, tc.wantRehash, hasher.NeedsRehash(tc.encoded))
+		})
+	}
+}
+
+func TestBcryptHasher_NeedsRehash(t *testing.T) {
+	hash, err := NewBcryptHasher(bcrypt.MinCost).Hash("hello#world123")
+	require.NoError(t, err)
+	assert.False(t, NewBcryptHasher(bcrypt.MinCost).NeedsRehash(hash))
+	assert.True(t, NewBcryptHasher(bcrypt.MinCost+1).NeedsRehash(hash))
+}
@@ -0,0 +1,132 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// PolicyError 密码不符合要求，Error 的内容可以直接展示给用户
+type PolicyError struct {
+	Msg string
+}
+
+func (e *PolicyError) Error() string {
+	return e.Msg
+}
+
+// ErrCommonPassword 密码在常见密码列表里面
+var ErrCommonPassword = &PolicyError{Msg: "密码太常见了，请换一个"}
+
+// PolicyConfig 密码策略的配置
+type PolicyConfig struct {
+	MinLength int `yaml:"minLength"`
+	// MaxLength bcrypt 只会用前 72 个字节
+	MaxLength     int  `yaml:"maxLength"`
+	RequireLetter bool `yaml:"requireLetter"`
+	RequireDigit  bool `yaml:"requireDigit"`
+	RequireSymbol bool `yaml:"requireSymbol"`
+	// CommonList 额外的常见密码列表文件，一行一个，例如泄露过的密码
+	CommonList string `yaml:"commonList"`
+}
+
+// Policy 设置密码的时候校验密码的强度
+type Policy struct {
+	cfg    PolicyConfig
+	common map[string]struct{}
+}
+
+func NewPolicy(cfg PolicyConfig) (*Policy, error) {
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = 8
+	}
+	if cfg.MaxLength <= 0 {
+		cfg.MaxLength = 64
+	}
+	p := &Policy{cfg: cfg, common: make(map[string]struct{}, 128)}
+	err := p.load(strings.NewReader(commonPasswords))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.CommonList != "" {
+		f, err := os.Open(cfg.CommonList)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		err = p.load(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *Policy) load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.common[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Validate 密码不符合要求的时候返回 *PolicyError
+func (p *Policy) Validate(password string) error {
+	length := len([]rune(password))
+	if length < p.cfg.MinLength || length > p.cfg.MaxLength {
+		return &PolicyError{Msg: fmt.Sprintf("密码长度必须在 %d 到 %d 位之间",
+			p.cfg.MinLength, p.cfg.MaxLength)}
+	}
+	var letter, digit, symbol bool
+	for _, c := range password {
+		switch {
+		case unicode.IsLetter(c):
+			letter = true
+		case unicode.IsDigit(c):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if (p.cfg.RequireLetter && !letter) ||
+		(p.cfg.RequireDigit && !digit) ||
+		(p.cfg.RequireSymbol && !symbol) {
+		return &PolicyError{Msg: p.compositionMsg()}
+	}
+	if _, ok := p.common[strings.ToLower(password)]; ok {
+		return ErrCommonPassword
+	}
+	return nil
+}
+
+func (p *Policy) compositionMsg() string {
+	var parts []string
+	if p.cfg.RequireLetter {
+		parts = append(parts, "字母")
+	}
+	if p.cfg.RequireDigit {
+		parts = append(parts, "数字")
+	}
+	if p.cfg.RequireSymbol {
+		parts = append(parts, "特殊字符")
+	}
+	return fmt.Sprintf("密码必须包含%s", strings.Join(parts, "、"))
+}
+
+// IsPolicyError 是不是密码不符合要求
+func IsPolicyError(err error) bool {
+	var pe *PolicyError
+	return errors.As(err, &pe)
+}
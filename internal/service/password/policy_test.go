@@ -0,0 +1,69 @@
+package password
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	// 额外的泄露密码列表
+	list := filepath.Join(t.TempDir(), "leaked.txt")
+	require.NoError(t, os.WriteFile(list, []byte("# 泄露的密码\nLeaked#2024\n"), 0644))
+	p, err := NewPolicy(PolicyConfig{
+		MinLength:     8,
+		MaxLength:     16,
+		RequireLetter: true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		CommonList:    list,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		password string
+		wantErr  error
+	}{
+		{
+			name:     "符合要求",
+			password: "hello#world123",
+		},
+		{
+			name:     "太短",
+			password: "h#1",
+			wantErr:  &PolicyError{Msg: "密码长度必须在 8 到 16 位之间"},
+		},
+		{
+			name:     "太长",
+			password: "hello#world123456",
+			wantErr:  &PolicyError{Msg: "密码长度必须在 8 到 16 位之间"},
+		},
+		{
+			name:     "没有特殊字符",
+			password: "helloworld123",
+			wantErr:  &PolicyError{Msg: "密码必须包含字母、数字、特殊字符"},
+		},
+		{
+			name:     "内置的常见密码，不区分大小写",
+			password: "P@SSW0RD",
+			wantErr:  ErrCommonPassword,
+		},
+		{
+			name:     "配置的泄露密码",
+			password: "leaked#2024",
+			wantErr:  ErrCommonPassword,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := p.Validate(tc.password)
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				assert.True(t, IsPolicyError(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package password
+
+import "errors"
+
+// ErrUnknownAlgorithm 哈希值的格式不认识
+var ErrUnknownAlgorithm = errors.New("未知的密码哈希算法")
+
+// Hasher 计算和校验密码的哈希值
+// 哈希值里面带着算法的前缀和参数，所以换了算法或者调整了参数之后，旧的哈希值依旧能校验
+type Hasher interface {
+	Hash(password string) (string, error)
+	// Verify 校验密码，密码不对的时候返回 false 和 nil
+	Verify(password, encoded string) (bool, error)
+	// NeedsRehash 哈希值使用的算法或者参数是不是已经过时了
+	NeedsRehash(encoded string) bool
+}
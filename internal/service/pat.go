@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+var (
+	ErrInvalidScope  = errors.New("未知的权限范围")
+	ErrTooManyPATs   = errors.New("访问令牌太多了")
+	ErrPATNotFound   = errors.New("访问令牌不存在")
+	ErrInvalidPAT    = errors.New("访问令牌无效或者已经过期")
+	ErrInvalidPATTTL = errors.New("访问令牌的有效期不对")
+)
+
+const (
+	// maxPATsPerUser 每个用户最多可以创建的访问令牌数量
+	maxPATsPerUser = 20
+	// maxPATTTL 访问令牌最长的有效期
+	maxPATTTL = time.Hour * 24 * 365
+)
+
+//go:generate mockgen -source=./pat.go -package=svcmocks -destination=mocks/pat.mock.go PATService
+type PATService interface {
+	// Create 创建访问令牌，返回的令牌只有这一次机会能看到
+	Create(ctx context.Context, uid int64, name string, scopes []domain.Scope, ttl time.Duration) (string, domain.PersonalAccessToken, error)
+	List(ctx context.Context, uid int64) ([]domain.PersonalAccessToken, error)
+	// Revoke 撤销访问令牌，只能撤销自己的
+	Revoke(ctx context.Context, uid, id int64) error
+	// Verify 校验访问令牌，登录校验的中间件调用
+	Verify(ctx context.Context, token string) (domain.PersonalAccessToken, error)
+}
+
+type patService struct {
+	repo     repository.PATRepository
+	userRepo repository.UserRepository
+}
+
+func NewPATService(repo repository.PATRepository, userRepo repository.UserRepository) PATService {
+	return &patService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (svc *patService) Create(ctx context.Context, uid int64, name string,
+	scopes []domain.Scope, ttl time.Duration) (string, domain.PersonalAccessToken, error) {
+	if len(scopes) == 0 {
+		return "", domain.PersonalAccessToken{}, ErrInvalidScope
+	}
+	for _, s := range scopes {
+		if !s.Valid() {
+			return "", domain.PersonalAccessToken{}, ErrInvalidScope
+		}
+	}
+	if ttl <= 0 || ttl > maxPATTTL {
+		return "", domain.PersonalAccessToken{}, ErrInvalidPATTTL
+	}
+	cnt, err := svc.repo.Count(ctx, uid)
+	if err != nil {
+		return "", domain.PersonalAccessToken{}, err
+	}
+	if cnt >= maxPATsPerUser {
+		return "", domain.PersonalAccessToken{}, ErrTooManyPATs
+	}
+
+	token, err := svc.generate()
+	if err != nil {
+		return "", domain.PersonalAccessToken{}, err
+	}
+	t := domain.PersonalAccessToken{
+		Uid:        uid,
+		Name:       name,
+		Scopes:     scopes,
+		Expiration: time.Now().Add(ttl),
+		Ctime:      time.Now(),
+	}
+	t.Id, err = svc.repo.Create(ctx, t, svc.hash(token))
+	return token, t, err
+}
+
+func (svc *patService) List(ctx context.Context, uid int64) ([]domain.PersonalAccessToken, error) {
+	return svc.repo.List(ctx, uid)
+}
+
+func (svc *patService) Revoke(ctx context.Context, uid, id int64) error {
+	err := svc.repo.Delete(ctx, uid, id)
+	if errors.Is(err, repository.ErrPATNotFound) {
+		return ErrPATNotFound
+	}
+	return err
+}
+
+func (svc *patService) Verify(ctx context.Context, token string) (domain.PersonalAccessToken, error) {
+	if !strings.HasPrefix(token, domain.PATPrefix) {
+		return domain.PersonalAccessToken{}, ErrInvalidPAT
+	}
+	t, err := svc.repo.FindByHash(ctx, svc.hash(token))
+	if errors.Is(err, repository.ErrPATNotFound) {
+		return domain.PersonalAccessToken{}, ErrInvalidPAT
+	}
+	if err != nil {
+		return domain.PersonalAccessToken{}, err
+	}
+	if t.Expired(time.Now()) {
+		return domain.PersonalAccessToken{}, ErrInvalidPAT
+	}
+	// 封禁用户的时候只会踢掉登录会话，所以这里还要确认用户没有被封禁
+	u, err := svc.userRepo.FindById(ctx, t.Uid)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return domain.PersonalAccessToken{}, ErrInvalidPAT
+	}
+	if err != nil {
+		return domain.PersonalAccessToken{}, err
+	}
+	if u.Banned {
+		return domain.PersonalAccessToken{}, ErrUserBanned
+	}
+	return t, nil
+}
+
+// generate 32 个字节的随机数，加上前缀
+func (svc *patService) generate() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return domain.PATPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hash 令牌本身就是足够长的随机数，所以用 SHA256 就可以了，不需要加盐或者慢哈希
+func (svc *patService) hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+)
+
+func TestPATService_Verify(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (repository.PATRepository, repository.UserRepository)
+
+		token string
+
+		wantUid int64
+		wantErr error
+	}{
+		{
+			name: "校验通过",
+			mock: func(ctrl *gomock.Controller) (repository.PATRepository, repository.UserRepository) {
+				repo := repomocks.NewMockPATRepository(ctrl)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).
+					Return(domain.PersonalAccessToken{
+						Uid:        123,
+						Expiration: time.Now().Add(time.Hour),
+					}, nil)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123}, nil)
+				return repo, userRepo
+			},
+			token:   "wbk_abc",
+			wantUid: 123,
+		},
+		{
+			name: "不是访问令牌",
+			mock: func(ctrl *gomock.Controller) (repository.PATRepository, repository.UserRepository) {
+				return repomocks.NewMockPATRepository(ctrl), repomocks.NewMockUserRepository(ctrl)
+			},
+			token:   "abc",
+			wantErr: ErrInvalidPAT,
+		},
+		{
+			name: "令牌不存在",
+			mock: func(ctrl *gomock.Controller) (repository.PATRepository, repository.UserRepository) {
+				repo := repomocks.NewMockPATRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).
+					Return(domain.PersonalAccessToken{}, repository.ErrPATNotFound)
+				return repo, repomocks.NewMockUserRepository(ctrl)
+			},
+			token:   "wbk_abc",
+			wantErr: ErrInvalidPAT,
+		},
+		{
+			name: "令牌已经过期",
+			mock: func(ctrl *gomock.Controller) (repository.PATRepository, repository.UserRepository) {
+				repo := repomocks.NewMockPATRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).
+					Return(domain.PersonalAccessToken{
+						Uid:        123,
+						Expiration: time.Now().Add(-time.Hour),
+					}, nil)
+				return repo, repomocks.NewMockUserRepository(ctrl)
+			},
+			token:   "wbk_abc",
+			wantErr: ErrInvalidPAT,
+		},
+		{
+			name: "用户已经被封禁",
+			mock: func(ctrl *gomock.Controller) (repository.PATRepository, repository.UserRepository) {
+				repo := repomocks.NewMockPATRepository(ctrl)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).
+					Return(domain.PersonalAccessToken{
+						Uid:        123,
+						Expiration: time.Now().Add(time.Hour),
+					}, nil)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123, Banned: true}, nil)
+				return repo, userRepo
+			},
+			token:   "wbk_abc",
+			wantErr: ErrUserBanned,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, userRepo := tc.mock(ctrl)
+			svc := NewPATService(repo, userRepo)
+			pat, err := svc.Verify(context.Background(), tc.token)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantUid, pat.Uid)
+		})
+	}
+}
+
+func TestPATService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockPATRepository(ctrl)
+	var savedHash string
+	repo.EXPECT().Count(gomock.Any(), int64(123)).Return(int64(0), nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, t domain.PersonalAccessToken, hash string) (int64, error) {
+			savedHash = hash
+			return 1, nil
+		})
+	svc := NewPATService(repo, repomocks.NewMockUserRepository(ctrl)).(*patService)
+
+	token, pat, err := svc.Create(context.Background(), 123, "脚本",
+		[]domain.Scope{domain.ScopeArticleRead}, time.Hour*24)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pat.Id)
+	assert.Contains(t, token, domain.PATPrefix)
+	// 落库的是哈希值，不是令牌本身
+	assert.NotEqual(t, token, savedHash)
+	assert.Equal(t, svc.hash(token), savedHash)
+
+	// 未知的权限范围
+	_, _, err = svc.Create(context.Background(), 123, "脚本",
+		[]domain.Scope{"admin"}, time.Hour*24)
+	assert.Equal(t, ErrInvalidScope, err)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+var (
+	ErrQRLoginExpired      = errors.New("二维码已经过期")
+	ErrQRLoginInvalidState = errors.New("二维码的状态不对")
+	// ErrQRLoginInvalidSecret 查询状态的时候带的凭证不对，不是创建票据的桌面端
+	ErrQRLoginInvalidSecret = errors.New("扫码登录的凭证不对")
+)
+
+const (
+	// qrLoginExpiration 二维码的有效期
+	qrLoginExpiration = time.Minute * 2
+	// qrLoginPollInterval 长轮询的时候查询状态的间隔
+	qrLoginPollInterval = time.Millisecond * 500
+)
+
+//go:generate mockgen -source=./qr_login.go -package=svcmocks -destination=mocks/qr_login.mock.go QRLoginService
+type QRLoginService interface {
+	// Create 桌面端创建一个等待扫码的票据，返回的 Secret 只能给桌面端
+	Create(ctx context.Context, userAgent, ip string) (domain.QRLoginTicket, error)
+	// Wait 桌面端等待票据的状态变化，known 是桌面端已知的状态
+	// secret 是 Create 返回的凭证，不对的时候返回 ErrQRLoginInvalidSecret
+	// 状态发生变化或者超时之后返回，超时的时候返回的状态就是 known
+	// 返回 QRLoginStatusConfirmed 的时候票据已经被使用了，桌面端应该直接登录 Uid
+	Wait(ctx context.Context, ticket, secret string, known domain.QRLoginStatus, timeout time.Duration) (domain.QRLoginTicket, error)
+	// Scan 已经登录的移动端扫码，返回桌面端的信息让用户确认
+	Scan(ctx context.Context, ticket string, uid int64) (domain.QRLoginTicket, error)
+	// Confirm 移动端确认或者拒绝登录，只有扫码的用户能确认
+	Confirm(ctx context.Context, ticket string, uid int64, approve bool) error
+}
+
+type qrLoginService struct {
+	repo repository.QRLoginRepository
+}
+
+func NewQRLoginService(repo repository.QRLoginRepository) QRLoginService {
+	return &qrLoginService{repo: repo}
+}
+
+func (svc *qrLoginService) Create(ctx context.Context, userAgent, ip string) (domain.QRLoginTicket, error) {
+	ticket, err := svc.randomToken()
+	if err != nil {
+		return domain.QRLoginTicket{}, err
+	}
+	secret, err := svc.randomToken()
+	if err != nil {
+		return domain.QRLoginTicket{}, err
+	}
+	t := domain.QRLoginTicket{
+		Ticket:    ticket,
+		Secret:    secret,
+		Status:    domain.QRLoginStatusPending,
+		UserAgent: userAgent,
+		IP:        ip,
+	}
+	return t, svc.repo.Create(ctx, t, qrLoginExpiration)
+}
+
+func (svc *qrLoginService) randomToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (svc *qrLoginService) Wait(ctx context.Context, ticket, secret string,
+	known domain.QRLoginStatus, timeout time.Duration) (domain.QRLoginTicket, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(qrLoginPollInterval)
+	defer ticker.Stop()
+	for {
+		t, err := svc.check(ctx, ticket, secret)
+		if err != nil {
+			return domain.QRLoginTicket{}, err
+		}
+		if t.Status != known {
+			return t, nil
+		}
+		select {
+		case <-ctx.Done():
+			// 超时了状态也没有变化
+			return t, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// check 查询一次票据的状态，已经确认的票据会被使用掉
+func (svc *qrLoginService) check(ctx context.Context, ticket, secret string) (domain.QRLoginTicket, error) {
+	t, err := svc.repo.Find(ctx, ticket)
+	if errors.Is(err, repository.ErrQRLoginTicketNotFound) {
+		return domain.QRLoginTicket{Ticket: ticket, Status: domain.QRLoginStatusUnknown}, nil
+	}
+	if err != nil {
+		return domain.QRLoginTicket{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(t.Secret), []byte(secret)) != 1 {
+		return domain.QRLoginTicket{}, ErrQRLoginInvalidSecret
+	}
+	if t.Status != domain.QRLoginStatusConfirmed {
+		return t, nil
+	}
+	// 并发轮询的时候，只有一个请求能够拿到登录态
+	t.Uid, err = svc.repo.Consume(ctx, ticket)
+	if errors.Is(err, repository.ErrQRLoginTicketNotFound) ||
+		errors.Is(err, repository.ErrQRLoginStatusMismatch) {
+		return domain.QRLoginTicket{Ticket: ticket, Status: domain.QRLoginStatusUnknown}, nil
+	}
+	return t, err
+}
+
+func (svc *qrLoginService) Scan(ctx context.Context, ticket string, uid int64) (domain.QRLoginTicket, error) {
+	err := svc.transit(ctx, ticket, domain.QRLoginStatusPending, domain.QRLoginStatusScanned, uid, true)
+	if err != nil {
+		return domain.QRLoginTicket{}, err
+	}
+	t, err := svc.repo.Find(ctx, ticket)
+	if errors.Is(err, repository.ErrQRLoginTicketNotFound) {
+		return domain.QRLoginTicket{}, ErrQRLoginExpired
+	}
+	return t, err
+}
+
+func (svc *qrLoginService) Confirm(ctx context.Context, ticket string, uid int64, approve bool) error {
+	to := domain.QRLoginStatusRejected
+	if approve {
+		to = domain.QRLoginStatusConfirmed
+	}
+	return svc.transit(ctx, ticket, domain.QRLoginStatusScanned, to, uid, false)
+}
+
+func (svc *qrLoginService) transit(ctx context.Context, ticket string,
+	from, to domain.QRLoginStatus, uid int64, bind bool) error {
+	err := svc.repo.Transit(ctx, ticket, from, to, uid, bind)
+	switch {
+	case errors.Is(err, repository.ErrQRLoginTicketNotFound):
+		return ErrQRLoginExpired
+	case errors.Is(err, repository.ErrQRLoginStatusMismatch):
+		return ErrQRLoginInvalidState
+	default:
+		return err
+	}
+}
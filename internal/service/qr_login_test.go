@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+)
+
+func TestQRLoginService_Wait(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.QRLoginRepository
+
+		known domain.QRLoginStatus
+
+		wantTicket domain.QRLoginTicket
+		wantErr    error
+	}{
+		{
+			name: "已经确认，使用票据",
+			mock: func(ctrl *gomock.Controller) repository.QRLoginRepository {
+				repo := repomocks.NewMockQRLoginRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), "t1").Return(domain.QRLoginTicket{
+					Ticket: "t1",
+					Secret: "s1",
+					Status: domain.QRLoginStatusConfirmed,
+					Uid:    123,
+				}, nil)
+				repo.EXPECT().Consume(gomock.Any(), "t1").Return(int64(123), nil)
+				return repo
+			},
+			known: domain.QRLoginStatusScanned,
+			wantTicket: domain.QRLoginTicket{
+				Ticket: "t1",
+				Secret: "s1",
+				Status: domain.QRLoginStatusConfirmed,
+				Uid:    123,
+			},
+		},
+		{
+			name: "并发轮询，票据被别的请求用掉了",
+			mock: func(ctrl *gomock.Controller) repository.QRLoginRepository {
+				repo := repomocks.NewMockQRLoginRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), "t1").Return(domain.QRLoginTicket{
+					Ticket: "t1",
+					Secret: "s1",
+					Status: domain.QRLoginStatusConfirmed,
+					Uid:    123,
+				}, nil)
+				repo.EXPECT().Consume(gomock.Any(), "t1").
+					Return(int64(0), repository.ErrQRLoginTicketNotFound)
+				return repo
+			},
+			known: domain.QRLoginStatusScanned,
+			wantTicket: domain.QRLoginTicket{
+				Ticket: "t1",
+				Status: domain.QRLoginStatusUnknown,
+			},
+		},
+		{
+			name: "状态变化之后返回",
+			mock: func(ctrl *gomock.Controller) repository.QRLoginRepository {
+				repo := repomocks.NewMockQRLoginRepository(ctrl)
+				gomock.InOrder(
+					repo.EXPECT().Find(gomock.Any(), "t1").Return(domain.QRLoginTicket{
+						Ticket: "t1",
+						Secret: "s1",
+						Status: domain.QRLoginStatusPending,
+					}, nil),
+					repo.EXPECT().Find(gomock.Any(), "t1").Return(domain.QRLoginTicket{
+						Ticket: "t1",
+						Secret: "s1",
+						Status: domain.QRLoginStatusScanned,
+						Uid:    123,
+					}, nil),
+				)
+				return repo
+			},
+			known: domain.QRLoginStatusPending,
+			wantTicket: domain.QRLoginTicket{
+				Ticket: "t1",
+				Secret: "s1",
+				Status: domain.QRLoginStatusScanned,
+				Uid:    123,
+			},
+		},
+		{
+			name: "凭证不对，不能使用票据",
+			mock: func(ctrl *gomock.Controller) repository.QRLoginRepository {
+				repo := repomocks.NewMockQRLoginRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), "t1").Return(domain.QRLoginTicket{
+					Ticket: "t1",
+					Secret: "other",
+					Status: domain.QRLoginStatusConfirmed,
+					Uid:    123,
+				}, nil)
+				return repo
+			},
+			known:   domain.QRLoginStatusScanned,
+			wantErr: ErrQRLoginInvalidSecret,
+		},
+		{
+			name: "已经过期",
+			mock: func(ctrl *gomock.Controller) repository.QRLoginRepository {
+				repo := repomocks.NewMockQRLoginRepository(ctrl)
+				repo.EXPECT().Find(gomock.Any(), "t1").
+					Return(domain.QRLoginTicket{}, repository.ErrQRLoginTicketNotFound)
+				return repo
+			},
+			known: domain.QRLoginStatusPending,
+			wantTicket: domain.QRLoginTicket{
+				Ticket: "t1",
+				Status: domain.QRLoginStatusUnknown,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewQRLoginService(tc.mock(ctrl))
+			ticket, err := svc.Wait(context.Background(), "t1", "s1", tc.known, time.Second*2)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantTicket, ticket)
+		})
+	}
+}
+
+func TestQRLoginService_Confirm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockQRLoginRepository(ctrl)
+	repo.EXPECT().Transit(gomock.Any(), "t1", domain.QRLoginStatusScanned,
+		domain.QRLoginStatusConfirmed, int64(123), false).Return(nil)
+	// 不是扫码的用户
+	repo.EXPECT().Transit(gomock.Any(), "t1", domain.QRLoginStatusScanned,
+		domain.QRLoginStatusConfirmed, int64(456), false).Return(repository.ErrQRLoginStatusMismatch)
+	repo.EXPECT().Transit(gomock.Any(), "t2", domain.QRLoginStatusScanned,
+		domain.QRLoginStatusRejected, int64(123), false).Return(repository.ErrQRLoginTicketNotFound)
+	svc := NewQRLoginService(repo)
+
+	assert.NoError(t, svc.Confirm(context.Background(), "t1", 123, true))
+	assert.Equal(t, ErrQRLoginInvalidState, svc.Confirm(context.Background(), "t1", 456, true))
+	assert.Equal(t, ErrQRLoginExpired, svc.Confirm(context.Background(), "t2", 123, false))
+}
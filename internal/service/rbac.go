@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+var ErrInvalidRole = errors.New("未知的角色")
+
+//go:generate mockgen -source=./rbac.go -package=svcmocks -destination=mocks/rbac.mock.go RBACService
+type RBACService interface {
+	// UserRoles 用户拥有的角色，登录或者刷新 token 的时候放进 UserClaims 里面
+	UserRoles(ctx context.Context, uid int64) ([]domain.Role, error)
+	// AssignRole 给用户授予角色，operator 是操作的管理员，为 0 表示系统初始化
+	// 用户已经有这个角色的时候什么也不做，也不会记录审计日志
+	AssignRole(ctx context.Context, operator, uid int64, role domain.Role) error
+	RevokeRole(ctx context.Context, operator, uid int64, role domain.Role) error
+}
+
+type rbacService struct {
+	repo repository.RBACRepository
+	l    logger.Logger
+}
+
+func NewRBACService(repo repository.RBACRepository, l logger.Logger) RBACService {
+	return &rbacService{
+		repo: repo,
+		l:    l,
+	}
+}
+
+func (svc *rbacService) UserRoles(ctx context.Context, uid int64) ([]domain.Role, error) {
+	return svc.repo.FindRoles(ctx, uid)
+}
+
+func (svc *rbacService) AssignRole(ctx context.Context, operator, uid int64, role domain.Role) error {
+	if !role.Valid() {
+		return ErrInvalidRole
+	}
+	added, err := svc.repo.AddRole(ctx, operator, uid, role)
+	if err != nil || !added {
+		return err
+	}
+	recordAudit(ctx, svc.repo, svc.l, domain.AuditLog{
+		OperatorId: operator,
+		Action:     domain.AuditActionAssignRole,
+		TargetId:   uid,
+		Detail:     string(role),
+	})
+	return nil
+}
+
+func (svc *rbacService) RevokeRole(ctx context.Context, operator, uid int64, role domain.Role) error {
+	if !role.Valid() {
+		return ErrInvalidRole
+	}
+	err := svc.repo.RemoveRole(ctx, uid, role)
+	if err != nil {
+		return err
+	}
+	recordAudit(ctx, svc.repo, svc.l, domain.AuditLog{
+		OperatorId: operator,
+		Action:     domain.AuditActionRevokeRole,
+		TargetId:   uid,
+		Detail:     string(role),
+	})
+	return nil
+}
+
+// recordAudit 记录审计日志
+// 操作本身已经成功了，所以审计日志写失败只记录错误日志，不影响返回结果
+func recordAudit(ctx context.Context, repo repository.RBACRepository, l logger.Logger, log domain.AuditLog) {
+	err := repo.AddAuditLog(ctx, log)
+	if err != nil {
+		l.Error("记录审计日志失败",
+			logger.Int64("operator", log.OperatorId),
+			logger.String("action", string(log.Action)),
+			logger.Int64("target", log.TargetId),
+			logger.Error(err))
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+)
+
+func TestRBACService_AssignRole(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) repository.RBACRepository
+
+		operator int64
+		uid      int64
+		role     domain.Role
+
+		wantErr error
+	}{
+		{
+			name: "授予成功，记录审计日志",
+			mock: func(ctrl *gomock.Controller) repository.RBACRepository {
+				repo := repomocks.NewMockRBACRepository(ctrl)
+				repo.EXPECT().AddRole(gomock.Any(), int64(1), int64(2), domain.RoleAdmin).
+					Return(true, nil)
+				repo.EXPECT().AddAuditLog(gomock.Any(), domain.AuditLog{
+					OperatorId: 1,
+					Action:     domain.AuditActionAssignRole,
+					TargetId:   2,
+					Detail:     string(domain.RoleAdmin),
+				}).Return(nil)
+				return repo
+			},
+			operator: 1,
+			uid:      2,
+			role:     domain.RoleAdmin,
+		},
+		{
+			name: "已经有这个角色，不记录审计日志",
+			mock: func(ctrl *gomock.Controller) repository.RBACRepository {
+				repo := repomocks.NewMockRBACRepository(ctrl)
+				// 例如每次启动的时候给配置里面的管理员授予角色
+				repo.EXPECT().AddRole(gomock.Any(), int64(0), int64(2), domain.RoleAdmin).
+					Return(false, nil)
+				return repo
+			},
+			operator: 0,
+			uid:      2,
+			role:     domain.RoleAdmin,
+		},
+		{
+			name: "授予失败",
+			mock: func(ctrl *gomock.Controller) repository.RBACRepository {
+				repo := repomocks.NewMockRBACRepository(ctrl)
+				repo.EXPECT().AddRole(gomock.Any(), int64(1), int64(2), domain.RoleAdmin).
+					Return(false, errors.New("mock db error"))
+				return repo
+			},
+			operator: 1,
+			uid:      2,
+			role:     domain.RoleAdmin,
+			wantErr:  errors.New("mock db error"),
+		},
+		{
+			name: "未知的角色",
+			mock: func(ctrl *gomock.Controller) repository.RBACRepository {
+				return repomocks.NewMockRBACRepository(ctrl)
+			},
+			operator: 1,
+			uid:      2,
+			role:     domain.Role("unknown"),
+			wantErr:  ErrInvalidRole,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewRBACService(tc.mock(ctrl), logger.NewZapLogger(zap.NewNop()))
+			err := svc.AssignRole(context.Background(), tc.operator, tc.uid, tc.role)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
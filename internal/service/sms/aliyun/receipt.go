@@ -0,0 +1,49 @@
+package aliyun
+
+import (
+	"encoding/json"
+	"time"
+	"webook/internal/domain"
+)
+
+// beijing 状态报告里面的时间是北京时间，没有带时区
+var beijing = time.FixedZone("CST", 8*3600)
+
+// ReceiptParser 阿里云短信的状态报告（SmsReport），使用 HTTP 批量推送的方式
+// 在控制台配置推送地址 /sms/callback/aliyun?token=xxx
+// 同一次请求的号码共用一个 biz_id，所以批量发送的时候一条状态报告会更新同一批的所有记录
+type ReceiptParser struct{}
+
+type receipt struct {
+	ReportTime string `json:"report_time"`
+	Success    bool   `json:"success"`
+	// ErrCode 成功的时候是 DELIVERED
+	ErrCode string `json:"err_code"`
+	ErrMsg  string `json:"err_msg"`
+	BizId   string `json:"biz_id"`
+}
+
+func (ReceiptParser) Parse(body []byte) ([]domain.SMSReceipt, error) {
+	var rs []receipt
+	if err := json.Unmarshal(body, &rs); err != nil {
+		return nil, err
+	}
+	res := make([]domain.SMSReceipt, 0, len(rs))
+	for _, r := range rs {
+		// 格式不对的时候就没有时间，不影响更新状态
+		t, _ := time.ParseInLocation(time.DateTime, r.ReportTime, beijing)
+		res = append(res, domain.SMSReceipt{
+			MessageId:  r.BizId,
+			Delivered:  r.Success,
+			Code:       r.ErrCode,
+			Msg:        r.ErrMsg,
+			ReportTime: t,
+		})
+	}
+	return res, nil
+}
+
+// Ack 阿里云要求返回 code 为 0，否则会重新推送
+func (ReceiptParser) Ack() any {
+	return map[string]any{"code": 0, "msg": "成功"}
+}
@@ -2,47 +2,88 @@ package aliyun
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	dysmsapi "github.com/alibabacloud-go/dysmsapi-20170525/v3/client"
 	"github.com/alibabacloud-go/tea/tea"
 	"strings"
+	"webook/internal/service/sms"
 )
 
+// ProviderName 在 TemplateRegistry 里面的名字
+const ProviderName = "aliyun"
+
 type Service struct {
-	client   *dysmsapi.Client
+	client *dysmsapi.Client
+	// signName 模板没有配置签名的时候使用
 	signName string
+	tpls     *sms.TemplateRegistry
 }
 
-func NewService(client *dysmsapi.Client, signName string) *Service {
+func NewService(client *dysmsapi.Client, signName string, tpls *sms.TemplateRegistry) *Service {
 	return &Service{
 		client:   client,
 		signName: signName,
+		tpls:     tpls,
 	}
 }
 
 func (s *Service) Send(ctx context.Context, tpl string, param []string, numbers ...string) error {
+	_, err := s.SendWithResult(ctx, tpl, param, numbers...)
+	return err
+}
+
+// SendWithResult 阿里云一次请求只返回一个 BizId，所有号码共用，状态报告里面的 biz_id 就是它
+func (s *Service) SendWithResult(ctx context.Context, tpl string, param []string,
+	numbers ...string) ([]sms.SendResult, error) {
 	// ali云要求最多一次发1000个
 	if len(numbers) > 1000 {
-		return fmt.Errorf("phone numbers 超过1000")
+		return nil, fmt.Errorf("phone numbers 超过1000")
+	}
+	rendered, err := s.tpls.Render(tpl, ProviderName, param)
+	if err != nil {
+		return nil, err
+	}
+	// 阿里云的参数是 JSON 对象，例如 {"code":"123456"}
+	params := make(map[string]string, len(rendered.Params))
+	for _, p := range rendered.Params {
+		params[p.Name] = p.Value
+	}
+	paramStr, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	signName := s.signName
+	if rendered.SignName != "" {
+		signName = rendered.SignName
 	}
 	// 多个号码格式要求为 133,137,150 用逗号分隔的字符串
-	numberStr := strings.Join(numbers, ",")
+	// 号码是 E.164 格式，阿里云要求国际号码是区号加号码，不带加号
+	nums := make([]string, 0, len(numbers))
+	for _, n := range numbers {
+		nums = append(nums, strings.TrimPrefix(n, "+"))
+	}
+	numberStr := strings.Join(nums, ",")
 
 	req := dysmsapi.SendSmsRequest{
-		SignName:      tea.String(s.signName),
+		SignName:      tea.String(signName),
 		PhoneNumbers:  tea.String(numberStr),
-		TemplateCode:  tea.String(tpl),
-		TemplateParam: tea.String(param[0]),
+		TemplateCode:  tea.String(rendered.TplId),
+		TemplateParam: tea.String(string(paramStr)),
 	}
 	resp, err := s.client.SendSms(&req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if *(resp.StatusCode) != 200 {
-		return fmt.Errorf("发送短信失败,code:%s,%s", string(*(resp.StatusCode)), *(resp.Body.Message))
+		return nil, fmt.Errorf("发送短信失败,code:%s,%s", string(*(resp.StatusCode)), *(resp.Body.Message))
 	}
 	if *(resp.Body.Code) != "OK" {
-		return fmt.Errorf("发送短信失败,code:%s,%s", *(resp.Body.Code), *(resp.Body.Message))
+		return nil, fmt.Errorf("发送短信失败,code:%s,%s", *(resp.Body.Code), *(resp.Body.Message))
+	}
+	results := make([]sms.SendResult, 0, len(numbers))
+	for _, n := range numbers {
+		results = append(results, sms.SendResult{Number: n, MessageId: tea.StringValue(resp.Body.BizId)})
 	}
-	return nil
+	return results, nil
 }
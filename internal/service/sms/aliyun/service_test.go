@@ -8,6 +8,7 @@ import (
 	"github.com/alibabacloud-go/tea/tea"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"webook/internal/service/sms"
 )
 
 func TestNewService(t *testing.T) {
@@ -20,7 +21,15 @@ func TestNewService(t *testing.T) {
 		fmt.Println(err)
 	}
 
-	s := NewService(c, "阿里云短信测试")
+	tpls, err := sms.NewTemplateRegistry(sms.Template{
+		Name:   "login_code",
+		Params: []string{"code"},
+		Providers: map[string]sms.ProviderTemplate{
+			ProviderName: {TplId: "SMS_154950909"},
+		},
+	})
+	assert.NoError(t, err)
+	s := NewService(c, "阿里云短信测试", tpls)
 
 	testCases := []struct {
 		name    string
@@ -31,15 +40,15 @@ func TestNewService(t *testing.T) {
 	}{
 		{
 			name:   "发送验证码",
-			tplId:  "SMS_154950909",
-			params: []string{"{\"code\":\"666666\"}"},
+			tplId:  "login_code",
+			params: []string{"666666"},
 			// 改成你的手机号码
 			numbers: []string{"151****", "197****"},
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			er := s.Send(context.Background(), tc.tplId, tc.params, tc.numbers)
+			er := s.Send(context.Background(), tc.tplId, tc.params, tc.numbers...)
 			assert.Equal(t, tc.wantErr, er)
 		})
 	}
@@ -0,0 +1,146 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/sms"
+	"webook/pkg/logger"
+)
+
+// Service 同步发送失败的时候（限流或者所有服务商都失败了），把短信存到数据库里，
+// 然后返回 sms.ErrDeferred，由定时任务调用 SendPending 异步重试
+// 重试的间隔按照指数退避，超过最大重试次数或者过了 sms.WithDeadline 设置的时间之后标记为失败，留着做审计
+type Service struct {
+	svc  sms.Service
+	repo repository.AsyncSMSRepository
+	l    logger.Logger
+
+	// 最多重试几次
+	maxRetry int
+	// 第一次重试的间隔，之后每次翻倍
+	backoff    time.Duration
+	maxBackoff time.Duration
+	// 每一批处理多少条
+	batchSize int
+	// 每一条重试的超时时间
+	timeout time.Duration
+}
+
+func NewService(svc sms.Service, repo repository.AsyncSMSRepository, l logger.Logger) *Service {
+	return &Service{
+		svc:  svc,
+		repo: repo,
+		l:    l,
+		// 验证码的有效期不长，重试太久也没有意义
+		maxRetry:   5,
+		backoff:    time.Second * 5,
+		maxBackoff: time.Minute,
+		batchSize:  100,
+		timeout:    time.Second * 10,
+	}
+}
+
+func (s *Service) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	err := s.svc.Send(ctx, tplId, args, numbers...)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sms.ErrUnsupportedRegion) {
+		// 没有服务商能发，重试也没有用
+		return err
+	}
+	nextTime := time.Now().Add(s.backoff)
+	deadline, _ := sms.DeadlineFrom(ctx)
+	if !deadline.IsZero() && !nextTime.Before(deadline) {
+		// 等不到重试就过期了
+		return err
+	}
+	dbErr := s.repo.Add(ctx, domain.AsyncSMS{
+		TplId:    tplId,
+		Args:     args,
+		Numbers:  numbers,
+		NextTime: nextTime,
+		Deadline: deadline,
+		LastErr:  err.Error(),
+	})
+	if dbErr != nil {
+		// 存不下来，只能让调用者知道发送失败了
+		s.l.Error("保存异步短信失败", logger.Error(dbErr))
+		return err
+	}
+	s.l.Warn("短信发送失败，转异步重试", logger.String("tpl", tplId), logger.Error(err))
+	return sms.ErrDeferred
+}
+
+// SendPending 重试到了时间的短信，处理完所有到期的短信或者 ctx 结束的时候返回
+func (s *Service) SendPending(ctx context.Context) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		msgs, err := s.repo.FindDue(ctx, s.batchSize)
+		if err != nil {
+			return err
+		}
+		failed := 0
+		for _, msg := range msgs {
+			if !s.retry(ctx, msg) {
+				failed++
+			}
+		}
+		// 状态没有更新成功的短信还是到期的，再查一批又会找出来重新发给用户，
+		// 所以有更新失败的就等下一次调度，这样一次调度里面同一条短信最多发一次
+		if len(msgs) < s.batchSize || failed > 0 {
+			return nil
+		}
+	}
+}
+
+// retry 重试一条短信，返回状态有没有更新成功
+func (s *Service) retry(ctx context.Context, msg domain.AsyncSMS) bool {
+	if !msg.Deadline.IsZero() && time.Now().After(msg.Deadline) {
+		// 例如验证码已经过期了，发过去用户也用不了
+		err := s.repo.ReportFailed(ctx, msg.Id, msg.RetryCnt, "已经过了有效期，不再重试")
+		if err != nil {
+			s.l.Error("更新异步短信的状态失败", logger.Int64("id", msg.Id), logger.Error(err))
+			return false
+		}
+		return true
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	err := s.svc.Send(sendCtx, msg.TplId, msg.Args, msg.Numbers...)
+	cancel()
+	retryCnt := msg.RetryCnt + 1
+	switch {
+	case err == nil:
+		err = s.repo.ReportSuccess(ctx, msg.Id, retryCnt)
+	case retryCnt >= s.maxRetry:
+		s.l.Error("异步短信重试次数用完，发送失败",
+			logger.Int64("id", msg.Id), logger.Error(err))
+		err = s.repo.ReportFailed(ctx, msg.Id, retryCnt, err.Error())
+	default:
+		err = s.repo.ReportRetry(ctx, msg.Id, retryCnt,
+			time.Now().Add(s.backoffOf(retryCnt)), err.Error())
+	}
+	if err != nil {
+		// 没更新成功的话，下一次还会被找出来，最坏的情况是重复发送
+		s.l.Error("更新异步短信的状态失败", logger.Int64("id", msg.Id), logger.Error(err))
+		return false
+	}
+	return true
+}
+
+// backoffOf 第 retryCnt 次重试失败之后，下一次重试的间隔
+func (s *Service) backoffOf(retryCnt int) time.Duration {
+	d := s.backoff
+	for i := 0; i < retryCnt && d < s.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > s.maxBackoff {
+		d = s.maxBackoff
+	}
+	return d
+}
@@ -0,0 +1,176 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/internal/service/sms"
+	smsmocks "webook/internal/service/sms/mocks"
+	"webook/pkg/logger"
+)
+
+func TestService_Send(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository)
+		// 没有设置的话用 context.Background()
+		ctx context.Context
+
+		wantErr error
+	}{
+		{
+			name: "同步发送成功",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "mytpl", []string{"123"}, "152xxx").Return(nil)
+				return svc, repomocks.NewMockAsyncSMSRepository(ctrl)
+			},
+		},
+		{
+			name: "发送失败，转异步",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "mytpl", []string{"123"}, "152xxx").
+					Return(errors.New("短信服务触发限流"))
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Add(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, s domain.AsyncSMS) error {
+						assert.Equal(t, "mytpl", s.TplId)
+						assert.Equal(t, []string{"123"}, s.Args)
+						assert.Equal(t, []string{"152xxx"}, s.Numbers)
+						assert.Equal(t, "短信服务触发限流", s.LastErr)
+						assert.True(t, s.NextTime.After(time.Now()))
+						return nil
+					})
+				return svc, repo
+			},
+			wantErr: sms.ErrDeferred,
+		},
+		{
+			name: "验证码转异步，带上有效期",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "mytpl", []string{"123"}, "152xxx").
+					Return(errors.New("短信服务触发限流"))
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Add(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, s domain.AsyncSMS) error {
+						assert.WithinDuration(t, time.Now().Add(time.Minute*10), s.Deadline, time.Second)
+						return nil
+					})
+				return svc, repo
+			},
+			ctx:     sms.WithDeadline(context.Background(), time.Now().Add(time.Minute*10)),
+			wantErr: sms.ErrDeferred,
+		},
+		{
+			name: "等不到重试就过期了，不转异步",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "mytpl", []string{"123"}, "152xxx").
+					Return(errors.New("短信服务触发限流"))
+				return svc, repomocks.NewMockAsyncSMSRepository(ctrl)
+			},
+			ctx:     sms.WithDeadline(context.Background(), time.Now().Add(time.Second)),
+			wantErr: errors.New("短信服务触发限流"),
+		},
+		{
+			name: "转异步也失败",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "mytpl", []string{"123"}, "152xxx").
+					Return(errors.New("发送失败，所有服务商都尝试过了"))
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Add(gomock.Any(), gomock.Any()).Return(errors.New("mock db 错误"))
+				return svc, repo
+			},
+			wantErr: errors.New("发送失败，所有服务商都尝试过了"),
+		},
+		{
+			name: "不支持的地区，不转异步",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "mytpl", []string{"123"}, "152xxx").
+					Return(sms.ErrUnsupportedRegion)
+				return svc, repomocks.NewMockAsyncSMSRepository(ctrl)
+			},
+			wantErr: sms.ErrUnsupportedRegion,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, repo := tc.mock(ctrl)
+			s := NewService(svc, repo, logger.NewZapLogger(zap.NewNop()))
+			ctx := tc.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			err := s.Send(ctx, "mytpl", []string{"123"}, "152xxx")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestService_SendPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := smsmocks.NewMockService(ctrl)
+	repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+	repo.EXPECT().FindDue(gomock.Any(), 100).Return([]domain.AsyncSMS{
+		{Id: 1, TplId: "tpl1", Args: []string{"1"}, Numbers: []string{"152xxx"}},
+		{Id: 2, TplId: "tpl2", Args: []string{"2"}, Numbers: []string{"152xxx"}, RetryCnt: 1},
+		{Id: 3, TplId: "tpl3", Args: []string{"3"}, Numbers: []string{"152xxx"}, RetryCnt: 4},
+		{Id: 4, TplId: "tpl4", Args: []string{"4"}, Numbers: []string{"152xxx"}, RetryCnt: 2,
+			Deadline: time.Now().Add(-time.Second)},
+	}, nil)
+
+	// 重试成功
+	svc.EXPECT().Send(gomock.Any(), "tpl1", []string{"1"}, "152xxx").Return(nil)
+	repo.EXPECT().ReportSuccess(gomock.Any(), int64(1), 1).Return(nil)
+	// 重试失败，第二次重试失败之后等 20 秒
+	svc.EXPECT().Send(gomock.Any(), "tpl2", []string{"2"}, "152xxx").
+		Return(errors.New("发送不了"))
+	repo.EXPECT().ReportRetry(gomock.Any(), int64(2), 2, gomock.Any(), "发送不了").
+		DoAndReturn(func(ctx context.Context, id int64, retryCnt int, nextTime time.Time, lastErr string) error {
+			assert.WithinDuration(t, time.Now().Add(time.Second*20), nextTime, time.Second)
+			return nil
+		})
+	// 重试次数用完了
+	svc.EXPECT().Send(gomock.Any(), "tpl3", []string{"3"}, "152xxx").
+		Return(errors.New("还是失败"))
+	repo.EXPECT().ReportFailed(gomock.Any(), int64(3), 5, "还是失败").Return(nil)
+	// 已经过期了，不发送
+	repo.EXPECT().ReportFailed(gomock.Any(), int64(4), 2, gomock.Any()).Return(nil)
+
+	s := NewService(svc, repo, logger.NewZapLogger(zap.NewNop()))
+	err := s.SendPending(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestService_SendPending_UpdateFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := smsmocks.NewMockService(ctrl)
+	repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+	s := NewService(svc, repo, logger.NewZapLogger(zap.NewNop()))
+	msgs := make([]domain.AsyncSMS, 0, s.batchSize)
+	for i := 0; i < s.batchSize; i++ {
+		msgs = append(msgs, domain.AsyncSMS{Id: int64(i + 1), TplId: "tpl", Args: []string{"1"}, Numbers: []string{"152xxx"}})
+	}
+	// 满满的一批，状态都没有更新成功，只能发一次，不能一直重复发给用户
+	repo.EXPECT().FindDue(gomock.Any(), s.batchSize).Return(msgs, nil).Times(1)
+	svc.EXPECT().Send(gomock.Any(), "tpl", []string{"1"}, "152xxx").Return(nil).Times(s.batchSize)
+	repo.EXPECT().ReportSuccess(gomock.Any(), gomock.Any(), 1).
+		Return(errors.New("数据库错误")).Times(s.batchSize)
+	assert.NoError(t, s.SendPending(context.Background()))
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service/sms"
+	"webook/pkg/logger"
+)
+
+// AuditSMSService 记录每一次调用服务商发送短信的结果，每个号码一条
+// 包在具体的服务商外面，所以故障转移的时候每个尝试过的服务商都会有记录
+// 服务商实现了 sms.ResultService 的话会记下消息 ID，之后状态报告就能对应上
+type AuditSMSService struct {
+	svc      sms.Service
+	provider string
+	repo     repository.SMSRecordRepository
+	l        logger.Logger
+	now      func() time.Time
+}
+
+func NewAuditSMSService(svc sms.Service, provider string,
+	repo repository.SMSRecordRepository, l logger.Logger) *AuditSMSService {
+	return &AuditSMSService{
+		svc:      svc,
+		provider: provider,
+		repo:     repo,
+		l:        l,
+		now:      time.Now,
+	}
+}
+
+func (s *AuditSMSService) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	start := s.now()
+	var (
+		results []sms.SendResult
+		err     error
+	)
+	if rs, ok := s.svc.(sms.ResultService); ok {
+		results, err = rs.SendWithResult(ctx, tplId, args, numbers...)
+	} else {
+		err = s.svc.Send(ctx, tplId, args, numbers...)
+	}
+	latency := s.now().Sub(start)
+
+	byNumber := make(map[string]sms.SendResult, len(results))
+	for _, r := range results {
+		byNumber[r.Number] = r
+	}
+	records := make([]domain.SMSRecord, 0, len(numbers))
+	for _, n := range numbers {
+		record := domain.SMSRecord{
+			Provider: s.provider,
+			TplId:    tplId,
+			Number:   n,
+			Latency:  latency,
+			Status:   domain.SMSRecordStatusSent,
+		}
+		r, ok := byNumber[n]
+		switch {
+		case ok && r.Err != nil:
+			record.MessageId = r.MessageId
+			record.Status = domain.SMSRecordStatusFailed
+			record.Err = r.Err.Error()
+		case ok:
+			record.MessageId = r.MessageId
+		case err != nil:
+			// 整个请求都失败了，或者服务商没有给出这个号码的结果
+			record.Status = domain.SMSRecordStatusFailed
+			record.Err = err.Error()
+		}
+		records = append(records, record)
+	}
+	// 记录失败不影响发送的结果，调用者的 ctx 可能已经超时了，这里不受它影响
+	if er := s.repo.Add(context.WithoutCancel(ctx), records...); er != nil {
+		s.l.Error("保存短信发送记录失败",
+			logger.String("provider", s.provider),
+			logger.String("tplId", tplId),
+			logger.Error(er))
+	}
+	return err
+}
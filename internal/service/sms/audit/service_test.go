@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	repomocks "webook/internal/repository/mocks"
+	"webook/internal/service/sms"
+	smsmocks "webook/internal/service/sms/mocks"
+	"webook/pkg/logger"
+)
+
+func TestAuditSMSService_Send(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) sms.Service
+		numbers []string
+		// addErr 保存记录的错误，不影响发送的结果
+		addErr error
+
+		wantRecords []domain.SMSRecord
+		wantErr     error
+	}{
+		{
+			name: "发送成功，记下消息 ID",
+			mock: func(ctrl *gomock.Controller) sms.Service {
+				svc := smsmocks.NewMockResultService(ctrl)
+				svc.EXPECT().SendWithResult(gomock.Any(), "login_code", []string{"123456"},
+					"+8613812345678", "+8613912345678").
+					Return([]sms.SendResult{
+						{Number: "+8613812345678", MessageId: "sid-1"},
+						{Number: "+8613912345678", MessageId: "sid-2"},
+					}, nil)
+				return svc
+			},
+			numbers: []string{"+8613812345678", "+8613912345678"},
+			wantRecords: []domain.SMSRecord{
+				{Provider: "tencent", TplId: "login_code", Number: "+8613812345678",
+					MessageId: "sid-1", Status: domain.SMSRecordStatusSent},
+				{Provider: "tencent", TplId: "login_code", Number: "+8613912345678",
+					MessageId: "sid-2", Status: domain.SMSRecordStatusSent},
+			},
+		},
+		{
+			name: "部分号码失败",
+			mock: func(ctrl *gomock.Controller) sms.Service {
+				svc := smsmocks.NewMockResultService(ctrl)
+				svc.EXPECT().SendWithResult(gomock.Any(), "login_code", []string{"123456"},
+					"+8613812345678", "+8613912345678").
+					Return([]sms.SendResult{
+						{Number: "+8613812345678", MessageId: "sid-1"},
+						{Number: "+8613912345678", MessageId: "sid-2", Err: errors.New("空号")},
+					}, errors.New("空号"))
+				return svc
+			},
+			numbers: []string{"+8613812345678", "+8613912345678"},
+			wantRecords: []domain.SMSRecord{
+				{Provider: "tencent", TplId: "login_code", Number: "+8613812345678",
+					MessageId: "sid-1", Status: domain.SMSRecordStatusSent},
+				{Provider: "tencent", TplId: "login_code", Number: "+8613912345678",
+					MessageId: "sid-2", Status: domain.SMSRecordStatusFailed, Err: "空号"},
+			},
+			wantErr: errors.New("空号"),
+		},
+		{
+			name: "整个请求失败",
+			mock: func(ctrl *gomock.Controller) sms.Service {
+				svc := smsmocks.NewMockResultService(ctrl)
+				svc.EXPECT().SendWithResult(gomock.Any(), "login_code", []string{"123456"}, "+8613812345678").
+					Return(nil, errors.New("网络错误"))
+				return svc
+			},
+			numbers: []string{"+8613812345678"},
+			wantRecords: []domain.SMSRecord{
+				{Provider: "tencent", TplId: "login_code", Number: "+8613812345678",
+					Status: domain.SMSRecordStatusFailed, Err: "网络错误"},
+			},
+			wantErr: errors.New("网络错误"),
+		},
+		{
+			name: "服务商不返回消息 ID",
+			mock: func(ctrl *gomock.Controller) sms.Service {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "login_code", []string{"123456"}, "+8613812345678").
+					Return(nil)
+				return svc
+			},
+			numbers: []string{"+8613812345678"},
+			wantRecords: []domain.SMSRecord{
+				{Provider: "tencent", TplId: "login_code", Number: "+8613812345678",
+					Status: domain.SMSRecordStatusSent},
+			},
+		},
+		{
+			name: "保存记录失败，不影响发送",
+			mock: func(ctrl *gomock.Controller) sms.Service {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "login_code", []string{"123456"}, "+8613812345678").
+					Return(nil)
+				return svc
+			},
+			numbers: []string{"+8613812345678"},
+			addErr:  errors.New("数据库错误"),
+			wantRecords: []domain.SMSRecord{
+				{Provider: "tencent", TplId: "login_code", Number: "+8613812345678",
+					Status: domain.SMSRecordStatusSent},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomocks.NewMockSMSRecordRepository(ctrl)
+			repo.EXPECT().Add(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, rs ...domain.SMSRecord) error {
+					assert.Equal(t, tc.wantRecords, rs)
+					return tc.addErr
+				})
+			svc := NewAuditSMSService(tc.mock(ctrl), "tencent", repo, logger.NewZapLogger(zap.NewNop()))
+			// 固定时间，耗时就是 0
+			now := time.Now()
+			svc.now = func() time.Time { return now }
+			err := svc.Send(context.Background(), "login_code", []string{"123456"}, tc.numbers...)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
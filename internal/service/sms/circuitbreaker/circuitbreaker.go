@@ -0,0 +1,234 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+	"webook/internal/service/sms"
+	"webook/pkg/logger"
+)
+
+// ErrCircuitOpen 熔断中，没有真的发送
+var ErrCircuitOpen = errors.New("短信服务已熔断")
+
+type state uint8
+
+const (
+	// stateClosed 正常状态，所有请求都放过去
+	stateClosed state = iota
+	// stateOpen 熔断状态，所有请求都直接失败
+	stateOpen
+	// stateHalfOpen 熔断一段时间之后，放少量请求过去探测
+	stateHalfOpen
+)
+
+func (s state) String() string {
+	switch s {
+	case stateClosed:
+		return "closed"
+	case stateOpen:
+		return "open"
+	default:
+		return "half-open"
+	}
+}
+
+// CircuitBreakerSMSService 根据最近的请求的错误率和慢请求比例熔断
+// 熔断期间直接返回 ErrCircuitOpen，不会卡住调用者，
+// 放在 FailoverSMSService 里面的话，就会立刻换成下一个服务商
+type CircuitBreakerSMSService struct {
+	svc  sms.Service
+	name string
+	l    logger.Logger
+	now  func() time.Time
+
+	// 滑动窗口，记录最近 windowSize 个请求的结果
+	windowSize int
+	// 窗口里面的请求数量达到这个值才会计算比例，避免刚启动的时候一个失败就熔断
+	minRequests int
+	// 错误率达到这个值就熔断
+	errorRate float64
+	// 超过 slowThreshold 的是慢请求，慢请求的比例达到 slowRate 就熔断
+	slowThreshold time.Duration
+	slowRate      float64
+	// 熔断多久之后进入半开状态
+	openDuration time.Duration
+	// 半开状态下探测的请求数量，全部成功了才恢复
+	halfOpenProbes int
+
+	mu       sync.Mutex
+	state    state
+	window   []result
+	pos      int
+	openedAt time.Time
+	// 半开状态下已经放过去的请求数量和成功的数量
+	probing   int
+	succeeded int
+}
+
+type result struct {
+	failed bool
+	slow   bool
+}
+
+func NewCircuitBreakerSMSService(svc sms.Service, name string, l logger.Logger) *CircuitBreakerSMSService {
+	return &CircuitBreakerSMSService{
+		svc:            svc,
+		name:           name,
+		l:              l,
+		now:            time.Now,
+		windowSize:     20,
+		minRequests:    10,
+		errorRate:      0.5,
+		slowThreshold:  time.Second * 2,
+		slowRate:       0.5,
+		openDuration:   time.Second * 30,
+		halfOpenProbes: 3,
+	}
+}
+
+// Window 滑动窗口的大小，以及计算比例需要的最少请求数量
+func (c *CircuitBreakerSMSService) Window(size, minRequests int) *CircuitBreakerSMSService {
+	c.windowSize = size
+	c.minRequests = minRequests
+	return c
+}
+
+// ErrorRate 错误率达到 rate 就熔断
+func (c *CircuitBreakerSMSService) ErrorRate(rate float64) *CircuitBreakerSMSService {
+	c.errorRate = rate
+	return c
+}
+
+// Slow 超过 threshold 的是慢请求，慢请求的比例达到 rate 就熔断
+func (c *CircuitBreakerSMSService) Slow(threshold time.Duration, rate float64) *CircuitBreakerSMSService {
+	c.slowThreshold = threshold
+	c.slowRate = rate
+	return c
+}
+
+// OpenDuration 熔断多久之后开始探测
+func (c *CircuitBreakerSMSService) OpenDuration(d time.Duration) *CircuitBreakerSMSService {
+	c.openDuration = d
+	return c
+}
+
+// HalfOpenProbes 半开状态下探测的请求数量
+func (c *CircuitBreakerSMSService) HalfOpenProbes(n int) *CircuitBreakerSMSService {
+	c.halfOpenProbes = n
+	return c
+}
+
+func (c *CircuitBreakerSMSService) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	if !c.allow() {
+		return ErrCircuitOpen
+	}
+	start := c.now()
+	err := c.svc.Send(ctx, tplId, args, numbers...)
+	// 给服务商设置的超时到了，例如 FailoverSMSService 的超时，说明服务商卡住了，是失败也是慢请求
+	providerTimeout := ctx.Err() != nil && errors.Is(context.Cause(ctx), sms.ErrProviderTimeout)
+	if err != nil && ctx.Err() != nil && !providerTimeout {
+		// 调用者自己取消或者超时了，不算服务商的问题，但是半开状态下的名额要还回去
+		c.release()
+		return err
+	}
+	c.record(result{
+		failed: err != nil,
+		slow:   providerTimeout || c.now().Sub(start) > c.slowThreshold,
+	})
+	return err
+}
+
+func (c *CircuitBreakerSMSService) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch c.state {
+	case stateOpen:
+		if c.now().Sub(c.openedAt) < c.openDuration {
+			return false
+		}
+		c.transit(stateHalfOpen)
+		fallthrough
+	case stateHalfOpen:
+		if c.probing >= c.halfOpenProbes {
+			return false
+		}
+		c.probing++
+		return true
+	default:
+		return true
+	}
+}
+
+func (c *CircuitBreakerSMSService) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == stateHalfOpen && c.probing > 0 {
+		c.probing--
+	}
+}
+
+func (c *CircuitBreakerSMSService) record(r result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch c.state {
+	case stateHalfOpen:
+		if r.failed || r.slow {
+			// 探测失败，继续熔断
+			c.transit(stateOpen)
+			return
+		}
+		c.succeeded++
+		if c.succeeded >= c.halfOpenProbes {
+			c.transit(stateClosed)
+		}
+	case stateClosed:
+		if len(c.window) < c.windowSize {
+			c.window = append(c.window, r)
+		} else {
+			c.window[c.pos] = r
+			c.pos = (c.pos + 1) % c.windowSize
+		}
+		if c.shouldOpen() {
+			c.transit(stateOpen)
+		}
+	}
+	// 熔断状态下完成的请求是熔断之前放过去的，忽略
+}
+
+func (c *CircuitBreakerSMSService) shouldOpen() bool {
+	total := len(c.window)
+	if total < c.minRequests {
+		return false
+	}
+	var failed, slow int
+	for _, r := range c.window {
+		if r.failed {
+			failed++
+		}
+		if r.slow {
+			slow++
+		}
+	}
+	return float64(failed)/float64(total) >= c.errorRate ||
+		float64(slow)/float64(total) >= c.slowRate
+}
+
+// transit 切换状态，调用者需要持有锁
+func (c *CircuitBreakerSMSService) transit(to state) {
+	from := c.state
+	c.state = to
+	c.probing = 0
+	c.succeeded = 0
+	switch to {
+	case stateOpen:
+		c.openedAt = c.now()
+	case stateClosed:
+		// 重新开始统计
+		c.window = c.window[:0]
+		c.pos = 0
+	}
+	c.l.Warn("短信服务熔断状态变化", logger.String("name", c.name),
+		logger.String("from", from.String()), logger.String("to", to.String()))
+}
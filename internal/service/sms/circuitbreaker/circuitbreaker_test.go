@@ -0,0 +1,125 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+	"webook/internal/service/sms"
+	smsmocks "webook/internal/service/sms/mocks"
+	"webook/pkg/logger"
+)
+
+func TestCircuitBreakerSMSService_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := smsmocks.NewMockService(ctrl)
+	now := time.Now()
+	cb := NewCircuitBreakerSMSService(svc, "mock", logger.NewZapLogger(zap.NewNop())).
+		Window(4, 4).ErrorRate(0.5).OpenDuration(time.Minute).HalfOpenProbes(1)
+	cb.now = func() time.Time { return now }
+	send := func() error {
+		return cb.Send(context.Background(), "mytpl", []string{"123"}, "152xxx")
+	}
+	mockErr := errors.New("发送不了")
+
+	// 窗口里面一半失败，熔断
+	gomock.InOrder(
+		svc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		svc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockErr),
+		svc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		svc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockErr),
+	)
+	assert.NoError(t, send())
+	assert.Equal(t, mockErr, send())
+	assert.NoError(t, send())
+	assert.Equal(t, mockErr, send())
+	// 熔断期间直接失败，不会调用服务商
+	assert.Equal(t, ErrCircuitOpen, send())
+
+	// 半开，探测失败，继续熔断
+	now = now.Add(time.Minute)
+	svc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockErr)
+	assert.Equal(t, mockErr, send())
+	assert.Equal(t, ErrCircuitOpen, send())
+
+	// 再次半开，探测成功，恢复
+	now = now.Add(time.Minute)
+	svc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	assert.NoError(t, send())
+	assert.NoError(t, send())
+}
+
+func TestCircuitBreakerSMSService_Slow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := smsmocks.NewMockService(ctrl)
+	now := time.Now()
+	cb := NewCircuitBreakerSMSService(svc, "mock", logger.NewZapLogger(zap.NewNop())).
+		Window(2, 2).Slow(time.Second, 1)
+	cb.now = func() time.Time { return now }
+	// 每个请求都很慢，虽然成功了，也会熔断
+	svc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tplId string, args []string, numbers ...string) error {
+			now = now.Add(time.Second * 2)
+			return nil
+		}).Times(2)
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, cb.Send(context.Background(), "mytpl", []string{"123"}, "152xxx"))
+	}
+	assert.Equal(t, ErrCircuitOpen, cb.Send(context.Background(), "mytpl", []string{"123"}, "152xxx"))
+}
+
+func TestCircuitBreakerSMSService_Timeout(t *testing.T) {
+	testCases := []struct {
+		name string
+		// ctx 调用者传进来的 ctx，超时之后服务商才返回
+		ctx func() (context.Context, context.CancelFunc)
+
+		wantOpen bool
+	}{
+		{
+			name: "服务商超时，熔断",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeoutCause(context.Background(), time.Millisecond*10, sms.ErrProviderTimeout)
+			},
+			wantOpen: true,
+		},
+		{
+			name: "调用者自己超时，不算服务商的问题",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Millisecond*10)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := smsmocks.NewMockService(ctrl)
+			cb := NewCircuitBreakerSMSService(svc, "mock", logger.NewZapLogger(zap.NewNop())).
+				Window(2, 2).ErrorRate(1)
+			// 服务商卡住了，一直等到 ctx 超时
+			svc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, tplId string, args []string, numbers ...string) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}).Times(2)
+			for i := 0; i < 2; i++ {
+				ctx, cancel := tc.ctx()
+				err := cb.Send(ctx, "mytpl", []string{"123"}, "152xxx")
+				cancel()
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			}
+			if !tc.wantOpen {
+				svc.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				assert.NoError(t, cb.Send(context.Background(), "mytpl", []string{"123"}, "152xxx"))
+				return
+			}
+			assert.Equal(t, ErrCircuitOpen, cb.Send(context.Background(), "mytpl", []string{"123"}, "152xxx"))
+		})
+	}
+}
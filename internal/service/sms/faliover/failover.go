@@ -3,29 +3,216 @@ package failover
 import (
 	"context"
 	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"sync"
+	"sync/atomic"
+	"time"
 	"webook/internal/service/sms"
+	"webook/pkg/logger"
 )
 
+var ErrAllFailed = errors.New("发送失败，所有服务商都尝试过了")
+
+// Provider 一个短信服务商
+type Provider struct {
+	// Name 用在日志和监控里面
+	Name string
+	Svc  sms.Service
+}
+
+// FailoverSMSService 轮流从不同的服务商开始尝试，一个失败了就换下一个
+// 连续失败（包括超时）达到阈值的服务商会被标记为不健康，之后的请求都会跳过它，
+// 每隔一段时间放一个请求过去探测，探测成功了就恢复
 type FailoverSMSService struct {
-	// 一大堆可供选择的 SMS Service 实现
-	svcs []sms.Service
+	providers []*provider
 
+	// 下一次从哪个服务商开始
 	idx uint64
+
+	// 连续失败多少次之后切走
+	threshold int
+	// 不健康的服务商多久探测一次
+	probeInterval time.Duration
+	// 单个服务商的超时时间，0 表示不单独设置
+	timeout time.Duration
+
+	counter *prometheus.CounterVec
+	l       logger.Logger
+	now     func() time.Time
 }
 
-func NewFailoverSMSService(svcs []sms.Service) *FailoverSMSService {
+func NewFailoverSMSService(providers []Provider, l logger.Logger) *FailoverSMSService {
+	ps := make([]*provider, 0, len(providers))
+	for _, p := range providers {
+		ps = append(ps, &provider{Provider: p, healthy: true})
+	}
 	return &FailoverSMSService{
-		svcs: svcs,
+		providers:     ps,
+		threshold:     3,
+		probeInterval: time.Second * 30,
+		timeout:       time.Second * 3,
+		l:             l,
+		now:           time.Now,
 	}
 }
 
+// Threshold 连续失败多少次之后认为服务商不健康
+func (f *FailoverSMSService) Threshold(n int) *FailoverSMSService {
+	f.threshold = n
+	return f
+}
+
+// ProbeInterval 不健康的服务商多久放一个请求过去探测
+func (f *FailoverSMSService) ProbeInterval(d time.Duration) *FailoverSMSService {
+	f.probeInterval = d
+	return f
+}
+
+// Timeout 单个服务商的超时时间，超时了就换下一个
+func (f *FailoverSMSService) Timeout(d time.Duration) *FailoverSMSService {
+	f.timeout = d
+	return f
+}
+
+// WithMetrics 按照服务商记录成功、失败和超时的次数
+// 同一个进程里面可能会创建多个实例，例如测试或者多个区号各自用一个，已经注册过的直接复用
+func (f *FailoverSMSService) WithMetrics(opt prometheus.CounterOpts) *FailoverSMSService {
+	counter := prometheus.NewCounterVec(opt, []string{"provider", "result"})
+	err := prometheus.Register(counter)
+	if err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			panic(err)
+		}
+		counter = are.ExistingCollector.(*prometheus.CounterVec)
+	}
+	f.counter = counter
+	return f
+}
+
 func (f *FailoverSMSService) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
-	for _, svc := range f.svcs {
-		err := svc.Send(ctx, tplId, args, numbers...)
+	n := uint64(len(f.providers))
+	if n == 0 {
+		return ErrAllFailed
+	}
+	// 轮流从不同的服务商开始，把压力分摊开
+	start := atomic.AddUint64(&f.idx, 1) - 1
+	var skipped []*provider
+	for i := uint64(0); i < n; i++ {
+		p := f.providers[(start+i)%n]
+		if !p.available(f.now(), f.probeInterval) {
+			skipped = append(skipped, p)
+			continue
+		}
+		err := f.sendTo(ctx, p, tplId, args, numbers)
 		if err == nil {
 			return nil
 		}
-		// TODO 考虑打日志
+		if ctx.Err() != nil {
+			// 调用者已经不等了，换服务商也没有意义
+			return ctx.Err()
+		}
+	}
+	// 所有的服务商都不健康，那就不管健康状态，每个都试一下
+	if len(skipped) == int(n) {
+		for _, p := range skipped {
+			err := f.sendTo(ctx, p, tplId, args, numbers)
+			if err == nil {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+	return ErrAllFailed
+}
+
+func (f *FailoverSMSService) sendTo(ctx context.Context, p *provider,
+	tplId string, args []string, numbers []string) error {
+	sendCtx := ctx
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		// 带上 cause，里面的熔断器才知道这是服务商超时了，而不是调用者取消了
+		sendCtx, cancel = context.WithTimeoutCause(ctx, f.timeout, sms.ErrProviderTimeout)
+		defer cancel()
+	}
+	err := p.Svc.Send(sendCtx, tplId, args, numbers...)
+	switch {
+	case err == nil:
+		f.report(p, "success")
+		if p.onSuccess() {
+			f.l.Info("短信服务商恢复正常", logger.String("provider", p.Name))
+		}
+		return nil
+	case ctx.Err() != nil:
+		// 调用者自己取消或者超时了，不算服务商的问题
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		f.report(p, "timeout")
+	default:
+		f.report(p, "failed")
+	}
+	f.l.Warn("短信服务商发送失败", logger.String("provider", p.Name), logger.Error(err))
+	if p.onFailure(f.now(), f.threshold, f.probeInterval) {
+		f.l.Error("短信服务商连续失败，切换到其它服务商",
+			logger.String("provider", p.Name), logger.Int64("threshold", int64(f.threshold)))
+	}
+	return err
+}
+
+func (f *FailoverSMSService) report(p *provider, result string) {
+	if f.counter != nil {
+		f.counter.WithLabelValues(p.Name, result).Inc()
+	}
+}
+
+// provider 服务商以及它的健康状态
+type provider struct {
+	Provider
+
+	mu sync.Mutex
+	// 连续失败的次数
+	failures int
+	healthy  bool
+	// 不健康的时候，下一次探测的时间
+	nextProbe time.Time
+}
+
+// available 健康的，或者到了探测时间的服务商可以用
+// 同一时间只会放一个请求过去探测
+func (p *provider) available(now time.Time, probeInterval time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.healthy {
+		return true
+	}
+	if now.Before(p.nextProbe) {
+		return false
+	}
+	p.nextProbe = now.Add(probeInterval)
+	return true
+}
+
+// onSuccess 返回是不是从不健康恢复过来了
+func (p *provider) onSuccess() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	recovered := !p.healthy
+	p.failures = 0
+	p.healthy = true
+	return recovered
+}
+
+// onFailure 返回是不是刚刚变成不健康
+func (p *provider) onFailure(now time.Time, threshold int, probeInterval time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures++
+	if p.healthy && p.failures >= threshold {
+		p.healthy = false
+		p.nextProbe = now.Add(probeInterval)
+		return true
 	}
-	return errors.New("发送失败，所有服务商都尝试过了")
+	return false
 }
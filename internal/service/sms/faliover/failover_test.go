@@ -3,11 +3,16 @@ package failover
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
 	"testing"
+	"time"
 	"webook/internal/service/sms"
 	smsmocks "webook/internal/service/sms/mocks"
+	"webook/pkg/logger"
 )
 
 func TestFailoverSMSService_Send(t *testing.T) {
@@ -53,7 +58,7 @@ func TestFailoverSMSService_Send(t *testing.T) {
 					Return(errors.New("还是失败"))
 				return []sms.Service{svc0, svc1}
 			},
-			wantErr: errors.New("发送失败，所有服务商都尝试过了"),
+			wantErr: ErrAllFailed,
 		},
 	}
 
@@ -61,10 +66,89 @@ func TestFailoverSMSService_Send(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
-			svc := NewFailoverSMSService(tc.mock(ctrl))
+			svc := NewFailoverSMSService(providers(tc.mock(ctrl)...), logger.NewZapLogger(zap.NewNop()))
 			err := svc.Send(context.Background(), "mytpl",
 				[]string{"123"}, "152xxx")
 			assert.Equal(t, tc.wantErr, err)
 		})
 	}
 }
+
+func TestFailoverSMSService_Health(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc0 := smsmocks.NewMockService(ctrl)
+	svc1 := smsmocks.NewMockService(ctrl)
+	now := time.Now()
+	svc := NewFailoverSMSService(providers(svc0, svc1), logger.NewZapLogger(zap.NewNop())).
+		Threshold(2).ProbeInterval(time.Minute)
+	svc.now = func() time.Time { return now }
+	send := func() error {
+		return svc.Send(context.Background(), "mytpl", []string{"123"}, "152xxx")
+	}
+
+	// 轮流从 svc0 和 svc1 开始，svc0 连续失败两次之后被标记为不健康
+	svc0.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("发送不了")).Times(2)
+	svc1.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).Times(5)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, send())
+	}
+	// 从 svc0 开始，但是 svc0 已经被跳过了
+	assert.NoError(t, send())
+
+	// 到了探测时间，放一个请求给 svc0，探测成功之后恢复
+	now = now.Add(time.Minute)
+	svc0.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).Times(2)
+	svc1.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).Times(2)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, send())
+	}
+}
+
+func TestFailoverSMSService_Timeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc0 := smsmocks.NewMockService(ctrl)
+	svc0.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tplId string, args []string, numbers ...string) error {
+			<-ctx.Done()
+			// 熔断器靠 cause 分辨是服务商超时，不是调用者取消
+			assert.ErrorIs(t, context.Cause(ctx), sms.ErrProviderTimeout)
+			return ctx.Err()
+		})
+	svc1 := smsmocks.NewMockService(ctrl)
+	svc1.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	svc := NewFailoverSMSService(providers(svc0, svc1), logger.NewZapLogger(zap.NewNop())).
+		Timeout(time.Millisecond * 10)
+	// 第一个服务商超时之后换成第二个
+	err := svc.Send(context.Background(), "mytpl", []string{"123"}, "152xxx")
+	assert.NoError(t, err)
+}
+
+func TestFailoverSMSService_WithMetrics(t *testing.T) {
+	opt := prometheus.CounterOpts{
+		Namespace: "webook_test",
+		Subsystem: "failover",
+		Name:      "sms_provider_send",
+	}
+	l := logger.NewZapLogger(zap.NewNop())
+	svc1 := NewFailoverSMSService(nil, l).WithMetrics(opt)
+	// 第二次不能 panic，而是复用已经注册的
+	var svc2 *FailoverSMSService
+	assert.NotPanics(t, func() {
+		svc2 = NewFailoverSMSService(nil, l).WithMetrics(opt)
+	})
+	assert.Same(t, svc1.counter, svc2.counter)
+}
+
+func providers(svcs ...sms.Service) []Provider {
+	res := make([]Provider, 0, len(svcs))
+	for i, svc := range svcs {
+		res = append(res, Provider{Name: fmt.Sprintf("svc%d", i), Svc: svc})
+	}
+	return res
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"encoding/json"
+	"time"
+	"webook/internal/domain"
+)
+
+// ReceiptParser 假的短信服务的状态报告，本地开发的时候自己往 /sms/callback/fake 推送，
+// 格式是 [{"messageId": "fake-1", "delivered": true, "code": "DELIVRD"}]
+type ReceiptParser struct{}
+
+type receipt struct {
+	MessageId string `json:"messageId"`
+	Delivered bool   `json:"delivered"`
+	Code      string `json:"code"`
+	Msg       string `json:"msg"`
+}
+
+func (ReceiptParser) Parse(body []byte) ([]domain.SMSReceipt, error) {
+	var rs []receipt
+	if err := json.Unmarshal(body, &rs); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	res := make([]domain.SMSReceipt, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, domain.SMSReceipt{
+			MessageId:  r.MessageId,
+			Delivered:  r.Delivered,
+			Code:       r.Code,
+			Msg:        r.Msg,
+			ReportTime: now,
+		})
+	}
+	return res, nil
+}
+
+func (ReceiptParser) Ack() any {
+	return map[string]any{"msg": "OK"}
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+	"webook/internal/service/sms"
+)
+
+// Message 一条发送出去的短信
+type Message struct {
+	// MessageId 模拟服务商的消息 ID，可以拿来模拟状态报告
+	MessageId string    `json:"messageId"`
+	TplId     string    `json:"tplId"`
+	Args      []string  `json:"args"`
+	Phone     string    `json:"phone"`
+	Ctime     time.Time `json:"ctime"`
+}
+
+// Service 假的短信服务，只是把短信存在内存里，用于本地开发和集成测试
+// 每个手机号只保留最近的 capacity 条
+type Service struct {
+	mu       sync.RWMutex
+	msgs     map[string][]Message
+	capacity int
+	seq      int64
+}
+
+func NewService(capacity int) *Service {
+	if capacity <= 0 {
+		capacity = 20
+	}
+	return &Service{
+		msgs:     make(map[string][]Message, 16),
+		capacity: capacity,
+	}
+}
+
+func (s *Service) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	_, err := s.SendWithResult(ctx, tplId, args, numbers...)
+	return err
+}
+
+// SendWithResult 每个号码一个消息 ID，和腾讯云一样
+func (s *Service) SendWithResult(ctx context.Context, tplId string, args []string,
+	numbers ...string) ([]sms.SendResult, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make([]sms.SendResult, 0, len(numbers))
+	for _, phone := range numbers {
+		s.seq++
+		msgId := "fake-" + strconv.FormatInt(s.seq, 10)
+		msgs := append(s.msgs[phone], Message{
+			MessageId: msgId,
+			TplId:     tplId,
+			Args:      args,
+			Phone:     phone,
+			Ctime:     now,
+		})
+		if len(msgs) > s.capacity {
+			msgs = msgs[len(msgs)-s.capacity:]
+		}
+		s.msgs[phone] = msgs
+		results = append(results, sms.SendResult{Number: phone, MessageId: msgId})
+	}
+	return results, nil
+}
+
+// Latest 某个手机号最近收到的短信，最新的在前面
+func (s *Service) Latest(phone string, limit int) []Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	msgs := s.msgs[phone]
+	if limit <= 0 || limit > len(msgs) {
+		limit = len(msgs)
+	}
+	res := make([]Message, 0, limit)
+	for i := len(msgs) - 1; i >= len(msgs)-limit; i-- {
+		res = append(res, msgs[i])
+	}
+	return res
+}
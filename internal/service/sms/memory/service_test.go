@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestService_Latest(t *testing.T) {
+	svc := NewService(2)
+	ctx := context.Background()
+	require.NoError(t, svc.Send(ctx, "tpl", []string{"1"}, "152xxx", "153xxx"))
+	require.NoError(t, svc.Send(ctx, "tpl", []string{"2"}, "152xxx"))
+	require.NoError(t, svc.Send(ctx, "tpl", []string{"3"}, "152xxx"))
+
+	// 只保留最近的两条，最新的在前面
+	msgs := svc.Latest("152xxx", 0)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, []string{"3"}, msgs[0].Args)
+	assert.Equal(t, []string{"2"}, msgs[1].Args)
+
+	msgs = svc.Latest("152xxx", 1)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, []string{"3"}, msgs[0].Args)
+
+	msgs = svc.Latest("153xxx", 10)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "153xxx", msgs[0].Phone)
+
+	assert.Empty(t, svc.Latest("154xxx", 10))
+}
+
+func TestService_SendWithResult(t *testing.T) {
+	svc := NewService(2)
+	res, err := svc.SendWithResult(context.Background(), "tpl", []string{"1"}, "152xxx", "153xxx")
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.NotEqual(t, res[0].MessageId, res[1].MessageId)
+	// 收件箱里面可以看到消息 ID，用来模拟状态报告
+	assert.Equal(t, res[1].MessageId, svc.Latest("153xxx", 1)[0].MessageId)
+
+	receipts, err := ReceiptParser{}.Parse([]byte(`[{"messageId":"` + res[0].MessageId + `","delivered":true,"code":"DELIVRD"}]`))
+	require.NoError(t, err)
+	require.Len(t, receipts, 1)
+	assert.Equal(t, res[0].MessageId, receipts[0].MessageId)
+	assert.True(t, receipts[0].Delivered)
+}
@@ -12,6 +12,8 @@ package smsmocks
 import (
 	context "context"
 	reflect "reflect"
+	domain "webook/internal/domain"
+	sms "webook/internal/service/sms"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -58,3 +60,119 @@ func (mr *MockServiceMockRecorder) Send(ctx, tplId, args any, numbers ...any) *g
 	varargs := append([]any{ctx, tplId, args}, numbers...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockService)(nil).Send), varargs...)
 }
+
+// MockResultService is a mock of ResultService interface.
+type MockResultService struct {
+	ctrl     *gomock.Controller
+	recorder *MockResultServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockResultServiceMockRecorder is the mock recorder for MockResultService.
+type MockResultServiceMockRecorder struct {
+	mock *MockResultService
+}
+
+// NewMockResultService creates a new mock instance.
+func NewMockResultService(ctrl *gomock.Controller) *MockResultService {
+	mock := &MockResultService{ctrl: ctrl}
+	mock.recorder = &MockResultServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResultService) EXPECT() *MockResultServiceMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockResultService) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, tplId, args}
+	for _, a := range numbers {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Send", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockResultServiceMockRecorder) Send(ctx, tplId, args any, numbers ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, tplId, args}, numbers...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockResultService)(nil).Send), varargs...)
+}
+
+// SendWithResult mocks base method.
+func (m *MockResultService) SendWithResult(ctx context.Context, tplId string, args []string, numbers ...string) ([]sms.SendResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, tplId, args}
+	for _, a := range numbers {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendWithResult", varargs...)
+	ret0, _ := ret[0].([]sms.SendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendWithResult indicates an expected call of SendWithResult.
+func (mr *MockResultServiceMockRecorder) SendWithResult(ctx, tplId, args any, numbers ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, tplId, args}, numbers...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendWithResult", reflect.TypeOf((*MockResultService)(nil).SendWithResult), varargs...)
+}
+
+// MockReceiptParser is a mock of ReceiptParser interface.
+type MockReceiptParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockReceiptParserMockRecorder
+	isgomock struct{}
+}
+
+// MockReceiptParserMockRecorder is the mock recorder for MockReceiptParser.
+type MockReceiptParserMockRecorder struct {
+	mock *MockReceiptParser
+}
+
+// NewMockReceiptParser creates a new mock instance.
+func NewMockReceiptParser(ctrl *gomock.Controller) *MockReceiptParser {
+	mock := &MockReceiptParser{ctrl: ctrl}
+	mock.recorder = &MockReceiptParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReceiptParser) EXPECT() *MockReceiptParserMockRecorder {
+	return m.recorder
+}
+
+// Ack mocks base method.
+func (m *MockReceiptParser) Ack() any {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ack")
+	ret0, _ := ret[0].(any)
+	return ret0
+}
+
+// Ack indicates an expected call of Ack.
+func (mr *MockReceiptParserMockRecorder) Ack() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ack", reflect.TypeOf((*MockReceiptParser)(nil).Ack))
+}
+
+// Parse mocks base method.
+func (m *MockReceiptParser) Parse(body []byte) ([]domain.SMSReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Parse", body)
+	ret0, _ := ret[0].([]domain.SMSReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Parse indicates an expected call of Parse.
+func (mr *MockReceiptParserMockRecorder) Parse(body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parse", reflect.TypeOf((*MockReceiptParser)(nil).Parse), body)
+}
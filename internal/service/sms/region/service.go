@@ -0,0 +1,46 @@
+package region
+
+import (
+	"context"
+	"fmt"
+	"webook/internal/service/sms"
+	"webook/pkg/phonex"
+)
+
+// RegionSMSService 按照号码的国际电话区号选择服务商，例如国内的号码用腾讯云，港澳台和海外的用阿里云
+// 号码必须是 E.164 格式，没有配置的区号返回 sms.ErrUnsupportedRegion
+type RegionSMSService struct {
+	// routes 国际电话区号（不带加号）到服务商
+	routes map[string]sms.Service
+}
+
+func NewRegionSMSService(routes map[string]sms.Service) *RegionSMSService {
+	return &RegionSMSService{routes: routes}
+}
+
+func (r *RegionSMSService) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	// 先全部检查一遍，有不支持的号码就一条都不发
+	groups := make(map[string][]string, 1)
+	// 保持号码出现的顺序，方便排查问题
+	var order []string
+	for _, n := range numbers {
+		cc, err := phonex.CountryCode(n)
+		if err != nil {
+			return fmt.Errorf("%w: %s", sms.ErrUnsupportedRegion, n)
+		}
+		if _, ok := r.routes[cc]; !ok {
+			return fmt.Errorf("%w: +%s", sms.ErrUnsupportedRegion, cc)
+		}
+		if _, ok := groups[cc]; !ok {
+			order = append(order, cc)
+		}
+		groups[cc] = append(groups[cc], n)
+	}
+	for _, cc := range order {
+		err := r.routes[cc].Send(ctx, tplId, args, groups[cc]...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
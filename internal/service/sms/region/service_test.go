@@ -0,0 +1,84 @@
+package region
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/service/sms"
+	smsmocks "webook/internal/service/sms/mocks"
+)
+
+func TestRegionSMSService_Send(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) (cn *smsmocks.MockService, hk *smsmocks.MockService)
+		numbers []string
+
+		wantErr error
+	}{
+		{
+			name: "国内号码",
+			mock: func(ctrl *gomock.Controller) (*smsmocks.MockService, *smsmocks.MockService) {
+				cn := smsmocks.NewMockService(ctrl)
+				cn.EXPECT().Send(gomock.Any(), "login_code", []string{"123456"},
+					"+8613812345678", "+8613912345678").Return(nil)
+				return cn, smsmocks.NewMockService(ctrl)
+			},
+			numbers: []string{"+8613812345678", "+8613912345678"},
+		},
+		{
+			name: "按照区号分开发送",
+			mock: func(ctrl *gomock.Controller) (*smsmocks.MockService, *smsmocks.MockService) {
+				cn := smsmocks.NewMockService(ctrl)
+				hk := smsmocks.NewMockService(ctrl)
+				cn.EXPECT().Send(gomock.Any(), "login_code", []string{"123456"}, "+8613812345678").Return(nil)
+				hk.EXPECT().Send(gomock.Any(), "login_code", []string{"123456"}, "+85291234567").Return(nil)
+				return cn, hk
+			},
+			numbers: []string{"+8613812345678", "+85291234567"},
+		},
+		{
+			name: "服务商发送失败",
+			mock: func(ctrl *gomock.Controller) (*smsmocks.MockService, *smsmocks.MockService) {
+				hk := smsmocks.NewMockService(ctrl)
+				hk.EXPECT().Send(gomock.Any(), "login_code", []string{"123456"}, "+85291234567").
+					Return(errors.New("发送失败"))
+				return smsmocks.NewMockService(ctrl), hk
+			},
+			numbers: []string{"+85291234567"},
+			wantErr: errors.New("发送失败"),
+		},
+		{
+			name: "有不支持的地区，一条都不发",
+			mock: func(ctrl *gomock.Controller) (*smsmocks.MockService, *smsmocks.MockService) {
+				return smsmocks.NewMockService(ctrl), smsmocks.NewMockService(ctrl)
+			},
+			numbers: []string{"+8613812345678", "+447911123456"},
+			wantErr: sms.ErrUnsupportedRegion,
+		},
+		{
+			name: "不是 E.164 格式",
+			mock: func(ctrl *gomock.Controller) (*smsmocks.MockService, *smsmocks.MockService) {
+				return smsmocks.NewMockService(ctrl), smsmocks.NewMockService(ctrl)
+			},
+			numbers: []string{"13812345678"},
+			wantErr: sms.ErrUnsupportedRegion,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cn, hk := tc.mock(ctrl)
+			svc := NewRegionSMSService(map[string]sms.Service{"86": cn, "852": hk})
+			err := svc.Send(context.Background(), "login_code", []string{"123456"}, tc.numbers...)
+			if errors.Is(tc.wantErr, sms.ErrUnsupportedRegion) {
+				assert.ErrorIs(t, err, sms.ErrUnsupportedRegion)
+				return
+			}
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
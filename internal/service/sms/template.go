@@ -0,0 +1,107 @@
+package sms
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownTemplate = errors.New("未知的短信模板")
+
+// Template 业务上的短信模板，例如 login_code
+// 业务方只需要知道模板的名字和参数的顺序，
+// 不同服务商的模板 ID、签名和参数的格式由各自的实现根据 TemplateRegistry 转换
+type Template struct {
+	Name string
+	// Params 参数的名字，调用 Service.Send 的时候 args 按照这个顺序传
+	Params []string
+	// Providers 服务商的名字到服务商的模板
+	Providers map[string]ProviderTemplate
+}
+
+// ProviderTemplate 一个模板在某个服务商那边的配置
+type ProviderTemplate struct {
+	TplId string
+	// SignName 为空的时候使用服务商默认的签名
+	SignName string
+	// Params 服务商那边的参数名字和顺序，必须都是 Template.Params 里面的
+	// 为空的时候和 Template.Params 一样
+	Params []string
+}
+
+// Param 渲染好的一个参数
+type Param struct {
+	Name  string
+	Value string
+}
+
+// RenderedTemplate 替换成某个服务商的模板之后的结果
+type RenderedTemplate struct {
+	TplId    string
+	SignName string
+	// Params 按照服务商要求的顺序排列
+	Params []Param
+}
+
+// TemplateRegistry 所有的短信模板
+type TemplateRegistry struct {
+	tpls map[string]Template
+}
+
+func NewTemplateRegistry(tpls ...Template) (*TemplateRegistry, error) {
+	r := &TemplateRegistry{tpls: make(map[string]Template, len(tpls))}
+	for _, tpl := range tpls {
+		if _, ok := r.tpls[tpl.Name]; ok {
+			return nil, fmt.Errorf("重复的短信模板 %s", tpl.Name)
+		}
+		for provider, pt := range tpl.Providers {
+			if pt.TplId == "" {
+				return nil, fmt.Errorf("短信模板 %s 没有配置 %s 的模板 ID", tpl.Name, provider)
+			}
+			for _, p := range pt.Params {
+				if index(tpl.Params, p) < 0 {
+					return nil, fmt.Errorf("短信模板 %s 在 %s 的参数 %s 不存在", tpl.Name, provider, p)
+				}
+			}
+		}
+		r.tpls[tpl.Name] = tpl
+	}
+	return r, nil
+}
+
+// Render 把模板和参数转换成 provider 的格式
+func (r *TemplateRegistry) Render(name, provider string, args []string) (RenderedTemplate, error) {
+	tpl, ok := r.tpls[name]
+	if !ok {
+		return RenderedTemplate{}, fmt.Errorf("%w %s", ErrUnknownTemplate, name)
+	}
+	pt, ok := tpl.Providers[provider]
+	if !ok {
+		return RenderedTemplate{}, fmt.Errorf("%w %s 没有配置服务商 %s", ErrUnknownTemplate, name, provider)
+	}
+	if len(args) != len(tpl.Params) {
+		return RenderedTemplate{}, fmt.Errorf("短信模板 %s 需要 %d 个参数，实际是 %d 个",
+			name, len(tpl.Params), len(args))
+	}
+	names := pt.Params
+	if len(names) == 0 {
+		names = tpl.Params
+	}
+	params := make([]Param, 0, len(names))
+	for _, n := range names {
+		params = append(params, Param{Name: n, Value: args[index(tpl.Params, n)]})
+	}
+	return RenderedTemplate{
+		TplId:    pt.TplId,
+		SignName: pt.SignName,
+		Params:   params,
+	}, nil
+}
+
+func index(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,89 @@
+package sms
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTemplateRegistry_Render(t *testing.T) {
+	r, err := NewTemplateRegistry(Template{
+		Name:   "login_code",
+		Params: []string{"code", "minutes"},
+		Providers: map[string]ProviderTemplate{
+			"tencent": {TplId: "2320764"},
+			// 参数的顺序和业务上的不一样
+			"aliyun": {TplId: "SMS_154950909", SignName: "webook", Params: []string{"minutes", "code"}},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		tpl      string
+		provider string
+		args     []string
+
+		want    RenderedTemplate
+		wantErr error
+	}{
+		{
+			name:     "使用业务上的参数顺序",
+			tpl:      "login_code",
+			provider: "tencent",
+			args:     []string{"123456", "10"},
+			want: RenderedTemplate{
+				TplId:  "2320764",
+				Params: []Param{{Name: "code", Value: "123456"}, {Name: "minutes", Value: "10"}},
+			},
+		},
+		{
+			name:     "使用服务商的参数顺序",
+			tpl:      "login_code",
+			provider: "aliyun",
+			args:     []string{"123456", "10"},
+			want: RenderedTemplate{
+				TplId:    "SMS_154950909",
+				SignName: "webook",
+				Params:   []Param{{Name: "minutes", Value: "10"}, {Name: "code", Value: "123456"}},
+			},
+		},
+		{
+			name:     "未知的模板",
+			tpl:      "bind_phone_code",
+			provider: "tencent",
+			args:     []string{"123456", "10"},
+			wantErr:  ErrUnknownTemplate,
+		},
+		{
+			name:     "服务商没有配置",
+			tpl:      "login_code",
+			provider: "fake",
+			args:     []string{"123456", "10"},
+			wantErr:  ErrUnknownTemplate,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := r.Render(tc.tpl, tc.provider, tc.args)
+			assert.True(t, errors.Is(err, tc.wantErr))
+			assert.Equal(t, tc.want, res)
+		})
+	}
+
+	// 参数的数量不对
+	_, err = r.Render("login_code", "tencent", []string{"123456"})
+	assert.Error(t, err)
+}
+
+func TestNewTemplateRegistry(t *testing.T) {
+	_, err := NewTemplateRegistry(Template{
+		Name:   "login_code",
+		Params: []string{"code"},
+		Providers: map[string]ProviderTemplate{
+			"aliyun": {TplId: "SMS_154950909", Params: []string{"number"}},
+		},
+	})
+	assert.Error(t, err)
+}
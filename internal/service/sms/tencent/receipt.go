@@ -0,0 +1,49 @@
+package tencent
+
+import (
+	"encoding/json"
+	"time"
+	"webook/internal/domain"
+)
+
+// beijing 状态报告里面的时间是北京时间，没有带时区
+var beijing = time.FixedZone("CST", 8*3600)
+
+// ReceiptParser 腾讯云短信的状态报告回调，一次推送多条
+// 在控制台配置回调地址 /sms/callback/tencent?token=xxx
+type ReceiptParser struct{}
+
+type receipt struct {
+	UserReceiveTime string `json:"user_receive_time"`
+	// ReportStatus SUCCESS 或者 FAIL
+	ReportStatus string `json:"report_status"`
+	ErrMsg       string `json:"errmsg"`
+	Description  string `json:"description"`
+	// Sid 就是发送的时候返回的 SerialNo
+	Sid string `json:"sid"`
+}
+
+func (ReceiptParser) Parse(body []byte) ([]domain.SMSReceipt, error) {
+	var rs []receipt
+	if err := json.Unmarshal(body, &rs); err != nil {
+		return nil, err
+	}
+	res := make([]domain.SMSReceipt, 0, len(rs))
+	for _, r := range rs {
+		// 格式不对的时候就没有时间，不影响更新状态
+		t, _ := time.ParseInLocation(time.DateTime, r.UserReceiveTime, beijing)
+		res = append(res, domain.SMSReceipt{
+			MessageId:  r.Sid,
+			Delivered:  r.ReportStatus == "SUCCESS",
+			Code:       r.ErrMsg,
+			Msg:        r.Description,
+			ReportTime: t,
+		})
+	}
+	return res, nil
+}
+
+// Ack 腾讯云要求返回 result 为 0，否则会重新推送
+func (ReceiptParser) Ack() any {
+	return map[string]any{"result": 0, "errmsg": "OK"}
+}
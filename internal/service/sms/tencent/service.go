@@ -6,34 +6,51 @@ import (
 	"github.com/ecodeclub/ekit"
 	"github.com/ecodeclub/ekit/slice"
 	sms "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/sms/v20210111"
+	smsx "webook/internal/service/sms"
 )
 
+// ProviderName 在 TemplateRegistry 里面的名字
+const ProviderName = "tencent"
+
 // Service 是实现了 sms.Service 接口的具体类型，用于调用腾讯云的短信服务
 // 它封装了调用腾讯云 SMS API 的相关操作
 type Service struct {
 	client   *sms.Client // 腾讯云短信服务的客户端
 	appId    *string     // 腾讯云短信应用的 ID
-	signName *string     // 短信签名名称
+	signName *string     // 短信签名名称，模板没有配置签名的时候使用
+	tpls     *smsx.TemplateRegistry
 }
 
 // NewService 创建并返回一个新的 Service 实例
 // client 是腾讯云短信服务的客户端，appId 和 signName 分别是短信应用 ID 和签名名称
 func NewService(c *sms.Client, appId string,
-	signName string) *Service {
+	signName string, tpls *smsx.TemplateRegistry) *Service {
 	return &Service{
 		client:   c,
 		appId:    ekit.ToPtr[string](appId),
 		signName: ekit.ToPtr[string](signName),
+		tpls:     tpls,
 	}
 }
 
 // Send 实现了 sms.Service 接口的 Send 方法，调用腾讯云的 API 发送短信
-// tplId 是短信模板 ID，args 是模板中占位符的参数，numbers 是目标手机号
+// tplId 是短信模板的名字，args 是模板中占位符的参数，numbers 是目标手机号
 func (s *Service) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	_, err := s.SendWithResult(ctx, tplId, args, numbers...)
+	return err
+}
+
+// SendWithResult 腾讯云会返回每个号码的受理结果，SerialNo 就是状态报告里面的 sid
+func (s *Service) SendWithResult(ctx context.Context, tplId string, args []string,
+	numbers ...string) ([]smsx.SendResult, error) {
+	tpl, err := s.tpls.Render(tplId, ProviderName, args)
+	if err != nil {
+		return nil, err
+	}
 	// 创建短信请求对象
 	req := sms.NewSendSmsRequest()
 
-	// 将手机号列表转换为指针切片
+	// 将手机号列表转换为指针切片，腾讯云支持 E.164 格式，例如 +8613812345678
 	req.PhoneNumberSet = toStringPtrSlice(numbers)
 
 	// 设置短信应用 ID
@@ -42,31 +59,44 @@ func (s *Service) Send(ctx context.Context, tplId string, args []string, numbers
 	// 传递上下文
 	req.SetContext(ctx)
 
-	// 设置短信模板参数
-	req.TemplateParamSet = toStringPtrSlice(args)
+	// 设置短信模板参数，腾讯云的参数是按照位置来的
+	req.TemplateParamSet = slice.Map(tpl.Params, func(idx int, src smsx.Param) *string {
+		return &src.Value
+	})
 
 	// 设置短信模板 ID
-	req.TemplateId = ekit.ToPtr[string](tplId)
+	req.TemplateId = ekit.ToPtr[string](tpl.TplId)
 
 	// 设置短信签名名称
 	req.SignName = s.signName
+	if tpl.SignName != "" {
+		req.SignName = ekit.ToPtr[string](tpl.SignName)
+	}
 
 	// 调用腾讯云短信服务的 API 发送短信
 	resp, err := s.client.SendSms(req)
 	if err != nil {
-		return err // 如果发生错误，直接返回
+		return nil, err // 如果发生错误，直接返回
 	}
 
 	// 检查发送状态，确保每一条短信都发送成功
+	results := make([]smsx.SendResult, 0, len(resp.Response.SendStatusSet))
 	for _, status := range resp.Response.SendStatusSet {
+		res := smsx.SendResult{
+			Number:    deref(status.PhoneNumber),
+			MessageId: deref(status.SerialNo),
+		}
 		if status.Code == nil || *(status.Code) != "Ok" {
+			res.Err = fmt.Errorf("发送失败，code: %s, 原因：%s",
+				deref(status.Code), deref(status.Message))
 			// 如果有任何一条短信发送失败，返回错误信息
-			return fmt.Errorf("发送失败，code: %s, 原因：%s", *status.Code, *status.Message)
+			if err == nil {
+				err = res.Err
+			}
 		}
+		results = append(results, res)
 	}
-
-	// 如果没有错误，返回 nil 表示发送成功
-	return nil
+	return results, err
 }
 
 // toStringPtrSlice 将字符串切片转换为字符串指针切片
@@ -76,3 +106,11 @@ func toStringPtrSlice(src []string) []*string {
 		return &src
 	})
 }
+
+// deref 腾讯云的响应里面字段都是指针，没有的时候当成空字符串
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -8,6 +8,7 @@ import (
 	sms "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/sms/v20210111"
 	"os"
 	"testing"
+	smsx "webook/internal/service/sms"
 )
 
 func TestService_Send(t *testing.T) {
@@ -28,7 +29,17 @@ func TestService_Send(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	s := NewService(c, "1400952398", "南絮0124公众号")
+	tpls, err := smsx.NewTemplateRegistry(smsx.Template{
+		Name:   "login_code",
+		Params: []string{"code"},
+		Providers: map[string]smsx.ProviderTemplate{
+			ProviderName: {TplId: "2320764"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(c, "1400952398", "南絮0124公众号", tpls)
 
 	testCases := []struct {
 		name    string
@@ -39,7 +50,7 @@ func TestService_Send(t *testing.T) {
 	}{
 		{
 			name:   "发送验证码",
-			tplId:  "2320764",
+			tplId:  "login_code",
 			params: []string{"666777"},
 			// 改成你的手机号码
 			numbers: []string{"151***"},
@@ -1,6 +1,36 @@
 package sms
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/domain"
+)
+
+var (
+	// ErrUnsupportedRegion 没有服务商可以发送到这个国家或地区的号码，重试也没有用
+	ErrUnsupportedRegion = errors.New("不支持发送短信到这个国家或地区")
+	// ErrProviderTimeout 给单个服务商设置的超时，作为 context.WithTimeoutCause 的 cause，
+	// 用来区分是服务商太慢还是调用者自己不等了
+	ErrProviderTimeout = errors.New("短信服务商响应超时")
+	// ErrDeferred 同步发送失败了，短信已经保存下来，稍后会异步重试
+	// 调用者可以告诉用户短信可能会晚一点到
+	ErrDeferred = errors.New("短信发送失败，稍后重试")
+)
+
+type deadlineKey struct{}
+
+// WithDeadline 过了 deadline 短信就没有意义了，例如验证码过期之后，
+// 异步重试的时候会直接丢弃，不会再发给用户
+func WithDeadline(ctx context.Context, deadline time.Time) context.Context {
+	return context.WithValue(ctx, deadlineKey{}, deadline)
+}
+
+// DeadlineFrom 取出 WithDeadline 设置的时间
+func DeadlineFrom(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(deadlineKey{}).(time.Time)
+	return deadline, ok
+}
 
 // Service 发送短信的抽象接口
 // 该接口定义了发送短信的基本操作，目的是为了适配不同的短信供应商。
@@ -11,10 +41,34 @@ import "context"
 type Service interface {
 	// Send 发送短信的方法
 	// ctx: 上下文，携带请求的元数据，方便做超时控制等操作
-	// tplId: 模板 ID，用于指定使用的短信模板
-	// args: 模板替换参数，短信模板中的占位符会被这些参数替换
+	// tplId: 模板的名字，例如 login_code，具体服务商的模板 ID 由 TemplateRegistry 决定
+	// args: 模板替换参数，按照 Template.Params 的顺序
 	// numbers: 目标手机号，可以传入一个或多个手机号，表示要发送短信的用户
 	// 返回值：发送失败时返回 error，成功时返回 nil
 	// 注意：该方法是发送短信的核心功能，不同的供应商会在这里实现具体的发送逻辑
 	Send(ctx context.Context, tplId string, args []string, numbers ...string) error
 }
+
+// SendResult 服务商对一个号码的受理结果
+type SendResult struct {
+	Number string
+	// MessageId 服务商的消息 ID，状态报告里面会带上
+	MessageId string
+	// Err 这个号码没有被受理的原因
+	Err error
+}
+
+// ResultService 能够返回每个号码受理结果的服务商，发送记录会用上
+// 返回的 error 和 Send 一样，有任何一个号码失败都不是 nil，
+// 这个时候服务商已经给出结果的号码依旧会在 []SendResult 里面
+type ResultService interface {
+	Service
+	SendWithResult(ctx context.Context, tplId string, args []string, numbers ...string) ([]SendResult, error)
+}
+
+// ReceiptParser 解析服务商推送过来的状态报告，每个服务商的格式都不一样
+type ReceiptParser interface {
+	Parse(body []byte) ([]domain.SMSReceipt, error)
+	// Ack 处理完之后返回给服务商的响应，服务商收不到约定的响应会重新推送
+	Ack() any
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+// SMSRecordService 短信的发送记录，记录本身是 sms/audit 在发送的时候写的，
+// 这里负责处理服务商的状态报告，以及给客服查询
+//
+//go:generate mockgen -source=./sms_record.go -package=svcmocks -destination=mocks/sms_record.mock.go SMSRecordService
+type SMSRecordService interface {
+	// HandleReceipts 处理 provider 推送过来的状态报告
+	HandleReceipts(ctx context.Context, provider string, receipts []domain.SMSReceipt) error
+	// ListByPhone phone 是 E.164 格式，最新的在前面
+	ListByPhone(ctx context.Context, phone string, offset, limit int) ([]domain.SMSRecord, error)
+}
+
+type smsRecordService struct {
+	repo repository.SMSRecordRepository
+}
+
+func NewSMSRecordService(repo repository.SMSRecordRepository) SMSRecordService {
+	return &smsRecordService{repo: repo}
+}
+
+func (svc *smsRecordService) HandleReceipts(ctx context.Context, provider string, receipts []domain.SMSReceipt) error {
+	for _, r := range receipts {
+		// 没有消息 ID 对应不上任何记录
+		if r.MessageId == "" {
+			continue
+		}
+		// 有一条失败就返回，服务商会重新推送整批，更新状态本来就是幂等的
+		if err := svc.repo.UpdateReport(ctx, provider, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (svc *smsRecordService) ListByPhone(ctx context.Context, phone string, offset, limit int) ([]domain.SMSRecord, error) {
+	return svc.repo.FindByNumber(ctx, phone, offset, limit)
+}
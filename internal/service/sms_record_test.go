@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+)
+
+func TestSMSRecordService_HandleReceipts(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) repository.SMSRecordRepository
+		receipts []domain.SMSReceipt
+
+		wantErr error
+	}{
+		{
+			name: "更新成功",
+			mock: func(ctrl *gomock.Controller) repository.SMSRecordRepository {
+				repo := repomocks.NewMockSMSRecordRepository(ctrl)
+				repo.EXPECT().UpdateReport(gomock.Any(), "tencent",
+					domain.SMSReceipt{MessageId: "sid-1", Delivered: true}).Return(nil)
+				repo.EXPECT().UpdateReport(gomock.Any(), "tencent",
+					domain.SMSReceipt{MessageId: "sid-2", Code: "MK:0001"}).Return(nil)
+				return repo
+			},
+			receipts: []domain.SMSReceipt{
+				{MessageId: "sid-1", Delivered: true},
+				{MessageId: "sid-2", Code: "MK:0001"},
+			},
+		},
+		{
+			name: "没有消息 ID 的跳过",
+			mock: func(ctrl *gomock.Controller) repository.SMSRecordRepository {
+				return repomocks.NewMockSMSRecordRepository(ctrl)
+			},
+			receipts: []domain.SMSReceipt{{Delivered: true}},
+		},
+		{
+			name: "更新失败",
+			mock: func(ctrl *gomock.Controller) repository.SMSRecordRepository {
+				repo := repomocks.NewMockSMSRecordRepository(ctrl)
+				repo.EXPECT().UpdateReport(gomock.Any(), "tencent", gomock.Any()).
+					Return(errors.New("数据库错误"))
+				return repo
+			},
+			receipts: []domain.SMSReceipt{
+				{MessageId: "sid-1", Delivered: true},
+				{MessageId: "sid-2", Delivered: true},
+			},
+			wantErr: errors.New("数据库错误"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewSMSRecordService(tc.mock(ctrl))
+			err := svc.HandleReceipts(context.Background(), "tencent", tc.receipts)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Service 把文件存放在本地磁盘上，本地开发或者单机部署的时候使用
+// 需要配合 Web 服务器把 dir 暴露成静态资源
+type Service struct {
+	dir string
+	// 访问 dir 的 URL 前缀
+	urlPrefix string
+}
+
+func NewService(dir, urlPrefix string) *Service {
+	return &Service{
+		dir:       dir,
+		urlPrefix: strings.TrimSuffix(urlPrefix, "/"),
+	}
+}
+
+func (s *Service) Upload(ctx context.Context, key string, data []byte) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(path, data, 0o644)
+	if err != nil {
+		return "", err
+	}
+	return s.urlPrefix + "/" + key, nil
+}
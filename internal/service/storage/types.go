@@ -0,0 +1,11 @@
+package storage
+
+import "context"
+
+// Service 文件存储的抽象接口，目前用来存放用户头像
+// 和 sms.Service 一样，目的是屏蔽不同的存储方式，例如本地磁盘、云厂商的对象存储等
+type Service interface {
+	// Upload 上传文件，返回可以直接访问的 URL
+	// key: 文件在存储中的路径，同一个 key 会覆盖
+	Upload(ctx context.Context, key string, data []byte) (string, error)
+}
@@ -3,55 +3,150 @@ package service
 import (
 	"context"
 	"errors"
-	"golang.org/x/crypto/bcrypt"
+	"fmt"
+	"github.com/google/uuid"
+	"strconv"
+	"strings"
+	"time"
 	"webook/internal/domain"
 	"webook/internal/repository"
+	"webook/internal/service/email"
+	"webook/internal/service/password"
 	"webook/pkg/logger"
 )
 
 var (
-	ErrUserDuplicateEmail    = repository.ErrUserDuplicate
+	ErrUserDuplicateEmail    = repository.ErrUserDuplicateEmail
+	ErrUserDuplicatePhone    = repository.ErrUserDuplicatePhone
 	ErrInvalidUserOrPassword = errors.New("邮箱或者密码不正确")
+	ErrIncorrectOldPassword  = errors.New("原密码不正确")
+	ErrPasswordNotSet        = errors.New("还没有设置过密码")
+	ErrPasswordAlreadySet    = errors.New("已经设置过密码")
+	ErrInvalidEmailToken     = errors.New("确认链接无效或者已经过期")
+	ErrHandleReserved        = errors.New("用户名是保留字")
+	ErrHandleTaken           = errors.New("用户名已经被占用")
+	ErrHandleChangeTooOften  = errors.New("修改用户名过于频繁")
+	ErrUserBanned            = errors.New("用户已经被封禁")
 )
 
+const (
+	// bizChangeEmail 修改邮箱的确认链接
+	bizChangeEmail = "change_email"
+	// emailConfirmPath 修改邮箱的确认链接的路径，%s 部分是令牌
+	emailConfirmPath = "/users/email/confirm?token=%s"
+	// handleChangeInterval 两次修改用户名之间至少间隔的时间
+	handleChangeInterval = time.Hour * 24 * 30
+)
+
+// reservedHandles 不允许用作用户名的保留字，避免用户冒充官方或者和路由冲突
+var reservedHandles = map[string]struct{}{
+	"admin":         {},
+	"administrator": {},
+	"root":          {},
+	"system":        {},
+	"webook":        {},
+	"official":      {},
+	"support":       {},
+	"help":          {},
+	"security":      {},
+	"api":           {},
+	"users":         {},
+	"user":          {},
+	"articles":      {},
+	"login":         {},
+	"logout":        {},
+	"signup":        {},
+	"settings":      {},
+	"profile":       {},
+	"static":        {},
+	"null":          {},
+	"undefined":     {},
+}
+
 type UserService interface {
 	Signup(ctx context.Context, u domain.User) error
 	FindOrCreate(ctx context.Context, phone string) (domain.User, error)
+	// FindOrCreateByEmail 和 FindOrCreate 一样，只不过是按照邮箱，用于登录链接
+	FindOrCreateByEmail(ctx context.Context, email string) (domain.User, error)
 	Login(ctx context.Context, email, password string) (domain.User, error)
 	Profile(ctx context.Context, id int64) (domain.User, error)
 	// UpdateNonSensitiveInfo 更新非敏感数据
 	UpdateNonSensitiveInfo(ctx context.Context, user domain.User) error
+
+	// ChangePassword 修改密码，必须提供正确的旧密码
+	// 如果用户之前没有设置过密码（例如通过短信登录注册的用户），返回 ErrPasswordNotSet，要用 SetPassword
+	ChangePassword(ctx context.Context, uid int64, oldPassword, newPassword string) error
+	// SetPassword 没有设置过密码的用户第一次设置密码，已经设置过的返回 ErrPasswordAlreadySet
+	// 只有登录态是不够的，调用者需要先通过 CodeService 校验发到用户手机或者邮箱的验证码
+	SetPassword(ctx context.Context, uid int64, newPassword string) error
+	// BindPhone 绑定或者更换手机号
+	// 调用者需要先通过 CodeService 校验新手机号的验证码
+	BindPhone(ctx context.Context, uid int64, phone string) error
+	// SendChangeEmailLink 往新邮箱发送一个确认链接，用户点击之后才会真的修改邮箱
+	SendChangeEmailLink(ctx context.Context, uid int64, email string) error
+	// ConfirmChangeEmail 根据确认链接中的令牌修改邮箱，返回被修改的用户 ID
+	ConfirmChangeEmail(ctx context.Context, token string) (int64, error)
+
+	// ChangeHandle 设置或者修改用户名，用户名不区分大小写
+	// 调用者需要先校验用户名的格式
+	ChangeHandle(ctx context.Context, uid int64, handle string) error
+	// FindByHandle 根据用户名查找用户
+	FindByHandle(ctx context.Context, handle string) (domain.User, error)
+	// UpdateAvatar 修改头像
+	UpdateAvatar(ctx context.Context, uid int64, avatar string) error
 }
 
 // UserService 结构体，表示用户相关的业务逻辑服务
 type userService struct {
-	repo   repository.UserRepository // 引用repository层的UserRepository对象，用于数据访问
+	repo      repository.UserRepository  // 引用repository层的UserRepository对象，用于数据访问
+	tokenRepo repository.TokenRepository // 存储修改邮箱之类的一次性令牌
+	emailSvc  email.Service
+	// hasher 计算和校验密码的哈希值，支持多种算法
+	hasher password.Hasher
+	// policy 设置密码的时候校验密码强度
+	policy *password.Policy
 	logger logger.Logger
+	// 修改邮箱的确认链接的有效期
+	emailTokenExpiration time.Duration
+	// baseURL 邮件里面的链接的前缀，例如 https://webook.com
+	baseURL string
 }
 
 // NewUserService 实现 UserService 接口
-func NewUserService(repo repository.UserRepository, l logger.Logger) UserService {
+func NewUserService(repo repository.UserRepository, tokenRepo repository.TokenRepository,
+	emailSvc email.Service, hasher password.Hasher, policy *password.Policy,
+	baseURL string, l logger.Logger) UserService {
 	return &userService{
-		repo:   repo,
-		logger: l,
+		repo:                 repo,
+		tokenRepo:            tokenRepo,
+		emailSvc:             emailSvc,
+		hasher:               hasher,
+		policy:               policy,
+		logger:               l,
+		emailTokenExpiration: time.Minute * 30,
+		baseURL:              strings.TrimSuffix(baseURL, "/"),
 	}
 }
 
 // Signup 方法用于用户注册
 // 参数ctx为上下文，用于控制操作的生命周期；u为要注册的用户信息，包含Email和Password字段
-// 该方法首先对用户密码进行加密，然后将加密后的密码保存到数据库
+// 该方法首先校验密码强度并对用户密码进行加密，然后将加密后的密码保存到数据库
+// 密码不符合要求的时候返回 *password.PolicyError
 func (svc *userService) Signup(ctx context.Context, u domain.User) error {
-	// 使用bcrypt生成加密后的密码
-	// bcrypt 会自动为每个密码生成一个随机的盐值，并将其与密码一起存储在最终的哈希值中，不需要手动生成或存储盐值
-	hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	err := svc.policy.Validate(u.Password)
+	if err != nil {
+		return err
+	}
+	// 哈希值里面带有算法和参数，盐值也是随机生成并且和哈希值存在一起的
+	hash, err := svc.hasher.Hash(u.Password)
 	if err != nil {
 		// 如果密码加密失败，返回错误
 		return err
 	}
-	// 将加密后的密码转为字符串并赋值给u.Password
-	u.Password = string(hash)
+	u.Password = hash
 	// 调用repository层的Create方法将加密后的用户信息保存到数据库
-	return svc.repo.Create(ctx, u)
+	// 注册的时候只有邮箱，冲突了只能是邮箱冲突
+	return duplicateAs(svc.repo.Create(ctx, u), ErrUserDuplicateEmail)
 }
 
 // FindOrCreate 如果手机号不存在，那么会初始化一个用户
@@ -60,6 +155,9 @@ func (svc *userService) FindOrCreate(ctx context.Context, phone string) (domain.
 	// 大部分人会命中这个分支
 	u, err := svc.repo.FindByPhone(ctx, phone)       // 从数据库中查找用户
 	if !errors.Is(err, repository.ErrUserNotFound) { // 如果用户已经存在，则直接返回
+		if err == nil && u.Banned {
+			return domain.User{}, ErrUserBanned
+		}
 		return u, err
 	}
 	// 如果找不到用户，则执行用户注册操作
@@ -74,6 +172,24 @@ func (svc *userService) FindOrCreate(ctx context.Context, phone string) (domain.
 	return svc.repo.FindByPhone(ctx, phone) // 返回用户
 }
 
+func (svc *userService) FindOrCreateByEmail(ctx context.Context, email string) (domain.User, error) {
+	u, err := svc.repo.FindByEmail(ctx, email)
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		if err == nil && u.Banned {
+			return domain.User{}, ErrUserBanned
+		}
+		return u, err
+	}
+	// 通过登录链接注册的用户没有密码，之后可以继续用登录链接登录
+	err = svc.repo.Create(ctx, domain.User{
+		Email: email,
+	})
+	if err != nil && !errors.Is(err, repository.ErrUserDuplicate) {
+		return domain.User{}, err
+	}
+	return svc.repo.FindByEmail(ctx, email)
+}
+
 func (svc *userService) Login(ctx context.Context, email, password string) (domain.User, error) {
 	// 查找数据库中是否存在该邮箱的用户
 	u, err := svc.repo.FindByEmail(ctx, email)
@@ -81,17 +197,41 @@ func (svc *userService) Login(ctx context.Context, email, password string) (doma
 		// 如果用户没有找到，返回一个“用户或密码错误”的错误
 		return domain.User{}, ErrInvalidUserOrPassword
 	}
-
-	// 使用 bcrypt 的 CompareHashAndPassword 方法来验证用户输入的密码
-	// 将数据库中的密码哈希和用户输入的密码进行比较
-	err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
 	if err != nil {
+		return domain.User{}, err
+	}
+
+	// 根据哈希值的前缀选择对应的算法，将数据库中的密码哈希和用户输入的密码进行比较
+	// 没有设置过密码的用户，哈希值是空的，也会校验失败
+	ok, err := svc.hasher.Verify(password, u.Password)
+	if err != nil || !ok {
 		// 如果密码不匹配，返回一个“用户或密码错误”的错误
 		return domain.User{}, ErrInvalidUserOrPassword
 	}
+	if u.Banned {
+		return domain.User{}, ErrUserBanned
+	}
+	// 只有在登录的时候才能拿到明文密码，所以在这里把旧的算法或者参数升级掉
+	if svc.hasher.NeedsRehash(u.Password) {
+		svc.rehash(ctx, u.Id, password)
+	}
 
 	// 密码验证通过，返回用户信息
-	return u, err
+	return u, nil
+}
+
+// rehash 重新计算密码的哈希值，失败了也不影响登录，下一次登录的时候会再试
+func (svc *userService) rehash(ctx context.Context, uid int64, pwd string) {
+	hash, err := svc.hasher.Hash(pwd)
+	if err == nil {
+		err = svc.repo.Update(ctx, domain.User{
+			Id:       uid,
+			Password: hash,
+		})
+	}
+	if err != nil {
+		svc.logger.Error("升级密码的哈希值失败", logger.Int64("uid", uid), logger.Error(err))
+	}
 }
 
 func (svc *userService) UpdateNonSensitiveInfo(ctx context.Context, user domain.User) error {
@@ -100,6 +240,9 @@ func (svc *userService) UpdateNonSensitiveInfo(ctx context.Context, user domain.
 	user.Email = ""
 	user.Phone = ""
 	user.Password = ""
+	// 用户名有单独的修改频率限制
+	user.Handle = ""
+	user.HandleUtime = time.Time{}
 	return svc.repo.Update(ctx, user)
 }
 
@@ -107,3 +250,148 @@ func (svc *userService) Profile(ctx context.Context,
 	id int64) (domain.User, error) {
 	return svc.repo.FindById(ctx, id)
 }
+
+func (svc *userService) ChangePassword(ctx context.Context, uid int64, oldPassword, newPassword string) error {
+	u, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// 没有设置过密码的，只有登录态不够，要走 SetPassword 校验验证码
+	if u.Password == "" {
+		return ErrPasswordNotSet
+	}
+	ok, err := svc.hasher.Verify(oldPassword, u.Password)
+	if err != nil || !ok {
+		return ErrIncorrectOldPassword
+	}
+	return svc.updatePassword(ctx, uid, newPassword)
+}
+
+func (svc *userService) SetPassword(ctx context.Context, uid int64, newPassword string) error {
+	u, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if u.Password != "" {
+		return ErrPasswordAlreadySet
+	}
+	return svc.updatePassword(ctx, uid, newPassword)
+}
+
+func (svc *userService) updatePassword(ctx context.Context, uid int64, newPassword string) error {
+	err := svc.policy.Validate(newPassword)
+	if err != nil {
+		return err
+	}
+	hash, err := svc.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	// 依赖于 repository 中更新会忽略 0 值，所以这里只会更新密码
+	return svc.repo.Update(ctx, domain.User{
+		Id:       uid,
+		Password: hash,
+	})
+}
+
+func (svc *userService) BindPhone(ctx context.Context, uid int64, phone string) error {
+	// 只更新了手机号，冲突了只能是手机号冲突
+	err := svc.repo.Update(ctx, domain.User{
+		Id:    uid,
+		Phone: phone,
+	})
+	return duplicateAs(err, ErrUserDuplicatePhone)
+}
+
+func (svc *userService) SendChangeEmailLink(ctx context.Context, uid int64, email string) error {
+	// 提前检查一下，避免用户点了链接之后才发现邮箱已经被人用了
+	_, err := svc.repo.FindByEmail(ctx, email)
+	switch {
+	case err == nil:
+		return ErrUserDuplicateEmail
+	case !errors.Is(err, repository.ErrUserNotFound):
+		return err
+	}
+	token := uuid.New().String()
+	// 令牌里面记录了是谁要把邮箱改成什么
+	err = svc.tokenRepo.Store(ctx, bizChangeEmail, token,
+		fmt.Sprintf("%d:%s", uid, email), svc.emailTokenExpiration)
+	if err != nil {
+		return err
+	}
+	link := svc.baseURL + fmt.Sprintf(emailConfirmPath, token)
+	content := fmt.Sprintf(`<p>你正在修改 webook 账号绑定的邮箱，请在 %d 分钟内点击下面的链接完成修改：</p>
+<p><a href="%s">%s</a></p><p>如果不是你本人操作，请忽略这封邮件。</p>`,
+		int(svc.emailTokenExpiration.Minutes()), link, link)
+	return svc.emailSvc.Send(ctx, "确认修改 webook 邮箱", content, email)
+}
+
+func (svc *userService) ConfirmChangeEmail(ctx context.Context, token string) (int64, error) {
+	val, err := svc.tokenRepo.Consume(ctx, bizChangeEmail, token)
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		return 0, ErrInvalidEmailToken
+	}
+	if err != nil {
+		return 0, err
+	}
+	segs := strings.SplitN(val, ":", 2)
+	if len(segs) != 2 {
+		return 0, ErrInvalidEmailToken
+	}
+	uid, err := strconv.ParseInt(segs[0], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidEmailToken
+	}
+	err = svc.repo.Update(ctx, domain.User{
+		Id:    uid,
+		Email: segs[1],
+	})
+	// 点链接之前邮箱可能已经被别人绑定了
+	return uid, duplicateAs(err, ErrUserDuplicateEmail)
+}
+
+func (svc *userService) ChangeHandle(ctx context.Context, uid int64, handle string) error {
+	handle = strings.ToLower(handle)
+	if _, ok := reservedHandles[handle]; ok {
+		return ErrHandleReserved
+	}
+	u, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if u.Handle == handle {
+		return nil
+	}
+	now := time.Now()
+	// 第一次设置用户名不受限制
+	if u.Handle != "" && now.Sub(u.HandleUtime) < handleChangeInterval {
+		return ErrHandleChangeTooOften
+	}
+	err = svc.repo.Update(ctx, domain.User{
+		Id:          uid,
+		Handle:      handle,
+		HandleUtime: now,
+	})
+	// 用户名冲突的时候，repository 会返回 ErrUserDuplicate
+	return duplicateAs(err, ErrHandleTaken)
+}
+
+// duplicateAs 只更新了一个唯一字段的时候，把唯一索引冲突统一转成这个字段对应的错误，
+// 不依赖 repository 能不能识别出具体是哪个索引
+func duplicateAs(err error, target error) error {
+	if errors.Is(err, repository.ErrUserDuplicate) {
+		return target
+	}
+	return err
+}
+
+func (svc *userService) FindByHandle(ctx context.Context, handle string) (domain.User, error) {
+	return svc.repo.FindByHandle(ctx, strings.ToLower(handle))
+}
+
+func (svc *userService) UpdateAvatar(ctx context.Context, uid int64, avatar string) error {
+	return svc.repo.Update(ctx, domain.User{
+		Id:     uid,
+		Avatar: avatar,
+	})
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -11,11 +12,28 @@ import (
 	"webook/internal/domain"
 	"webook/internal/repository"
 	repomocks "webook/internal/repository/mocks"
+	"webook/internal/service/password"
 )
 
+// newTestUserService 新密码用 bcrypt，和测试用例里面的哈希值的 cost 一致
+func newTestUserService(t *testing.T, repo repository.UserRepository) UserService {
+	hasher := password.NewMultiHasher(password.NewBcryptHasher(bcrypt.DefaultCost),
+		password.NewArgon2idHasher(password.DefaultArgon2idParams))
+	policy, err := password.NewPolicy(password.PolicyConfig{
+		RequireLetter: true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	})
+	require.NoError(t, err)
+	return NewUserService(repo, nil, nil, hasher, policy, "http://localhost:8080", nil)
+}
+
 func TestUserService_Login(t *testing.T) {
 	//固定使用一个时间
 	ctime := time.Now()
+	// cost 和现在的配置不一样的老密码
+	oldHash, err := bcrypt.GenerateFromPassword([]byte("hello#world123"), bcrypt.MinCost)
+	require.NoError(t, err)
 	testCases := []struct {
 		name string
 
@@ -101,13 +119,60 @@ func TestUserService_Login(t *testing.T) {
 			// 返回密码错误
 			wantErr: ErrInvalidUserOrPassword,
 		},
+		{
+			name: "登录成功并且升级哈希值",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().
+					FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{
+						Id:       123,
+						Email:    "123@qq.com",
+						Password: string(oldHash),
+					}, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, u domain.User) error {
+						// 只更新密码，并且使用新的 cost
+						assert.Equal(t, int64(123), u.Id)
+						assert.Empty(t, u.Email)
+						cost, err := bcrypt.Cost([]byte(u.Password))
+						assert.NoError(t, err)
+						assert.Equal(t, bcrypt.DefaultCost, cost)
+						assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("hello#world123")))
+						return nil
+					})
+				return repo
+			},
+			ctx:      context.Background(),
+			email:    "123@qq.com",
+			password: "hello#world123",
+			wantUser: domain.User{
+				Id:       123,
+				Email:    "123@qq.com",
+				Password: string(oldHash),
+			},
+		},
+		{
+			name: "没有设置过密码",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().
+					FindByEmail(gomock.Any(), "123@qq.com").
+					Return(domain.User{Id: 123, Email: "123@qq.com"}, nil)
+				return repo
+			},
+			ctx:      context.Background(),
+			email:    "123@qq.com",
+			password: "hello#world123",
+			wantErr:  ErrInvalidUserOrPassword,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			repo := tc.mock(ctrl)
-			svc := NewUserService(repo, nil)
+			svc := newTestUserService(t, repo)
 			user, err := svc.Login(tc.ctx, tc.email, tc.password)
 			assert.Equal(t, tc.wantErr, err)
 			assert.Equal(t, tc.wantUser, user)
@@ -115,6 +180,285 @@ func TestUserService_Login(t *testing.T) {
 	}
 }
 
+func TestUserService_ChangePassword(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		uid         int64
+		oldPassword string
+		newPassword string
+
+		wantErr error
+	}{
+		{
+			name: "修改成功",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{
+						Id:       123,
+						Password: "$2a$10$s51GBcU20dkNUVTpUAQqpe6febjXkRYvhEwa5OkN5rU6rw2KTbNUi",
+					}, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, u domain.User) error {
+						// 只更新密码，而且存的是加密后的密码
+						assert.Equal(t, int64(123), u.Id)
+						assert.Empty(t, u.Email)
+						assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("new#world123")))
+						return nil
+					})
+				return repo
+			},
+			uid:         123,
+			oldPassword: "hello#world123",
+			newPassword: "new#world123",
+		},
+		{
+			name: "没有设置过密码，只有登录态不能设置",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123, Phone: "15261890000"}, nil)
+				return repo
+			},
+			uid:         123,
+			newPassword: "new#world123",
+			wantErr:     ErrPasswordNotSet,
+		},
+		{
+			name: "原密码错误",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{
+						Id:       123,
+						Password: "$2a$10$s51GBcU20dkNUVTpUAQqpe6febjXkRYvhEwa5OkN5rU6rw2KTbNUi",
+					}, nil)
+				return repo
+			},
+			uid:         123,
+			oldPassword: "hello#world",
+			newPassword: "new#world123",
+			wantErr:     ErrIncorrectOldPassword,
+		},
+		{
+			name: "新密码太常见",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{
+						Id:       123,
+						Password: "$2a$10$s51GBcU20dkNUVTpUAQqpe6febjXkRYvhEwa5OkN5rU6rw2KTbNUi",
+					}, nil)
+				return repo
+			},
+			uid:         123,
+			oldPassword: "hello#world123",
+			newPassword: "P@ssw0rd",
+			wantErr:     password.ErrCommonPassword,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := newTestUserService(t, tc.mock(ctrl))
+			err := svc.ChangePassword(context.Background(), tc.uid, tc.oldPassword, tc.newPassword)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestUserService_SetPassword(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		uid         int64
+		newPassword string
+
+		wantErr error
+	}{
+		{
+			name: "设置成功",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123, Phone: "15261890000"}, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, u domain.User) error {
+						assert.Equal(t, int64(123), u.Id)
+						assert.Empty(t, u.Phone)
+						assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("new#world123")))
+						return nil
+					})
+				return repo
+			},
+			uid:         123,
+			newPassword: "new#world123",
+		},
+		{
+			name: "已经设置过密码",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{
+						Id:       123,
+						Password: "$2a$10$s51GBcU20dkNUVTpUAQqpe6febjXkRYvhEwa5OkN5rU6rw2KTbNUi",
+					}, nil)
+				return repo
+			},
+			uid:         123,
+			newPassword: "new#world123",
+			wantErr:     ErrPasswordAlreadySet,
+		},
+		{
+			name: "新密码太常见",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123, Phone: "15261890000"}, nil)
+				return repo
+			},
+			uid:         123,
+			newPassword: "P@ssw0rd",
+			wantErr:     password.ErrCommonPassword,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := newTestUserService(t, tc.mock(ctrl))
+			err := svc.SetPassword(context.Background(), tc.uid, tc.newPassword)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestUserService_ChangeHandle(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		uid    int64
+		handle string
+
+		wantErr error
+	}{
+		{
+			name: "第一次设置",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{Id: 123}, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, u domain.User) error {
+						// 统一存小写
+						assert.Equal(t, "tom_123", u.Handle)
+						assert.False(t, u.HandleUtime.IsZero())
+						return nil
+					})
+				return repo
+			},
+			uid:    123,
+			handle: "Tom_123",
+		},
+		{
+			name: "保留字",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				return repomocks.NewMockUserRepository(ctrl)
+			},
+			uid:     123,
+			handle:  "Admin",
+			wantErr: ErrHandleReserved,
+		},
+		{
+			name: "修改过于频繁",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{
+						Id:          123,
+						Handle:      "tom",
+						HandleUtime: time.Now().Add(-time.Hour * 24),
+					}, nil)
+				return repo
+			},
+			uid:     123,
+			handle:  "jerry",
+			wantErr: ErrHandleChangeTooOften,
+		},
+		{
+			name: "用户名已经被占用",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123)).
+					Return(domain.User{
+						Id:          123,
+						Handle:      "tom",
+						HandleUtime: time.Now().Add(-time.Hour * 24 * 31),
+					}, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					Return(repository.ErrUserDuplicate)
+				return repo
+			},
+			uid:     123,
+			handle:  "jerry",
+			wantErr: ErrHandleTaken,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := newTestUserService(t, tc.mock(ctrl))
+			err := svc.ChangeHandle(context.Background(), tc.uid, tc.handle)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestUserService_BindPhone(t *testing.T) {
+	testCases := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{
+			name: "绑定成功",
+		},
+		{
+			name:    "手机号已经被使用",
+			repoErr: repository.ErrUserDuplicatePhone,
+			wantErr: ErrUserDuplicatePhone,
+		},
+		{
+			// repository 没认出来是哪个索引，只更新了手机号，所以还是手机号冲突
+			name:    "未知的唯一索引冲突",
+			repoErr: repository.ErrUserDuplicate,
+			wantErr: ErrUserDuplicatePhone,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomocks.NewMockUserRepository(ctrl)
+			repo.EXPECT().Update(gomock.Any(), domain.User{Id: 123, Phone: "+8613812345678"}).
+				Return(tc.repoErr)
+			svc := newTestUserService(t, repo)
+			err := svc.BindPhone(context.Background(), 123, "+8613812345678")
+			assert.Equal(t, tc.wantErr, err)
+			assert.False(t, errors.Is(err, ErrUserDuplicateEmail))
+		})
+	}
+}
+
 func TestPasswordEncrypt(t *testing.T) {
 	pwd := []byte("123456#123456#11adasfasfsfsf2")
 	// 加密
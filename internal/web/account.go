@@ -0,0 +1,119 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"webook/internal/domain"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// AccountHandler 注销账号和导出个人数据
+type AccountHandler struct {
+	svc service.AccountService
+	l   logger.Logger
+}
+
+func NewAccountHandler(svc service.AccountService, l logger.Logger) *AccountHandler {
+	return &AccountHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *AccountHandler) RegisterRoutes(s *gin.Engine) {
+	g := s.Group("/users")
+	g.POST("/delete", ginx.WrapClaims(h.RequestDeletion))
+	g.POST("/delete/cancel", ginx.WrapClaims(h.CancelDeletion))
+	g.POST("/export", ginx.WrapClaims(h.RequestExport))
+	g.GET("/export/:id", ginx.WrapClaims(h.ExportDetail))
+	g.GET("/export/:id/download", h.DownloadExport)
+}
+
+// RequestDeletion 申请注销，冷静期之后才会真的注销
+func (h *AccountHandler) RequestDeletion(ctx *gin.Context, uc ginx.UserClaims) (Result, error) {
+	d, err := h.svc.RequestDeletion(ctx, uc.Id)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: AccountDeletionVo{
+		ExecuteAt: d.ExecuteAt.UnixMilli(),
+	}}, nil
+}
+
+func (h *AccountHandler) CancelDeletion(ctx *gin.Context, uc ginx.UserClaims) (Result, error) {
+	err := h.svc.CancelDeletion(ctx, uc.Id)
+	switch {
+	case err == nil:
+		return Result{Msg: "OK"}, nil
+	case errors.Is(err, service.ErrAccountDeletionNotFound):
+		return Result{Code: 4, Msg: "没有可以撤销的注销申请"}, nil
+	default:
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+// RequestExport 申请导出个人数据，返回导出任务的 ID，前端用这个 ID 轮询导出的进度
+func (h *AccountHandler) RequestExport(ctx *gin.Context, uc ginx.UserClaims) (Result, error) {
+	id, err := h.svc.RequestExport(ctx, uc.Id)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: id}, nil
+}
+
+func (h *AccountHandler) ExportDetail(ctx *gin.Context, uc ginx.UserClaims) (Result, error) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return Result{Code: 4, Msg: "参数错误"}, nil
+	}
+	e, err := h.svc.GetExport(ctx, uc.Id, id)
+	switch {
+	case err == nil:
+		return Result{Data: DataExportVo{
+			Id:     e.Id,
+			Status: e.Status.ToUint8(),
+			Ctime:  e.Ctime.UnixMilli(),
+			Utime:  e.Utime.UnixMilli(),
+		}}, nil
+	case errors.Is(err, service.ErrDataExportNotFound):
+		return Result{Code: 4, Msg: "导出任务不存在"}, nil
+	default:
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+// DownloadExport 下载导出的压缩包，成功的时候直接返回文件，而不是 JSON
+func (h *AccountHandler) DownloadExport(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "参数错误"})
+		return
+	}
+	uc, ok := ctx.MustGet("user").(ijwt.UserClaims)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("获得用户会话信息失败")
+		return
+	}
+	e, err := h.svc.GetExport(ctx, uc.Id, id)
+	switch {
+	case errors.Is(err, service.ErrDataExportNotFound):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "导出任务不存在"})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		h.l.Error("查询导出任务失败", logger.Int64("id", id), logger.Error(err))
+		return
+	}
+	if e.Status != domain.DataExportStatusDone {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "导出还没有完成"})
+		return
+	}
+	ctx.FileAttachment(e.Path, fmt.Sprintf("webook_export_%d.zip", e.Id))
+}
@@ -0,0 +1,14 @@
+package web
+
+type AccountDeletionVo struct {
+	// 冷静期结束的时间，毫秒数，在这之前都可以撤销
+	ExecuteAt int64 `json:"executeAt"`
+}
+
+type DataExportVo struct {
+	Id int64 `json:"id"`
+	// 1 导出中，2 可以下载，3 导出失败
+	Status uint8 `json:"status"`
+	Ctime  int64 `json:"ctime"`
+	Utime  int64 `json:"utime"`
+}
@@ -0,0 +1,151 @@
+package web
+
+import (
+	"errors"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/web/middleware"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// AdminHandler 管理后台的接口，每一组接口都要求对应的权限
+type AdminHandler struct {
+	svc     service.AdminService
+	rbacSvc service.RBACService
+	l       logger.Logger
+}
+
+func NewAdminHandler(svc service.AdminService, rbacSvc service.RBACService, l logger.Logger) *AdminHandler {
+	return &AdminHandler{
+		svc:     svc,
+		rbacSvc: rbacSvc,
+		l:       l,
+	}
+}
+
+func (h *AdminHandler) RegisterRoutes(s *gin.Engine) {
+	g := s.Group("/admin")
+
+	users := g.Group("/users",
+		middleware.NewRBACMiddlewareBuilder(domain.PermissionUserRead).Build())
+	users.GET("/search", ginx.WrapClaimsAndReq[AdminSearchUserReq](h.SearchUsers))
+
+	ban := g.Group("/users",
+		middleware.NewRBACMiddlewareBuilder(domain.PermissionUserBan).Build())
+	ban.POST("/ban", ginx.WrapClaimsAndReq[AdminBanReq](h.BanUser))
+	ban.POST("/unban", ginx.WrapClaimsAndReq[AdminBanReq](h.UnbanUser))
+
+	roles := g.Group("/roles",
+		middleware.NewRBACMiddlewareBuilder(domain.PermissionRoleManage).Build())
+	roles.POST("/assign", ginx.WrapClaimsAndReq[AdminRoleReq](h.AssignRole))
+	roles.POST("/revoke", ginx.WrapClaimsAndReq[AdminRoleReq](h.RevokeRole))
+
+	arts := g.Group("/articles",
+		middleware.NewRBACMiddlewareBuilder(domain.PermissionArticleUnpublish).Build())
+	arts.POST("/unpublish", ginx.WrapClaimsAndReq[AdminUnpublishReq](h.UnpublishArticle))
+
+	audit := g.Group("/audit_logs",
+		middleware.NewRBACMiddlewareBuilder(domain.PermissionAuditRead).Build())
+	audit.GET("", ginx.WrapClaimsAndReq[AdminAuditLogReq](h.AuditLogs))
+}
+
+func (h *AdminHandler) SearchUsers(ctx *gin.Context, req AdminSearchUserReq, uc ginx.UserClaims) (Result, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	us, err := h.svc.SearchUsers(ctx, req.Keyword, req.Offset, req.Limit)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: slice.Map(us, func(idx int, src domain.User) AdminUserVo {
+		return AdminUserVo{
+			Id:       src.Id,
+			Email:    src.Email,
+			Phone:    src.Phone,
+			Handle:   src.Handle,
+			Nickname: src.Nickname,
+			Banned:   src.Banned,
+			Ctime:    src.Ctime.Format(time.DateTime),
+		}
+	})}, nil
+}
+
+func (h *AdminHandler) BanUser(ctx *gin.Context, req AdminBanReq, uc ginx.UserClaims) (Result, error) {
+	if req.Uid == uc.Id {
+		return Result{Code: 4, Msg: "不能封禁自己"}, nil
+	}
+	err := h.svc.BanUser(ctx, uc.Id, req.Uid, req.Reason)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return Result{Code: 4, Msg: "用户不存在"}, nil
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+func (h *AdminHandler) UnbanUser(ctx *gin.Context, req AdminBanReq, uc ginx.UserClaims) (Result, error) {
+	err := h.svc.UnbanUser(ctx, uc.Id, req.Uid, req.Reason)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+func (h *AdminHandler) AssignRole(ctx *gin.Context, req AdminRoleReq, uc ginx.UserClaims) (Result, error) {
+	err := h.rbacSvc.AssignRole(ctx, uc.Id, req.Uid, domain.Role(req.Role))
+	if errors.Is(err, service.ErrInvalidRole) {
+		return Result{Code: 4, Msg: "未知的角色"}, nil
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+func (h *AdminHandler) RevokeRole(ctx *gin.Context, req AdminRoleReq, uc ginx.UserClaims) (Result, error) {
+	err := h.rbacSvc.RevokeRole(ctx, uc.Id, req.Uid, domain.Role(req.Role))
+	if errors.Is(err, service.ErrInvalidRole) {
+		return Result{Code: 4, Msg: "未知的角色"}, nil
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+func (h *AdminHandler) UnpublishArticle(ctx *gin.Context, req AdminUnpublishReq, uc ginx.UserClaims) (Result, error) {
+	err := h.svc.UnpublishArticle(ctx, uc.Id, req.Aid, req.Reason)
+	if errors.Is(err, service.ErrArticleNotPublished) {
+		return Result{Code: 4, Msg: "文章不存在或者已经下线"}, err
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+func (h *AdminHandler) AuditLogs(ctx *gin.Context, req AdminAuditLogReq, uc ginx.UserClaims) (Result, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	logs, err := h.svc.ListAuditLogs(ctx, req.Offset, req.Limit)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: slice.Map(logs, func(idx int, src domain.AuditLog) AuditLogVo {
+		return AuditLogVo{
+			Id:         src.Id,
+			OperatorId: src.OperatorId,
+			Action:     string(src.Action),
+			TargetId:   src.TargetId,
+			Detail:     src.Detail,
+			Ctime:      src.Ctime.Format(time.DateTime),
+		}
+	})}, nil
+}
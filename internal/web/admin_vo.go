@@ -0,0 +1,47 @@
+package web
+
+type AdminSearchUserReq struct {
+	Keyword string `form:"keyword"`
+	Offset  int    `form:"offset"`
+	Limit   int    `form:"limit"`
+}
+
+type AdminBanReq struct {
+	Uid    int64  `json:"uid"`
+	Reason string `json:"reason"`
+}
+
+type AdminRoleReq struct {
+	Uid  int64  `json:"uid"`
+	Role string `json:"role"`
+}
+
+type AdminUnpublishReq struct {
+	Aid    int64  `json:"aid"`
+	Reason string `json:"reason"`
+}
+
+type AdminAuditLogReq struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+// AdminUserVo 管理后台看到的用户信息，比公开的个人主页多了联系方式和封禁状态
+type AdminUserVo struct {
+	Id       int64  `json:"id"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Handle   string `json:"handle"`
+	Nickname string `json:"nickname"`
+	Banned   bool   `json:"banned"`
+	Ctime    string `json:"ctime"`
+}
+
+type AuditLogVo struct {
+	Id         int64  `json:"id"`
+	OperatorId int64  `json:"operatorId"`
+	Action     string `json:"action"`
+	TargetId   int64  `json:"targetId"`
+	Detail     string `json:"detail"`
+	Ctime      string `json:"ctime"`
+}
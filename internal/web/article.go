@@ -1,6 +1,7 @@
 package web
 
 import (
+	"errors"
 	"fmt"
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/gin-gonic/gin"
@@ -8,27 +9,34 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+	followv1 "webook/api/proto/gen/follow/v1"
 	intrv1 "webook/api/proto/gen/intr/v1"
 	"webook/internal/domain"
 	"webook/internal/service"
+	"webook/internal/web/client"
 	"webook/internal/web/jwt"
 	"webook/pkg/ginx"
 	"webook/pkg/logger"
 )
 
 type ArticleHandler struct {
-	svc     service.ArticleService
-	intrSvc intrv1.InteractiveServiceClient
-	biz     string
-	l       logger.Logger
+	svc        service.ArticleService
+	intrSvc    intrv1.InteractiveServiceClient
+	followSvc  followv1.FollowServiceClient
+	rankingSvc service.RankingService
+	biz        string
+	l          logger.Logger
 }
 
-func NewArticleHandler(svc service.ArticleService, intrSvc intrv1.InteractiveServiceClient, l logger.Logger) *ArticleHandler {
+func NewArticleHandler(svc service.ArticleService, intrSvc intrv1.InteractiveServiceClient,
+	followSvc followv1.FollowServiceClient, rankingSvc service.RankingService, l logger.Logger) *ArticleHandler {
 	return &ArticleHandler{
-		svc:     svc,
-		l:       l,
-		biz:     "article",
-		intrSvc: intrSvc,
+		svc:        svc,
+		l:          l,
+		biz:        "article",
+		intrSvc:    intrSvc,
+		followSvc:  followSvc,
+		rankingSvc: rankingSvc,
 	}
 }
 
@@ -43,18 +51,23 @@ func (hdl *ArticleHandler) RegisterRoutes(s *gin.Engine) {
 
 	pub := g.Group("/pub")
 	//pub.GET("/pub", a.PubList)
+	pub.GET("/ranking", ginx.WrapClaims(hdl.Ranking))
 	pub.GET("/:id", ginx.WrapClaims(hdl.PubDetail))
 	pub.POST("/like", ginx.WrapClaimsAndReq[LikeReq](hdl.Like))
 	pub.POST("/collect", ginx.WrapClaimsAndReq[CollectReq](hdl.Collect))
 }
 
 func (hdl *ArticleHandler) Collect(ctx *gin.Context, req CollectReq, uc ginx.UserClaims) (Result, error) {
+	// 拉黑的检查在 intrSvc 里面，见 client.BlockCheckInteractiveClient
 	_, err := hdl.intrSvc.Collect(ctx, &intrv1.CollectRequest{
 		Biz:   hdl.biz,
 		BizId: req.Id,
 		Cid:   req.Cid,
 		Uid:   uc.Id,
 	})
+	if errors.Is(err, client.ErrBlocked) {
+		return Result{Code: 4, Msg: "对方已将你拉黑"}, nil
+	}
 	if err != nil {
 		return Result{
 			Code: 5,
@@ -80,6 +93,9 @@ func (hdl *ArticleHandler) Like(ctx *gin.Context, req LikeReq, uc ginx.UserClaim
 		})
 	}
 
+	if errors.Is(err, client.ErrBlocked) {
+		return Result{Code: 4, Msg: "对方已将你拉黑"}, nil
+	}
 	if err != nil {
 		return Result{
 			Code: 5,
@@ -89,6 +105,42 @@ func (hdl *ArticleHandler) Like(ctx *gin.Context, req LikeReq, uc ginx.UserClaim
 	return Result{Msg: "OK"}, nil
 }
 
+// Ranking 热榜，会过滤掉当前用户屏蔽了的作者
+func (hdl *ArticleHandler) Ranking(ctx *gin.Context, uc ginx.UserClaims) (Result, error) {
+	arts, err := hdl.rankingSvc.GetTopN(ctx)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, fmt.Errorf("获取热榜失败 %w", err)
+	}
+	resp, err := hdl.followSvc.GetBlockList(ctx, &followv1.GetBlockListRequest{
+		Uid:  uc.Id,
+		Type: followv1.BlockType_BLOCK_TYPE_MUTE,
+	})
+	if err != nil {
+		// 屏蔽列表查不到的时候，就不过滤了
+		hdl.l.Error("查询屏蔽列表失败", logger.Int64("uid", uc.Id), logger.Error(err))
+	}
+	muted := make(map[int64]struct{}, len(resp.GetBlockRelations()))
+	for _, br := range resp.GetBlockRelations() {
+		muted[br.GetTarget()] = struct{}{}
+	}
+	// 热榜可能来自本地缓存，不能原地修改
+	res := make([]ArticleVo, 0, len(arts))
+	for _, art := range arts {
+		if _, ok := muted[art.Author.Id]; ok {
+			continue
+		}
+		res = append(res, ArticleVo{
+			Id:       art.Id,
+			Title:    art.Title,
+			Abstract: art.Abstract(),
+			Author:   art.Author.Name,
+			Ctime:    art.Ctime.Format(time.DateTime),
+			Utime:    art.Utime.Format(time.DateTime),
+		})
+	}
+	return Result{Data: res}, nil
+}
+
 func (hdl *ArticleHandler) PubDetail(ctx *gin.Context, uc ginx.UserClaims) (Result, error) {
 	idstr := ctx.Param("id")
 	id, err := strconv.ParseInt(idstr, 10, 64)
@@ -123,7 +175,12 @@ func (hdl *ArticleHandler) PubDetail(ctx *gin.Context, uc ginx.UserClaims) (Resu
 	})
 
 	err = eg.Wait()
-
+	if errors.Is(err, service.ErrArticleNotPublished) {
+		return Result{
+			Code: 4,
+			Msg:  "文章不存在",
+		}, fmt.Errorf("文章 %d 不存在或者已经下线 %w", id, err)
+	}
 	if err != nil {
 		return Result{
 			Code: 5,
@@ -140,6 +197,13 @@ func (hdl *ArticleHandler) PubDetail(ctx *gin.Context, uc ginx.UserClaims) (Resu
 	//		hdl.l.Error("增加文章阅读数失败", logger.Error(err))
 	//	}
 	//}()
+	// 兜底，撤回或者下线的文章不能返回给读者
+	if art.Status != domain.ArticleStatusPublished {
+		return Result{
+			Code: 4,
+			Msg:  "文章不存在",
+		}, fmt.Errorf("文章 %d 不是发表状态 %d", id, art.Status)
+	}
 	intr := intrResp.Intr
 	return Result{
 		Data: ArticleVo{
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"encoding/base64"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"webook/internal/service"
+	"webook/pkg/ginx"
+)
+
+// CaptchaHandler 图形验证码
+// 前端先获取验证码，用户答对了之后拿到凭证，再带着凭证调用需要图形验证码的接口
+type CaptchaHandler struct {
+	svc service.CaptchaService
+}
+
+func NewCaptchaHandler(svc service.CaptchaService) *CaptchaHandler {
+	return &CaptchaHandler{svc: svc}
+}
+
+func (h *CaptchaHandler) RegisterRoutes(s *gin.Engine) {
+	g := s.Group("/captcha")
+	g.POST("/generate", ginx.Wrap(h.Generate))
+	g.POST("/verify", ginx.WrapReq[VerifyCaptchaReq](h.Verify))
+}
+
+func (h *CaptchaHandler) Generate(ctx *gin.Context) (Result, error) {
+	c, err := h.svc.Generate(ctx)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: CaptchaVo{
+		Id:    c.Id,
+		Image: "data:image/png;base64," + base64.StdEncoding.EncodeToString(c.Image),
+	}}, nil
+}
+
+func (h *CaptchaHandler) Verify(ctx *gin.Context, req VerifyCaptchaReq) (Result, error) {
+	if req.Id == "" || req.Answer == "" {
+		return Result{Code: 4, Msg: "请输入图形验证码"}, nil
+	}
+	token, err := h.svc.Verify(ctx, req.Id, req.Answer)
+	switch {
+	case err == nil:
+		return Result{Data: CaptchaPassVo{Token: token}}, nil
+	case errors.Is(err, service.ErrCaptchaIncorrect):
+		// 验证码只能校验一次，答错了就要重新获取
+		return Result{Code: 4, Msg: "图形验证码错误，请重新获取"}, nil
+	default:
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
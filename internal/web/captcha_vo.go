@@ -0,0 +1,17 @@
+package web
+
+type VerifyCaptchaReq struct {
+	Id     string `json:"id"`
+	Answer string `json:"answer"`
+}
+
+type CaptchaVo struct {
+	Id string `json:"id"`
+	// Image data URL 格式的 PNG 图片，前端直接放到 img 的 src 里面
+	Image string `json:"image"`
+}
+
+type CaptchaPassVo struct {
+	// Token 通过图形验证码的凭证，只能使用一次
+	Token string `json:"token"`
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	followv1 "webook/api/proto/gen/follow/v1"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	"webook/internal/domain"
+)
+
+// ErrBlocked 作者已经把用户拉黑了，不能点赞、收藏
+var ErrBlocked = errors.New("对方已将你拉黑")
+
+// bizArticle 只有文章有作者，别的业务不检查
+const bizArticle = "article"
+
+// AuthorFinder 查询已经发表的文章的作者
+type AuthorFinder interface {
+	GetPublishedAuthor(ctx context.Context, id int64) (domain.Author, error)
+}
+
+// BlockCheckInteractiveClient 点赞、收藏之前检查作者有没有拉黑用户
+// 包在 InteractiveClient 外面，所以不管走本地还是走 gRPC，所有的调用者都会检查
+// 取消点赞不需要检查
+type BlockCheckInteractiveClient struct {
+	intrv1.InteractiveServiceClient
+	authors   AuthorFinder
+	followSvc followv1.FollowServiceClient
+}
+
+func NewBlockCheckInteractiveClient(intrSvc intrv1.InteractiveServiceClient,
+	authors AuthorFinder, followSvc followv1.FollowServiceClient) *BlockCheckInteractiveClient {
+	return &BlockCheckInteractiveClient{
+		InteractiveServiceClient: intrSvc,
+		authors:                  authors,
+		followSvc:                followSvc,
+	}
+}
+
+func (b *BlockCheckInteractiveClient) Like(ctx context.Context, in *intrv1.LikeRequest,
+	opts ...grpc.CallOption) (*intrv1.LIkeResponse, error) {
+	if err := b.checkBlocked(ctx, in.GetBiz(), in.GetBizId(), in.GetUid()); err != nil {
+		return nil, err
+	}
+	return b.InteractiveServiceClient.Like(ctx, in, opts...)
+}
+
+func (b *BlockCheckInteractiveClient) Collect(ctx context.Context, in *intrv1.CollectRequest,
+	opts ...grpc.CallOption) (*intrv1.CollectResponse, error) {
+	if err := b.checkBlocked(ctx, in.GetBiz(), in.GetBizId(), in.GetUid()); err != nil {
+		return nil, err
+	}
+	return b.InteractiveServiceClient.Collect(ctx, in, opts...)
+}
+
+// checkBlocked 被拉黑的时候返回 ErrBlocked
+func (b *BlockCheckInteractiveClient) checkBlocked(ctx context.Context, biz string, bizId, uid int64) error {
+	if biz != bizArticle {
+		return nil
+	}
+	author, err := b.authors.GetPublishedAuthor(ctx, bizId)
+	if err != nil {
+		return fmt.Errorf("查询文章作者失败 %w", err)
+	}
+	resp, err := b.followSvc.IsBlocked(ctx, &followv1.IsBlockedRequest{
+		Uid:    author.Id,
+		Target: uid,
+	})
+	if err != nil {
+		return fmt.Errorf("查询拉黑关系失败 %w", err)
+	}
+	if resp.GetBlocked() {
+		return ErrBlocked
+	}
+	return nil
+}
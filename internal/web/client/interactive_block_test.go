@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	followv1 "webook/api/proto/gen/follow/v1"
+	followmocks "webook/api/proto/gen/follow/v1/mocks"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	intrmocks "webook/api/proto/gen/intr/v1/mocks"
+	"webook/internal/domain"
+	svcmocks "webook/internal/service/mocks"
+)
+
+func TestBlockCheckInteractiveClient_Like(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient,
+			AuthorFinder, followv1.FollowServiceClient)
+		req *intrv1.LikeRequest
+
+		wantErr error
+	}{
+		{
+			name: "没有被拉黑",
+			mock: func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient,
+				AuthorFinder, followv1.FollowServiceClient) {
+				intrSvc := intrmocks.NewMockInteractiveServiceClient(ctrl)
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+				artSvc.EXPECT().GetPublishedAuthor(gomock.Any(), int64(1)).
+					Return(domain.Author{Id: 2}, nil)
+				followSvc.EXPECT().IsBlocked(gomock.Any(), &followv1.IsBlockedRequest{Uid: 2, Target: 3}).
+					Return(&followv1.IsBlockedResponse{}, nil)
+				intrSvc.EXPECT().Like(gomock.Any(), gomock.Any()).Return(&intrv1.LIkeResponse{}, nil)
+				return intrSvc, artSvc, followSvc
+			},
+			req: &intrv1.LikeRequest{Biz: "article", BizId: 1, Uid: 3},
+		},
+		{
+			name: "被作者拉黑了",
+			mock: func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient,
+				AuthorFinder, followv1.FollowServiceClient) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+				artSvc.EXPECT().GetPublishedAuthor(gomock.Any(), int64(1)).
+					Return(domain.Author{Id: 2}, nil)
+				followSvc.EXPECT().IsBlocked(gomock.Any(), &followv1.IsBlockedRequest{Uid: 2, Target: 3}).
+					Return(&followv1.IsBlockedResponse{Blocked: true}, nil)
+				return intrmocks.NewMockInteractiveServiceClient(ctrl), artSvc, followSvc
+			},
+			req:     &intrv1.LikeRequest{Biz: "article", BizId: 1, Uid: 3},
+			wantErr: ErrBlocked,
+		},
+		{
+			name: "查询拉黑关系失败",
+			mock: func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient,
+				AuthorFinder, followv1.FollowServiceClient) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+				artSvc.EXPECT().GetPublishedAuthor(gomock.Any(), int64(1)).
+					Return(domain.Author{Id: 2}, nil)
+				followSvc.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("网络错误"))
+				return intrmocks.NewMockInteractiveServiceClient(ctrl), artSvc, followSvc
+			},
+			req:     &intrv1.LikeRequest{Biz: "article", BizId: 1, Uid: 3},
+			wantErr: errors.New("查询拉黑关系失败 网络错误"),
+		},
+		{
+			name: "别的业务不检查",
+			mock: func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient,
+				AuthorFinder, followv1.FollowServiceClient) {
+				intrSvc := intrmocks.NewMockInteractiveServiceClient(ctrl)
+				intrSvc.EXPECT().Like(gomock.Any(), gomock.Any()).Return(&intrv1.LIkeResponse{}, nil)
+				return intrSvc, svcmocks.NewMockArticleService(ctrl), followmocks.NewMockFollowServiceClient(ctrl)
+			},
+			req: &intrv1.LikeRequest{Biz: "video", BizId: 1, Uid: 3},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			intrSvc, authors, followSvc := tc.mock(ctrl)
+			c := NewBlockCheckInteractiveClient(intrSvc, authors, followSvc)
+			_, err := c.Like(context.Background(), tc.req)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.wantErr.Error())
+		})
+	}
+}
+
+func TestBlockCheckInteractiveClient_Collect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	artSvc := svcmocks.NewMockArticleService(ctrl)
+	followSvc := followmocks.NewMockFollowServiceClient(ctrl)
+	artSvc.EXPECT().GetPublishedAuthor(gomock.Any(), int64(1)).Return(domain.Author{Id: 2}, nil)
+	followSvc.EXPECT().IsBlocked(gomock.Any(), &followv1.IsBlockedRequest{Uid: 2, Target: 3}).
+		Return(&followv1.IsBlockedResponse{Blocked: true}, nil)
+	c := NewBlockCheckInteractiveClient(intrmocks.NewMockInteractiveServiceClient(ctrl), artSvc, followSvc)
+	_, err := c.Collect(context.Background(), &intrv1.CollectRequest{Biz: "article", BizId: 1, Cid: 4, Uid: 3})
+	assert.ErrorIs(t, err, ErrBlocked)
+}
@@ -51,6 +51,18 @@ func (i *InteractiveClient) GetByIds(ctx context.Context, in *intrv1.GetByIdsReq
 	return i.selectClient().GetByIds(ctx, in)
 }
 
+func (i *InteractiveClient) GetUserLikes(ctx context.Context, in *intrv1.GetUserLikesRequest, opts ...grpc.CallOption) (*intrv1.GetUserLikesResponse, error) {
+	return i.selectClient().GetUserLikes(ctx, in)
+}
+
+func (i *InteractiveClient) GetUserCollects(ctx context.Context, in *intrv1.GetUserCollectsRequest, opts ...grpc.CallOption) (*intrv1.GetUserCollectsResponse, error) {
+	return i.selectClient().GetUserCollects(ctx, in)
+}
+
+func (i *InteractiveClient) DeleteUserData(ctx context.Context, in *intrv1.DeleteUserDataRequest, opts ...grpc.CallOption) (*intrv1.DeleteUserDataResponse, error) {
+	return i.selectClient().DeleteUserData(ctx, in)
+}
+
 func (i *InteractiveClient) selectClient() intrv1.InteractiveServiceClient {
 	num := rand.Int31n(100)
 	if num < i.threshold.Load() {
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"github.com/ecodeclub/ekit/slice"
 	"google.golang.org/grpc"
 	intrv1 "webook/api/proto/gen/intr/v1"
 	"webook/interactive/domain"
@@ -75,3 +76,39 @@ func (i *InteractiveLocalAdapter) toDTO(intr domain.Interactive) *intrv1.Interac
 		Collected:  intr.Collected,
 	}
 }
+
+func (i *InteractiveLocalAdapter) GetUserLikes(ctx context.Context, in *intrv1.GetUserLikesRequest, opts ...grpc.CallOption) (*intrv1.GetUserLikesResponse, error) {
+	records, err := i.svc.GetUserLikes(ctx, in.GetUid(), int(in.GetOffset()), int(in.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.GetUserLikesResponse{
+		Records: i.toRecordDTOs(records),
+	}, nil
+}
+
+func (i *InteractiveLocalAdapter) GetUserCollects(ctx context.Context, in *intrv1.GetUserCollectsRequest, opts ...grpc.CallOption) (*intrv1.GetUserCollectsResponse, error) {
+	records, err := i.svc.GetUserCollects(ctx, in.GetUid(), int(in.GetOffset()), int(in.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.GetUserCollectsResponse{
+		Records: i.toRecordDTOs(records),
+	}, nil
+}
+
+func (i *InteractiveLocalAdapter) DeleteUserData(ctx context.Context, in *intrv1.DeleteUserDataRequest, opts ...grpc.CallOption) (*intrv1.DeleteUserDataResponse, error) {
+	err := i.svc.DeleteUserData(ctx, in.GetUid())
+	return &intrv1.DeleteUserDataResponse{}, err
+}
+
+func (i *InteractiveLocalAdapter) toRecordDTOs(records []domain.UserBizRecord) []*intrv1.UserBizRecord {
+	return slice.Map(records, func(idx int, src domain.UserBizRecord) *intrv1.UserBizRecord {
+		return &intrv1.UserBizRecord{
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Cid:   src.Cid,
+			Ctime: src.Ctime,
+		}
+	})
+}
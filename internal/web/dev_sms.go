@@ -0,0 +1,45 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"webook/internal/service/sms/memory"
+)
+
+// DevSMSHandler 开发环境查看假的短信服务发出去的短信，方便拿到验证码
+// 只有配置了假的短信服务的时候才会注册路由
+type DevSMSHandler struct {
+	inbox *memory.Service
+}
+
+func NewDevSMSHandler(inbox *memory.Service) *DevSMSHandler {
+	return &DevSMSHandler{inbox: inbox}
+}
+
+func (h *DevSMSHandler) RegisterRoutes(s *gin.Engine) {
+	if h.inbox == nil {
+		return
+	}
+	s.GET("/dev/sms", h.Inbox)
+}
+
+// Inbox 某个手机号最近收到的短信，最新的在前面
+func (h *DevSMSHandler) Inbox(ctx *gin.Context) {
+	type Req struct {
+		Phone string `form:"phone"`
+		Limit int    `form:"limit"`
+	}
+	var req Req
+	if err := ctx.BindQuery(&req); err != nil {
+		return
+	}
+	// 短信是按照 E.164 格式的号码发送的
+	phone, ok := normalizePhone(ctx, req.Phone)
+	if !ok {
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	ctx.JSON(http.StatusOK, Result{Data: h.inbox.Latest(phone, req.Limit)})
+}
@@ -0,0 +1,55 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// FeedHandler 关注流
+type FeedHandler struct {
+	svc service.FeedService
+	l   logger.Logger
+}
+
+func NewFeedHandler(svc service.FeedService, l logger.Logger) *FeedHandler {
+	return &FeedHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *FeedHandler) RegisterRoutes(s *gin.Engine) {
+	g := s.Group("/feed")
+	g.GET("/following", ginx.WrapClaimsAndReq[FeedReq](h.Following))
+}
+
+// Following 关注的作者发表的文章，按照发表时间倒序
+func (h *FeedHandler) Following(ctx *gin.Context, req FeedReq, uc ginx.UserClaims) (Result, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	page, err := h.svc.GetFeed(ctx, uc.Id, domain.FeedCursor{
+		Ctime: req.Cursor,
+		Aid:   req.CursorAid,
+	}, req.Limit)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: FeedVo{
+		Items: slice.Map(page.Items, func(idx int, src domain.FeedItem) FeedItemVo {
+			return FeedItemVo{
+				Aid:      src.Aid,
+				AuthorId: src.AuthorId,
+				Ctime:    src.Ctime,
+				Title:    src.Article.Title,
+				Abstract: src.Article.Abstract(),
+			}
+		}),
+		NextCursor:    page.Next.Ctime,
+		NextCursorAid: page.Next.Aid,
+	}}, nil
+}
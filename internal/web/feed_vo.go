@@ -0,0 +1,25 @@
+package web
+
+type FeedReq struct {
+	// 上一页返回的 nextCursor 和 nextCursorAid，第一页不传
+	Cursor    int64 `form:"cursor"`
+	CursorAid int64 `form:"cursorAid"`
+	Limit     int64 `form:"limit"`
+}
+
+type FeedVo struct {
+	Items []FeedItemVo `json:"items"`
+	// 下一页的游标，为 0 说明没有更多了
+	NextCursor int64 `json:"nextCursor"`
+	// 同一毫秒发表的文章靠文章 ID 区分，翻页的时候和 nextCursor 一起带上
+	NextCursorAid int64 `json:"nextCursorAid"`
+}
+
+type FeedItemVo struct {
+	Aid      int64  `json:"aid"`
+	AuthorId int64  `json:"authorId"`
+	Ctime    int64  `json:"ctime"`
+	Title    string `json:"title"`
+	// 摘要
+	Abstract string `json:"abstract"`
+}
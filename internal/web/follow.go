@@ -0,0 +1,220 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	followv1 "webook/api/proto/gen/follow/v1"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// FollowHandler 关注相关的接口，具体的逻辑都在 follow 服务里面
+type FollowHandler struct {
+	svc followv1.FollowServiceClient
+	l   logger.Logger
+}
+
+func NewFollowHandler(svc followv1.FollowServiceClient, l logger.Logger) *FollowHandler {
+	return &FollowHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *FollowHandler) RegisterRoutes(s *gin.Engine) {
+	g := s.Group("/users")
+	g.POST("/follow", ginx.WrapClaimsAndReq[FollowReq](h.Follow))
+	g.POST("/follow/cancel", ginx.WrapClaimsAndReq[FollowReq](h.CancelFollow))
+	g.GET("/followees", ginx.WrapClaimsAndReq[FollowListReq](h.Followees))
+	g.GET("/followers", ginx.WrapClaimsAndReq[FollowListReq](h.Followers))
+	g.GET("/follow/info", ginx.WrapClaimsAndReq[FollowUidReq](h.FollowInfo))
+	g.GET("/follow/statics", ginx.WrapClaimsAndReq[FollowUidReq](h.FollowStatics))
+
+	// 拉黑
+	g.POST("/block", ginx.WrapClaimsAndReq[BlockReq](h.Block))
+	g.POST("/block/cancel", ginx.WrapClaimsAndReq[BlockReq](h.CancelBlock))
+	g.GET("/blocks", ginx.WrapClaimsAndReq[BlockListReq](h.Blocks))
+	// 屏蔽
+	g.POST("/mute", ginx.WrapClaimsAndReq[BlockReq](h.Mute))
+	g.POST("/mute/cancel", ginx.WrapClaimsAndReq[BlockReq](h.CancelMute))
+	g.GET("/mutes", ginx.WrapClaimsAndReq[BlockListReq](h.Mutes))
+}
+
+func (h *FollowHandler) Follow(ctx *gin.Context, req FollowReq, uc ginx.UserClaims) (Result, error) {
+	_, err := h.svc.Follow(ctx, &followv1.FollowRequest{
+		Follower: uc.Id,
+		Followee: req.Followee,
+	})
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return Result{Code: 4, Msg: "不能关注自己"}, nil
+	case codes.PermissionDenied:
+		return Result{Code: 4, Msg: "对方已将你拉黑"}, nil
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+func (h *FollowHandler) CancelFollow(ctx *gin.Context, req FollowReq, uc ginx.UserClaims) (Result, error) {
+	_, err := h.svc.CancelFollow(ctx, &followv1.CancelFollowRequest{
+		Follower: uc.Id,
+		Followee: req.Followee,
+	})
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+// Followees 关注列表
+func (h *FollowHandler) Followees(ctx *gin.Context, req FollowListReq, uc ginx.UserClaims) (Result, error) {
+	resp, err := h.svc.GetFollowee(ctx, &followv1.GetFolloweeRequest{
+		Follower: h.uidOrSelf(req.Uid, uc),
+		Offset:   req.Offset,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: h.toVos(resp.GetFollowRelations())}, nil
+}
+
+// Followers 粉丝列表
+func (h *FollowHandler) Followers(ctx *gin.Context, req FollowListReq, uc ginx.UserClaims) (Result, error) {
+	resp, err := h.svc.GetFollower(ctx, &followv1.GetFollowerRequest{
+		Followee: h.uidOrSelf(req.Uid, uc),
+		Offset:   req.Offset,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: h.toVos(resp.GetFollowRelations())}, nil
+}
+
+// FollowInfo 我和 uid 之间的关注关系
+func (h *FollowHandler) FollowInfo(ctx *gin.Context, req FollowUidReq, uc ginx.UserClaims) (Result, error) {
+	resp, err := h.svc.FollowInfo(ctx, &followv1.FollowInfoRequest{
+		Follower: uc.Id,
+		Followee: req.Uid,
+	})
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: FollowInfoVo{
+		Followed:     resp.GetFollowed(),
+		FollowedBack: resp.GetFollowedBack(),
+		Mutual:       resp.GetFollowed() && resp.GetFollowedBack(),
+	}}, nil
+}
+
+// FollowStatics 粉丝数和关注数
+func (h *FollowHandler) FollowStatics(ctx *gin.Context, req FollowUidReq, uc ginx.UserClaims) (Result, error) {
+	resp, err := h.svc.GetFollowStatics(ctx, &followv1.GetFollowStaticsRequest{
+		Uid: h.uidOrSelf(req.Uid, uc),
+	})
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: FollowStaticsVo{
+		Followers: resp.GetStatics().GetFollowers(),
+		Followees: resp.GetStatics().GetFollowees(),
+	}}, nil
+}
+
+// Block 拉黑，拉黑之后会解除双方的关注关系
+func (h *FollowHandler) Block(ctx *gin.Context, req BlockReq, uc ginx.UserClaims) (Result, error) {
+	return h.block(ctx, uc.Id, req.Uid, followv1.BlockType_BLOCK_TYPE_BLOCK)
+}
+
+func (h *FollowHandler) CancelBlock(ctx *gin.Context, req BlockReq, uc ginx.UserClaims) (Result, error) {
+	return h.cancelBlock(ctx, uc.Id, req.Uid, followv1.BlockType_BLOCK_TYPE_BLOCK)
+}
+
+func (h *FollowHandler) Blocks(ctx *gin.Context, req BlockListReq, uc ginx.UserClaims) (Result, error) {
+	return h.blockList(ctx, uc.Id, req, followv1.BlockType_BLOCK_TYPE_BLOCK)
+}
+
+// Mute 屏蔽，关注流和热榜中不再出现对方的文章
+func (h *FollowHandler) Mute(ctx *gin.Context, req BlockReq, uc ginx.UserClaims) (Result, error) {
+	return h.block(ctx, uc.Id, req.Uid, followv1.BlockType_BLOCK_TYPE_MUTE)
+}
+
+func (h *FollowHandler) CancelMute(ctx *gin.Context, req BlockReq, uc ginx.UserClaims) (Result, error) {
+	return h.cancelBlock(ctx, uc.Id, req.Uid, followv1.BlockType_BLOCK_TYPE_MUTE)
+}
+
+func (h *FollowHandler) Mutes(ctx *gin.Context, req BlockListReq, uc ginx.UserClaims) (Result, error) {
+	return h.blockList(ctx, uc.Id, req, followv1.BlockType_BLOCK_TYPE_MUTE)
+}
+
+func (h *FollowHandler) block(ctx *gin.Context, uid, target int64, typ followv1.BlockType) (Result, error) {
+	_, err := h.svc.Block(ctx, &followv1.BlockRequest{
+		Uid:    uid,
+		Target: target,
+		Type:   typ,
+	})
+	if status.Code(err) == codes.InvalidArgument {
+		return Result{Code: 4, Msg: "不能拉黑或者屏蔽自己"}, nil
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+func (h *FollowHandler) cancelBlock(ctx *gin.Context, uid, target int64, typ followv1.BlockType) (Result, error) {
+	_, err := h.svc.CancelBlock(ctx, &followv1.CancelBlockRequest{
+		Uid:    uid,
+		Target: target,
+		Type:   typ,
+	})
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+func (h *FollowHandler) blockList(ctx *gin.Context, uid int64, req BlockListReq, typ followv1.BlockType) (Result, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	resp, err := h.svc.GetBlockList(ctx, &followv1.GetBlockListRequest{
+		Uid:    uid,
+		Type:   typ,
+		Offset: req.Offset,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{
+		Data: slice.Map(resp.GetBlockRelations(), func(idx int, src *followv1.BlockRelation) BlockVo {
+			return BlockVo{
+				Uid:   src.GetTarget(),
+				Ctime: src.GetCtime(),
+			}
+		}),
+	}, nil
+}
+
+func (h *FollowHandler) uidOrSelf(uid int64, uc ginx.UserClaims) int64 {
+	if uid > 0 {
+		return uid
+	}
+	return uc.Id
+}
+
+func (h *FollowHandler) toVos(list []*followv1.FollowRelation) []FollowRelationVo {
+	return slice.Map(list, func(idx int, src *followv1.FollowRelation) FollowRelationVo {
+		return FollowRelationVo{
+			Follower: src.GetFollower(),
+			Followee: src.GetFollowee(),
+			Ctime:    src.GetCtime(),
+		}
+	})
+}
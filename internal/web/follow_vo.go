@@ -0,0 +1,53 @@
+package web
+
+type FollowReq struct {
+	// 被关注的人
+	Followee int64 `json:"followee"`
+}
+
+type FollowListReq struct {
+	// 要查看谁的列表，不传就是自己
+	Uid    int64 `form:"uid"`
+	Offset int64 `form:"offset"`
+	Limit  int64 `form:"limit"`
+}
+
+type FollowUidReq struct {
+	Uid int64 `form:"uid"`
+}
+
+type FollowRelationVo struct {
+	Follower int64 `json:"follower"`
+	Followee int64 `json:"followee"`
+	Ctime    int64 `json:"ctime"`
+}
+
+type FollowInfoVo struct {
+	// 我是否关注了对方
+	Followed bool `json:"followed"`
+	// 对方是否关注了我
+	FollowedBack bool `json:"followedBack"`
+	// 是否互相关注
+	Mutual bool `json:"mutual"`
+}
+
+type FollowStaticsVo struct {
+	Followers int64 `json:"followers"`
+	Followees int64 `json:"followees"`
+}
+
+type BlockReq struct {
+	// 要拉黑或者屏蔽的人
+	Uid int64 `json:"uid"`
+}
+
+type BlockListReq struct {
+	Offset int64 `form:"offset"`
+	Limit  int64 `form:"limit"`
+}
+
+type BlockVo struct {
+	// 被拉黑或者屏蔽的人
+	Uid   int64 `json:"uid"`
+	Ctime int64 `json:"ctime"`
+}
@@ -0,0 +1,28 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	ijwt "webook/internal/web/jwt"
+)
+
+// JWKSHandler 公开短 token 的验证公钥，别的服务可以自己验证 token，不需要调用我们
+// 长 token 只有我们自己用，所以不公开
+type JWKSHandler struct {
+	keys ijwt.Keys
+}
+
+func NewJWKSHandler(keys ijwt.Keys) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+func (h *JWKSHandler) RegisterRoutes(s *gin.Engine) {
+	s.GET("/.well-known/jwks.json", h.JWKS)
+}
+
+// JWKS 这里是标准的格式，所以不用 Result 包装
+func (h *JWKSHandler) JWKS(ctx *gin.Context) {
+	// 公钥的变化不频繁，允许缓存一段时间
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.keys.Access.JWKS())
+}
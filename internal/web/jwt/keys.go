@@ -0,0 +1,185 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+)
+
+var (
+	ErrUnknownKid       = errors.New("未知的 kid")
+	ErrUnexpectedMethod = errors.New("签名算法和 kid 对应的密钥不匹配")
+)
+
+// Key 一个签名密钥
+// 对称算法（HS256）的 SignKey 和 VerifyKey 是同一个 []byte
+// 非对称算法（RS256、EdDSA）的 SignKey 是私钥，VerifyKey 是公钥，
+// 只用来验证的旧密钥可以只有公钥
+type Key struct {
+	Kid       string
+	Method    jwt.SigningMethod
+	SignKey   any
+	VerifyKey any
+}
+
+// NewHMACKey 使用 HS256 的密钥
+func NewHMACKey(kid string, secret []byte) Key {
+	return Key{
+		Kid:       kid,
+		Method:    jwt.SigningMethodHS256,
+		SignKey:   secret,
+		VerifyKey: secret,
+	}
+}
+
+// NewRSAKey 使用 RS256 的密钥，privateKey 为 nil 的时候只能用来验证
+func NewRSAKey(kid string, privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) Key {
+	k := Key{Kid: kid, Method: jwt.SigningMethodRS256, VerifyKey: publicKey}
+	if privateKey != nil {
+		k.SignKey = privateKey
+		k.VerifyKey = &privateKey.PublicKey
+	}
+	return k
+}
+
+// NewEdDSAKey 使用 EdDSA（Ed25519）的密钥，privateKey 为 nil 的时候只能用来验证
+func NewEdDSAKey(kid string, privateKey ed25519.PrivateKey, publicKey ed25519.PublicKey) Key {
+	k := Key{Kid: kid, Method: jwt.SigningMethodEdDSA, VerifyKey: publicKey}
+	if privateKey != nil {
+		k.SignKey = privateKey
+		k.VerifyKey = privateKey.Public()
+	}
+	return k
+}
+
+// KeySet 一组密钥，其中一个用来签名，全部都可以用来验证
+// 轮换密钥的步骤：
+//  1. 加入新的密钥，但是依旧用旧的密钥签名；如果是多实例部署，等所有实例都更新配置
+//  2. 切换成用新的密钥签名
+//  3. 等旧的密钥签发的 token 全部过期之后，移除旧的密钥
+type KeySet struct {
+	signing Key
+	keys    map[string]Key
+}
+
+// NewKeySet signingKid 是用来签名的密钥，必须在 keys 里面，并且有私钥
+func NewKeySet(signingKid string, keys ...Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]Key, len(keys))}
+	for _, k := range keys {
+		if _, ok := ks.keys[k.Kid]; ok {
+			return nil, fmt.Errorf("重复的 kid %s", k.Kid)
+		}
+		ks.keys[k.Kid] = k
+	}
+	signing, ok := ks.keys[signingKid]
+	if !ok || signing.SignKey == nil {
+		return nil, fmt.Errorf("签名密钥 %s 不存在或者没有私钥", signingKid)
+	}
+	ks.signing = signing
+	return ks, nil
+}
+
+// Sign 用当前的签名密钥签名，并且在 header 里面带上 kid
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.Kid
+	return token.SignedString(ks.signing.SignKey)
+}
+
+// Keyfunc 根据 header 里面的 kid 找到验证的密钥
+// 没有 kid 的是引入 kid 之前签发的 token，用当前的签名密钥来验证，避免升级的时候所有人都被踢下线
+func (ks *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	k := ks.signing
+	if kid, ok := token.Header["kid"].(string); ok {
+		k, ok = ks.keys[kid]
+		if !ok {
+			return nil, ErrUnknownKid
+		}
+	}
+	// 防止算法混淆攻击，例如用 RSA 公钥当做 HMAC 的密钥来伪造 token
+	if token.Method.Alg() != k.Method.Alg() {
+		return nil, ErrUnexpectedMethod
+	}
+	return k.VerifyKey, nil
+}
+
+// Parse 解析并且验证 token
+func (ks *KeySet) Parse(tokenStr string, claims jwt.Claims) error {
+	token, err := jwt.ParseWithClaims(tokenStr, claims, ks.Keyfunc)
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return errors.New("token 无效")
+	}
+	return nil
+}
+
+// JWKS 公钥集合，给别的服务验证 token 用
+// 对称密钥不能公开，所以只包含非对称的密钥
+func (ks *KeySet) JWKS() JWKS {
+	res := JWKS{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, k := range ks.keys {
+		jwk, ok := toJWK(k)
+		if ok {
+			res.Keys = append(res.Keys, jwk)
+		}
+	}
+	return res
+}
+
+// JWKS 参考 RFC 7517
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func toJWK(k Key) (JWK, bool) {
+	enc := base64.RawURLEncoding
+	switch pub := k.VerifyKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: k.Kid,
+			Alg: k.Method.Alg(),
+			Use: "sig",
+			N:   enc.EncodeToString(pub.N.Bytes()),
+			E:   enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: k.Kid,
+			Alg: k.Method.Alg(),
+			Use: "sig",
+			Crv: "Ed25519",
+			X:   enc.EncodeToString(pub),
+		}, true
+	}
+	// 其余的，例如对称密钥，不能公开
+	return JWK{}, false
+}
+
+// Keys 登录用的两组密钥
+type Keys struct {
+	// Access 短 token
+	Access *KeySet
+	// Refresh 长 token
+	Refresh *KeySet
+}
@@ -0,0 +1,168 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestKeySet_Parse(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	oldKey := NewHMACKey("k1", []byte("old secret"))
+	newKey := NewHMACKey("k2", []byte("new secret"))
+
+	claims := func() UserClaims {
+		return UserClaims{
+			Id: 123,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name string
+		// 签发 token
+		sign func(t *testing.T) string
+		// 验证 token
+		verifier *KeySet
+
+		wantErr error
+		wantId  int64
+	}{
+		{
+			name: "轮换之后，旧密钥签发的 token 依旧有效",
+			sign: func(t *testing.T) string {
+				ks, err := NewKeySet("k1", oldKey)
+				require.NoError(t, err)
+				token, err := ks.Sign(claims())
+				require.NoError(t, err)
+				return token
+			},
+			verifier: mustKeySet(t, "k2", oldKey, newKey),
+			wantId:   123,
+		},
+		{
+			name: "旧密钥已经移除",
+			sign: func(t *testing.T) string {
+				ks, err := NewKeySet("k1", oldKey)
+				require.NoError(t, err)
+				token, err := ks.Sign(claims())
+				require.NoError(t, err)
+				return token
+			},
+			verifier: mustKeySet(t, "k2", newKey),
+			wantErr:  ErrUnknownKid,
+		},
+		{
+			name: "没有 kid 的 token 使用签名密钥验证",
+			sign: func(t *testing.T) string {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims()).
+					SignedString([]byte("old secret"))
+				require.NoError(t, err)
+				return token
+			},
+			verifier: mustKeySet(t, "k1", oldKey),
+			wantId:   123,
+		},
+		{
+			name: "RS256",
+			sign: func(t *testing.T) string {
+				token, err := mustKeySet(t, "r1", NewRSAKey("r1", rsaKey, nil)).Sign(claims())
+				require.NoError(t, err)
+				return token
+			},
+			// 只有公钥也可以验证
+			verifier: mustKeySet(t, "k1", oldKey, NewRSAKey("r1", nil, &rsaKey.PublicKey)),
+			wantId:   123,
+		},
+		{
+			name: "EdDSA",
+			sign: func(t *testing.T) string {
+				token, err := mustKeySet(t, "e1", NewEdDSAKey("e1", edKey, nil)).Sign(claims())
+				require.NoError(t, err)
+				return token
+			},
+			verifier: mustKeySet(t, "e1", NewEdDSAKey("e1", edKey, nil)),
+			wantId:   123,
+		},
+		{
+			name: "算法和 kid 不匹配",
+			sign: func(t *testing.T) string {
+				// 伪造 kid，用 HMAC 冒充 RSA 的密钥
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+				token.Header["kid"] = "r1"
+				str, err := token.SignedString([]byte("old secret"))
+				require.NoError(t, err)
+				return str
+			},
+			verifier: mustKeySet(t, "k1", oldKey, NewRSAKey("r1", nil, &rsaKey.PublicKey)),
+			wantErr:  ErrUnexpectedMethod,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var uc UserClaims
+			err := tc.verifier.Parse(tc.sign(t), &uc)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantId, uc.Id)
+		})
+	}
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ks := mustKeySet(t, "r1",
+		NewRSAKey("r1", rsaKey, nil),
+		NewEdDSAKey("e1", nil, edPub),
+		NewHMACKey("k1", []byte("secret")))
+
+	jwks := ks.JWKS()
+	// 对称密钥不能公开
+	assert.Len(t, jwks.Keys, 2)
+	kids := map[string]JWK{}
+	for _, k := range jwks.Keys {
+		kids[k.Kid] = k
+	}
+	assert.Equal(t, "RSA", kids["r1"].Kty)
+	assert.Equal(t, "RS256", kids["r1"].Alg)
+	// 65537
+	assert.Equal(t, "AQAB", kids["r1"].E)
+	assert.Equal(t, "OKP", kids["e1"].Kty)
+	assert.Equal(t, "Ed25519", kids["e1"].Crv)
+}
+
+func TestNewKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	// 签名密钥不存在
+	_, err = NewKeySet("k2", NewHMACKey("k1", []byte("secret")))
+	assert.Error(t, err)
+	// 签名密钥没有私钥
+	_, err = NewKeySet("r1", NewRSAKey("r1", nil, &rsaKey.PublicKey))
+	assert.Error(t, err)
+	// 重复的 kid
+	_, err = NewKeySet("k1", NewHMACKey("k1", []byte("a")), NewHMACKey("k1", []byte("b")))
+	assert.Error(t, err)
+}
+
+func mustKeySet(t *testing.T, active string, keys ...Key) *KeySet {
+	ks, err := NewKeySet(active, keys...)
+	require.NoError(t, err)
+	return ks
+}
@@ -0,0 +1,19 @@
+-- 当前有效的长 token 的 ID
+local key = KEYS[1]
+-- 客户端提交的长 token 的 ID
+local old = ARGV[1]
+-- 新的长 token 的 ID
+local new = ARGV[2]
+-- 过期时间，秒
+local expiration = tonumber(ARGV[3])
+
+local cur = redis.call("get", key)
+if cur == false then
+    -- 会话不存在或者已经过期了
+    return -1
+elseif cur ~= old then
+    -- 已经被轮换掉的长 token 又被提交了一次
+    return -2
+end
+redis.call("set", key, new, "EX", expiration)
+return 0
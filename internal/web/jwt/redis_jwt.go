@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"context"
+	_ "embed"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
@@ -11,36 +13,78 @@ import (
 	"time"
 )
 
-// AccessTokenKey 因为 JWT Key 不太可能变，所以可以直接写成常量
-var AccessTokenKey = []byte("moyn8y9abnd7q4zkq2m73yw8tu9j5ixm")
-var RefreshTokenKey = []byte("moyn8y9abnd7q4zkq2m73yw8tu9j5ixA")
+var (
+	//go:embed lua/rotate_refresh.lua
+	luaRotateRefresh string
+
+	ErrRefreshTokenInvalid = errors.New("长 token 无效或者会话已经过期")
+	ErrRefreshTokenReused  = errors.New("长 token 被重复使用")
+	// ErrSessionExpired 会话到了绝对过期时间，不能再续期短 token
+	ErrSessionExpired = errors.New("会话已经过期，需要刷新 token")
+)
 
 type RedisHandler struct {
-	cmd redis.Cmdable
+	cmd   redis.Cmdable
+	roles RoleProvider
+	keys  Keys
+	// 短 token 的过期时间
+	atExpiration time.Duration
 	// 长 token 的过期时间
 	rtExpiration time.Duration
 }
 
-func NewRedisHandler(cmd redis.Cmdable) Handler {
+func NewRedisHandler(cmd redis.Cmdable, roles RoleProvider, keys Keys) Handler {
 	return &RedisHandler{
 		cmd:          cmd,
+		roles:        roles,
+		keys:         keys,
+		atExpiration: time.Minute * 30,
 		rtExpiration: time.Hour * 24 * 7,
 	}
 }
 
 func (h *RedisHandler) SetJWTToken(ctx *gin.Context, ssid string, uid int64) error {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, UserClaims{
+	// 每次生成 token 都重新查询角色，这样刷新 token 之后角色的修改就生效了
+	roles, err := h.roles(ctx, uid)
+	if err != nil {
+		return err
+	}
+	return h.signAccessToken(ctx, UserClaims{
 		Id:        uid, // 用户 ID
 		Ssid:      ssid,
 		UserAgent: ctx.GetHeader("User-Agent"), // 从请求头中获取 User-Agent
-		RegisteredClaims: jwt.RegisteredClaims{
-			//ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 30)), // 设置过期时间为 30 分钟
-		},
+		Roles:     roles,
+		// 和一起签发的长 token 同时过期
+		SessionExpiresAt: jwt.NewNumericDate(time.Now().Add(h.rtExpiration)),
 	})
+}
+
+// RenewAccessToken 续期短 token，会话和 User-Agent 的绑定关系不变
+// 角色和 SetJWTToken 一样重新查询，所以撤销的角色在下一次续期的时候就会失效
+// 续期不能超过会话的绝对过期时间，否则活跃的客户端就永远不需要刷新 token，
+// 也就绕过了长 token 的轮换和重复使用检测
+func (h *RedisHandler) RenewAccessToken(ctx *gin.Context, uc UserClaims) error {
+	// 引入绝对过期时间之前签发的短 token 没有这个字段，也当成过期了
+	if uc.SessionExpiresAt == nil || !uc.SessionExpiresAt.After(time.Now()) {
+		return ErrSessionExpired
+	}
+	roles, err := h.roles(ctx, uc.Id)
+	if err != nil {
+		return err
+	}
+	uc.Roles = roles
+	return h.signAccessToken(ctx, uc)
+}
 
-	// 使用 AccessTokenKey 对 token 进行签名
-	tokenStr, err := token.SignedString(AccessTokenKey)
+func (h *RedisHandler) signAccessToken(ctx *gin.Context, uc UserClaims) error {
+	expiresAt := time.Now().Add(h.atExpiration)
+	// 短 token 不能比会话活得久
+	if uc.SessionExpiresAt != nil && uc.SessionExpiresAt.Before(expiresAt) {
+		expiresAt = uc.SessionExpiresAt.Time
+	}
+	uc.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	// 使用短 token 的签名密钥进行签名，header 里面会带上 kid
+	tokenStr, err := h.keys.Access.Sign(uc)
 	if err != nil {
 		// 如果签名过程中出错，返回系统异常信息
 		return err
@@ -58,13 +102,27 @@ func (h *RedisHandler) ClearToken(ctx *gin.Context) error {
 	ctx.Header("x-refresh-token", "")
 	// 这里不可能拿不到
 	uc := ctx.MustGet("user").(UserClaims)
-	return h.cmd.Set(ctx, h.key(uc.Ssid), "", h.rtExpiration).Err()
+	pipe := h.cmd.TxPipeline()
+	pipe.Set(ctx, h.key(uc.Ssid), "", h.rtExpiration)
+	pipe.Del(ctx, h.refreshKey(uc.Ssid))
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
 func (h *RedisHandler) key(ssid string) string {
 	return fmt.Sprintf("users:Ssid:%s", ssid)
 }
 
+// refreshKey 会话当前有效的长 token 的 ID
+// 一个会话里面先后签发的长 token 是一个 family，只有最新的那个是有效的
+func (h *RedisHandler) refreshKey(ssid string) string {
+	return fmt.Sprintf("users:refresh:%s", ssid)
+}
+
+func (h *RedisHandler) sessionsKey(uid int64) string {
+	return fmt.Sprintf("users:sessions:%d", uid)
+}
+
 // SetLoginToken 设置登录后的 token
 func (h *RedisHandler) SetLoginToken(ctx *gin.Context, uid int64) error {
 	ssid := uuid.New().String()
@@ -73,19 +131,62 @@ func (h *RedisHandler) SetLoginToken(ctx *gin.Context, uid int64) error {
 		return err
 	}
 	err = h.setRefreshToken(ctx, ssid, uid)
+	if err != nil {
+		return err
+	}
+	return h.addSession(ctx, uid, ssid)
+}
+
+// addSession 记录用户有哪些会话，方便在修改密码之类的场景下让其它会话失效
+func (h *RedisHandler) addSession(ctx context.Context, uid int64, ssid string) error {
+	key := h.sessionsKey(uid)
+	pipe := h.cmd.TxPipeline()
+	pipe.SAdd(ctx, key, ssid)
+	// 长 token 过期之后，会话也就没有意义了
+	pipe.Expire(ctx, key, h.rtExpiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisHandler) RevokeSessions(ctx context.Context, uid int64, exceptSsid string) error {
+	key := h.sessionsKey(uid)
+	ssids, err := h.cmd.SMembers(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	pipe := h.cmd.TxPipeline()
+	for _, ssid := range ssids {
+		if ssid == exceptSsid {
+			continue
+		}
+		// 和退出登录一样，标记这个会话已经失效
+		pipe.Set(ctx, h.key(ssid), "", h.rtExpiration)
+		pipe.Del(ctx, h.refreshKey(ssid))
+		pipe.SRem(ctx, key, ssid)
+	}
+	_, err = pipe.Exec(ctx)
 	return err
 }
 
 func (h *RedisHandler) setRefreshToken(ctx *gin.Context, ssid string, uid int64) error {
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, RefreshClaims{
-		Id: uid,
+	jti := uuid.New().String()
+	err := h.cmd.Set(ctx, h.refreshKey(ssid), jti, h.rtExpiration).Err()
+	if err != nil {
+		return err
+	}
+	return h.signRefreshToken(ctx, ssid, uid, jti)
+}
+
+func (h *RedisHandler) signRefreshToken(ctx *gin.Context, ssid string, uid int64, jti string) error {
+	refreshTokenStr, err := h.keys.Refresh.Sign(RefreshClaims{
+		Id:   uid,
+		Ssid: ssid,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID: jti,
 			// 设置为七天过期
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 7)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(h.rtExpiration)),
 		},
 	})
-
-	refreshTokenStr, err := refreshToken.SignedString(RefreshTokenKey)
 	if err != nil {
 		return err
 	}
@@ -93,6 +194,62 @@ func (h *RedisHandler) setRefreshToken(ctx *gin.Context, ssid string, uid int64)
 	return nil
 }
 
+// RotateRefreshToken 每次刷新都会签发新的长 token，旧的长 token 随之失效
+// 如果已经失效的长 token 又被提交了，说明长 token 很可能被盗了，
+// 这个时候无法分辨哪一边是真正的用户，所以整个会话都失效，让用户重新登录
+func (h *RedisHandler) RotateRefreshToken(ctx *gin.Context, rc RefreshClaims) error {
+	// 引入轮换之前签发的长 token 没有 ssid 和 ID，只能重新登录
+	if rc.Ssid == "" || rc.ID == "" {
+		return ErrRefreshTokenInvalid
+	}
+	jti := uuid.New().String()
+	res, err := h.cmd.Eval(ctx, luaRotateRefresh, []string{h.refreshKey(rc.Ssid)},
+		rc.ID, jti, int(h.rtExpiration.Seconds())).Int()
+	if err != nil {
+		return err
+	}
+	switch res {
+	case 0:
+	case -1:
+		return ErrRefreshTokenInvalid
+	case -2:
+		err = h.revokeSession(ctx, rc.Id, rc.Ssid)
+		if err != nil {
+			return fmt.Errorf("%w, 并且让会话失效失败 %w", ErrRefreshTokenReused, err)
+		}
+		return ErrRefreshTokenReused
+	default:
+		return errors.New("轮换长 token 遇到未知错误")
+	}
+	err = h.SetJWTToken(ctx, rc.Ssid, rc.Id)
+	if err != nil {
+		return err
+	}
+	return h.signRefreshToken(ctx, rc.Ssid, rc.Id, jti)
+}
+
+// revokeSession 让一个会话失效
+func (h *RedisHandler) revokeSession(ctx context.Context, uid int64, ssid string) error {
+	pipe := h.cmd.TxPipeline()
+	pipe.Set(ctx, h.key(ssid), "", h.rtExpiration)
+	pipe.Del(ctx, h.refreshKey(ssid))
+	pipe.SRem(ctx, h.sessionsKey(uid), ssid)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisHandler) ParseAccessToken(tokenStr string) (UserClaims, error) {
+	var uc UserClaims
+	err := h.keys.Access.Parse(tokenStr, &uc)
+	return uc, err
+}
+
+func (h *RedisHandler) ParseRefreshToken(tokenStr string) (RefreshClaims, error) {
+	var rc RefreshClaims
+	err := h.keys.Refresh.Parse(tokenStr, &rc)
+	return rc, err
+}
+
 func (h *RedisHandler) CheckSession(ctx *gin.Context, ssid string) error {
 	logout, err := h.cmd.Exists(ctx, fmt.Sprintf("users:Ssid:%s", ssid)).Result()
 	if err != nil {
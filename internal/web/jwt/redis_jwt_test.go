@@ -0,0 +1,198 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"webook/internal/repository/cache/redismocks"
+)
+
+func TestRedisHandler_RotateRefreshToken(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+		rc   RefreshClaims
+
+		wantErr error
+		// 是否签发了新的长短 token
+		wantTokens bool
+	}{
+		{
+			name: "轮换成功",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				cmd.EXPECT().Eval(gomock.Any(), luaRotateRefresh,
+					[]string{"users:refresh:ssid-1"}, "jti-1", gomock.Any(), gomock.Any()).
+					Return(redis.NewCmdResult(int64(0), nil))
+				return cmd
+			},
+			rc:         refreshClaims(123, "ssid-1", "jti-1"),
+			wantTokens: true,
+		},
+		{
+			name: "会话已经过期",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				cmd.EXPECT().Eval(gomock.Any(), luaRotateRefresh,
+					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(redis.NewCmdResult(int64(-1), nil))
+				return cmd
+			},
+			rc:      refreshClaims(123, "ssid-1", "jti-1"),
+			wantErr: ErrRefreshTokenInvalid,
+		},
+		{
+			name: "轮换之前签发的长 token",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				return redismocks.NewMockCmdable(ctrl)
+			},
+			rc:      refreshClaims(123, "", ""),
+			wantErr: ErrRefreshTokenInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			keys := Keys{
+				Access:  mustKeySet(t, "k1", NewHMACKey("k1", []byte("access"))),
+				Refresh: mustKeySet(t, "k1", NewHMACKey("k1", []byte("refresh"))),
+			}
+			hdl := NewRedisHandler(tc.mock(ctrl), func(ctx context.Context, uid int64) ([]string, error) {
+				return nil, nil
+			}, keys)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/users/refresh_token", nil)
+
+			err := hdl.RotateRefreshToken(ctx, tc.rc)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if !tc.wantTokens {
+				return
+			}
+			// 新的长 token 带着同一个会话，但是 ID 换了
+			rc, err := hdl.ParseRefreshToken(recorder.Header().Get("x-refresh-token"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.rc.Ssid, rc.Ssid)
+			assert.NotEqual(t, tc.rc.ID, rc.ID)
+			uc, err := hdl.ParseAccessToken(recorder.Header().Get("x-jwt-token"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.rc.Id, uc.Id)
+		})
+	}
+}
+
+func TestRedisHandler_RenewAccessToken(t *testing.T) {
+	testCases := []struct {
+		name  string
+		roles RoleProvider
+		uc    UserClaims
+
+		wantErr   error
+		wantRoles []string
+		// 新的短 token 最晚的过期时间
+		wantExpiresBefore time.Time
+	}{
+		{
+			name: "续期的时候重新查询角色",
+			roles: func(ctx context.Context, uid int64) ([]string, error) {
+				return []string{}, nil
+			},
+			uc: UserClaims{
+				Id:               123,
+				Ssid:             "ssid-1",
+				Roles:            []string{"admin"},
+				SessionExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
+			},
+			wantRoles:         []string{},
+			wantExpiresBefore: time.Now().Add(time.Minute * 31),
+		},
+		{
+			name: "不能超过会话的过期时间",
+			roles: func(ctx context.Context, uid int64) ([]string, error) {
+				return []string{"admin"}, nil
+			},
+			uc: UserClaims{
+				Id:               123,
+				Ssid:             "ssid-1",
+				SessionExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 5)),
+			},
+			wantRoles:         []string{"admin"},
+			wantExpiresBefore: time.Now().Add(time.Minute * 6),
+		},
+		{
+			name: "会话已经过期",
+			roles: func(ctx context.Context, uid int64) ([]string, error) {
+				return nil, nil
+			},
+			uc: UserClaims{
+				Id:               123,
+				SessionExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+			},
+			wantErr: ErrSessionExpired,
+		},
+		{
+			name: "没有会话过期时间的旧 token",
+			roles: func(ctx context.Context, uid int64) ([]string, error) {
+				return nil, nil
+			},
+			uc:      UserClaims{Id: 123},
+			wantErr: ErrSessionExpired,
+		},
+		{
+			name: "查询角色失败",
+			roles: func(ctx context.Context, uid int64) ([]string, error) {
+				return nil, errors.New("数据库错误")
+			},
+			uc: UserClaims{
+				Id:               123,
+				SessionExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+			wantErr: errors.New("数据库错误"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys := Keys{
+				Access:  mustKeySet(t, "k1", NewHMACKey("k1", []byte("access"))),
+				Refresh: mustKeySet(t, "k1", NewHMACKey("k1", []byte("refresh"))),
+			}
+			hdl := NewRedisHandler(nil, tc.roles, keys)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/users/profile", nil)
+
+			err := hdl.RenewAccessToken(ctx, tc.uc)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				assert.Empty(t, recorder.Header().Get("x-jwt-token"))
+				return
+			}
+			uc, err := hdl.ParseAccessToken(recorder.Header().Get("x-jwt-token"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRoles, uc.Roles)
+			assert.Equal(t, tc.uc.Ssid, uc.Ssid)
+			assert.True(t, uc.ExpiresAt.Before(tc.wantExpiresBefore))
+			assert.Equal(t, tc.uc.SessionExpiresAt.Unix(), uc.SessionExpiresAt.Unix())
+		})
+	}
+}
+
+func refreshClaims(uid int64, ssid, jti string) RefreshClaims {
+	rc := RefreshClaims{Id: uid, Ssid: ssid}
+	rc.ID = jti
+	return rc
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -9,8 +10,21 @@ type Handler interface {
 	ClearToken(ctx *gin.Context) error
 	SetLoginToken(ctx *gin.Context, uid int64) error
 	SetJWTToken(ctx *gin.Context, ssid string, uid int64) error
+	// RenewAccessToken 给快要过期的短 token 续期，新的短 token 放在 x-jwt-token 里面
+	// 会话已经到了绝对过期时间的时候返回 ErrSessionExpired
+	RenewAccessToken(ctx *gin.Context, uc UserClaims) error
 	CheckSession(ctx *gin.Context, ssid string) error
 	ExtractTokenString(ctx *gin.Context) string
+	// ParseAccessToken 解析并且验证短 token
+	ParseAccessToken(tokenStr string) (UserClaims, error)
+	// ParseRefreshToken 解析并且验证长 token
+	ParseRefreshToken(tokenStr string) (RefreshClaims, error)
+	// RotateRefreshToken 用长 token 换取新的长短 token，旧的长 token 会失效
+	// 返回 ErrRefreshTokenReused 的时候，整个会话都已经失效了
+	RotateRefreshToken(ctx *gin.Context, rc RefreshClaims) error
+	// RevokeSessions 让用户的登录会话失效，exceptSsid 是需要保留的会话，一般是当前会话
+	// exceptSsid 为空的时候，用户所有的会话都会失效
+	RevokeSessions(ctx context.Context, uid int64, exceptSsid string) error
 }
 
 type RefreshClaims struct {
@@ -23,5 +37,14 @@ type UserClaims struct {
 	Id        int64
 	UserAgent string
 	Ssid      string
+	// Roles 用户的角色，权限校验的中间件直接用这个，不需要查询数据库
+	// 所以修改角色之后，要等到下一次刷新或者续期 token 才会生效
+	Roles []string
+	// SessionExpiresAt 会话的绝对过期时间，也就是签发时候的长 token 的过期时间
+	// 滑动续期不能超过这个时间，过了之后只能走刷新 token 的流程
+	SessionExpiresAt *jwt.NumericDate
 	jwt.RegisteredClaims
 }
+
+// RoleProvider 查询用户的角色，生成 token 的时候用
+type RoleProvider func(ctx context.Context, uid int64) ([]string, error)
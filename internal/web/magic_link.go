@@ -0,0 +1,72 @@
+package web
+
+import (
+	"errors"
+	regexp "github.com/dlclark/regexp2"
+	"github.com/gin-gonic/gin"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// MagicLinkHandler 通过邮件里面的登录链接登录，不需要密码
+type MagicLinkHandler struct {
+	svc           service.MagicLinkService
+	emailRegexExp *regexp.Regexp
+	ijwt.Handler
+	l logger.Logger
+}
+
+func NewMagicLinkHandler(svc service.MagicLinkService, jwtHdl ijwt.Handler, l logger.Logger) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		svc:           svc,
+		emailRegexExp: regexp.MustCompile(emailRegexPattern, regexp.None),
+		Handler:       jwtHdl,
+		l:             l,
+	}
+}
+
+func (h *MagicLinkHandler) RegisterRoutes(s *gin.Engine) {
+	g := s.Group("/users/login_link")
+	g.POST("/send", ginx.WrapReq[SendMagicLinkReq](h.Send))
+	// 邮件里面的链接打开的是前端页面，用户点击登录之后前端再调用这个接口
+	g.POST("/confirm", ginx.WrapReq[ConfirmMagicLinkReq](h.Confirm))
+}
+
+func (h *MagicLinkHandler) Send(ctx *gin.Context, req SendMagicLinkReq) (Result, error) {
+	ok, err := h.emailRegexExp.MatchString(req.Email)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	if !ok {
+		return Result{Code: 4, Msg: "邮箱格式不正确"}, nil
+	}
+	err = h.svc.Send(ctx, req.Email)
+	switch {
+	case err == nil:
+		return Result{Msg: "登录链接已经发送到邮箱"}, nil
+	case errors.Is(err, service.ErrMagicLinkSendTooMany):
+		return Result{Code: 4, Msg: "发送太频繁，请稍后再试"}, nil
+	default:
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *MagicLinkHandler) Confirm(ctx *gin.Context, req ConfirmMagicLinkReq) (Result, error) {
+	u, err := h.svc.Login(ctx, req.Token)
+	switch {
+	case err == nil:
+	case errors.Is(err, service.ErrInvalidMagicLink):
+		return Result{Code: 4, Msg: "登录链接无效或者已经过期"}, nil
+	case errors.Is(err, service.ErrUserBanned):
+		return Result{Code: 4, Msg: "账号已被封禁"}, nil
+	default:
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	err = h.SetLoginToken(ctx, u.Id)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "登录成功"}, nil
+}
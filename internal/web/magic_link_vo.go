@@ -0,0 +1,9 @@
+package web
+
+type SendMagicLinkReq struct {
+	Email string `json:"email"`
+}
+
+type ConfirmMagicLinkReq struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import "webook/internal/domain"
+
+// AuthPolicy 路由的登录校验策略
+type AuthPolicy uint8
+
+const (
+	// AuthRequired 必须登录，没有声明策略的路由都是这个
+	AuthRequired AuthPolicy = iota
+	// AuthOptional 登录了就解析出用户信息，没有登录的话 uid 为 0，例如游客也可以看的文章详情
+	AuthOptional
+	// AuthNone 不需要登录，也不会解析 token，例如登录、注册
+	AuthNone
+)
+
+// AuthPolicies 按照 gin 的 FullPath 声明路由的登录校验策略
+// 用 FullPath 而不是请求的路径，是为了支持 /articles/pub/:id 这种带参数的路由
+type AuthPolicies struct {
+	policies map[string]AuthPolicy
+	// 个人访问令牌能访问的接口，以及要求的权限范围
+	scopes map[string]domain.Scope
+}
+
+func NewAuthPolicies() *AuthPolicies {
+	return &AuthPolicies{
+		policies: make(map[string]AuthPolicy, 16),
+		scopes:   make(map[string]domain.Scope, 16),
+	}
+}
+
+// Scope 声明这些路由可以用个人访问令牌访问，并且令牌要有 scope 这个权限范围
+func (p *AuthPolicies) Scope(scope domain.Scope, fullPaths ...string) *AuthPolicies {
+	for _, path := range fullPaths {
+		p.scopes[path] = scope
+	}
+	return p
+}
+
+// Allow 个人访问令牌能否访问这个路由，没有声明权限范围的路由都不能访问
+func (p *AuthPolicies) Allow(fullPath string, pat domain.PersonalAccessToken) bool {
+	scope, ok := p.scopes[fullPath]
+	return ok && pat.HasScope(scope)
+}
+
+// None 声明这些路由不需要登录
+func (p *AuthPolicies) None(fullPaths ...string) *AuthPolicies {
+	return p.set(AuthNone, fullPaths)
+}
+
+// Optional 声明这些路由登录与否都可以访问
+func (p *AuthPolicies) Optional(fullPaths ...string) *AuthPolicies {
+	return p.set(AuthOptional, fullPaths)
+}
+
+func (p *AuthPolicies) set(policy AuthPolicy, fullPaths []string) *AuthPolicies {
+	for _, path := range fullPaths {
+		p.policies[path] = policy
+	}
+	return p
+}
+
+// Policy 查询路由的策略，没有匹配到路由的时候 FullPath 是空字符串，也是必须登录
+func (p *AuthPolicies) Policy(fullPath string) AuthPolicy {
+	return p.policies[fullPath]
+}
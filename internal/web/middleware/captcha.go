@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+	"webook/pkg/ratelimit"
+)
+
+// CaptchaHeader 前端把通过图形验证码之后拿到的凭证放在这个请求头里面
+const CaptchaHeader = "X-Captcha-Token"
+
+// CaptchaVerifier 校验通过图形验证码之后拿到的凭证
+type CaptchaVerifier interface {
+	VerifyPass(ctx context.Context, token string) (bool, error)
+}
+
+// CaptchaMiddlewareBuilder 要求请求先通过图形验证码，只用在单个路由上，例如注册、登录
+// 凭证只能用一次，所以每次请求之前都要重新做一次图形验证码
+// 设置了 SoftLimit 的话，同一个 IP 的请求次数没有超过阈值之前不需要图形验证码
+type CaptchaMiddlewareBuilder struct {
+	verifier CaptchaVerifier
+	// limiter 软阈值，为 nil 的时候每次都要图形验证码
+	limiter ratelimit.Limiter
+	l       logger.Logger
+}
+
+func NewCaptchaMiddlewareBuilder(verifier CaptchaVerifier, l logger.Logger) *CaptchaMiddlewareBuilder {
+	return &CaptchaMiddlewareBuilder{
+		verifier: verifier,
+		l:        l,
+	}
+}
+
+// SoftLimit 和发送验证码一样，同一个 IP 在窗口内的请求被 limiter 限流之后才需要图形验证码
+// 窗口和阈值由 limiter 决定
+func (b *CaptchaMiddlewareBuilder) SoftLimit(limiter ratelimit.Limiter) *CaptchaMiddlewareBuilder {
+	b.limiter = limiter
+	return b
+}
+
+func (b *CaptchaMiddlewareBuilder) Build() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if b.limiter != nil {
+			limited, err := b.limiter.Limit(ctx,
+				fmt.Sprintf("captcha:soft:%s:%s", ctx.FullPath(), ctx.ClientIP()))
+			if err != nil {
+				// 保守一点，计数失败的时候要求图形验证码
+				b.l.Error("图形验证码软阈值计数失败", logger.Error(err))
+			}
+			if err == nil && !limited {
+				return
+			}
+		}
+		ok, err := b.verifier.VerifyPass(ctx, ctx.GetHeader(CaptchaHeader))
+		if err != nil {
+			b.l.Error("校验图形验证码凭证失败", logger.Error(err))
+			ctx.AbortWithStatusJSON(http.StatusOK, ginx.Result{Code: 5, Msg: "系统错误"})
+			return
+		}
+		if !ok {
+			// 和发送短信验证码要求图形验证码的时候一样，前端看到 6 就弹出图形验证码
+			ctx.AbortWithStatusJSON(http.StatusOK, ginx.Result{Code: 6, Msg: "请先完成图形验证码"})
+			return
+		}
+	}
+}
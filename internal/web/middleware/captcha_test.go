@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+	"webook/pkg/ratelimit"
+	limitmocks "webook/pkg/ratelimit/mocks"
+)
+
+func TestCaptchaMiddlewareBuilder(t *testing.T) {
+	testCases := []struct {
+		name  string
+		mock  func(ctrl *gomock.Controller) CaptchaVerifier
+		token string
+		// limiter 为 nil 的时候每次都要图形验证码
+		limiter func(ctrl *gomock.Controller) ratelimit.Limiter
+
+		wantCode int
+	}{
+		{
+			name: "凭证有效",
+			mock: func(ctrl *gomock.Controller) CaptchaVerifier {
+				svc := svcmocks.NewMockCaptchaService(ctrl)
+				svc.EXPECT().VerifyPass(gomock.Any(), "pass").Return(true, nil)
+				return svc
+			},
+			token:    "pass",
+			wantCode: 0,
+		},
+		{
+			name: "没有凭证或者凭证无效",
+			mock: func(ctrl *gomock.Controller) CaptchaVerifier {
+				svc := svcmocks.NewMockCaptchaService(ctrl)
+				svc.EXPECT().VerifyPass(gomock.Any(), "").Return(false, nil)
+				return svc
+			},
+			wantCode: 6,
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) CaptchaVerifier {
+				svc := svcmocks.NewMockCaptchaService(ctrl)
+				svc.EXPECT().VerifyPass(gomock.Any(), "pass").Return(false, errors.New("redis 错误"))
+				return svc
+			},
+			token:    "pass",
+			wantCode: 5,
+		},
+		{
+			name: "没有超过软阈值，不需要图形验证码",
+			mock: func(ctrl *gomock.Controller) CaptchaVerifier {
+				return svcmocks.NewMockCaptchaService(ctrl)
+			},
+			limiter: func(ctrl *gomock.Controller) ratelimit.Limiter {
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				limiter.EXPECT().Limit(gomock.Any(), "captcha:soft:/users/signup:192.0.2.1").Return(false, nil)
+				return limiter
+			},
+			wantCode: 0,
+		},
+		{
+			name: "超过软阈值，需要图形验证码",
+			mock: func(ctrl *gomock.Controller) CaptchaVerifier {
+				svc := svcmocks.NewMockCaptchaService(ctrl)
+				svc.EXPECT().VerifyPass(gomock.Any(), "").Return(false, nil)
+				return svc
+			},
+			limiter: func(ctrl *gomock.Controller) ratelimit.Limiter {
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				limiter.EXPECT().Limit(gomock.Any(), gomock.Any()).Return(true, nil)
+				return limiter
+			},
+			wantCode: 6,
+		},
+		{
+			name: "软阈值计数失败，需要图形验证码",
+			mock: func(ctrl *gomock.Controller) CaptchaVerifier {
+				svc := svcmocks.NewMockCaptchaService(ctrl)
+				svc.EXPECT().VerifyPass(gomock.Any(), "pass").Return(true, nil)
+				return svc
+			},
+			limiter: func(ctrl *gomock.Controller) ratelimit.Limiter {
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				limiter.EXPECT().Limit(gomock.Any(), gomock.Any()).Return(false, errors.New("redis 错误"))
+				return limiter
+			},
+			token:    "pass",
+			wantCode: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			server := gin.New()
+			builder := NewCaptchaMiddlewareBuilder(tc.mock(ctrl), logger.NewZapLogger(zap.NewNop()))
+			if tc.limiter != nil {
+				builder = builder.SoftLimit(tc.limiter(ctrl))
+			}
+			mw := builder.Build()
+			server.POST("/users/signup", mw, func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, ginx.Result{Msg: "注册成功"})
+			})
+
+			req, err := http.NewRequest(http.MethodPost, "/users/signup", nil)
+			require.NoError(t, err)
+			req.RemoteAddr = "192.0.2.1:1234"
+			if tc.token != "" {
+				req.Header.Set(CaptchaHeader, tc.token)
+			}
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res ginx.Result
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+			assert.Equal(t, tc.wantCode, res.Code)
+		})
+	}
+}
@@ -1,105 +1,177 @@
 package middleware
 
 import (
-	"github.com/ecodeclub/ekit/set"
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
+	"webook/internal/domain"
 	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
 )
 
 // JWTLoginMiddlewareBuilder 是一个中间件构建器，用于验证用户请求中的JWT令牌。
 type JWTLoginMiddlewareBuilder struct {
-	publicPaths set.Set[string]
+	// 每个路由的登录校验策略
+	policies *AuthPolicies
+	// 短 token 剩余的有效期小于这个值的时候续期，0 表示不续期
+	renewThreshold time.Duration
+	renewCounter   *prometheus.CounterVec
+	// 个人访问令牌，为 nil 的时候不支持
+	pats PATVerifier
+	l    logger.Logger
 	ijwt.Handler
 }
 
-func NewJWTLoginMiddlewareBuilder(hdl ijwt.Handler) *JWTLoginMiddlewareBuilder {
-	s := set.NewMapSet[string](5)
-	// 如果请求的路径是用户注册（/users/signup）或登录（/users/login）
-	// 这些接口不需要JWT验证，直接放行
-	s.Add("/users/signup")
-	s.Add("/users/login_sms/code/send")
-	s.Add("/users/login_sms")
-	s.Add("/users/login")
-	s.Add("/users/refresh_token")
-	s.Add("/users/metrics")
+// renewCounter 续期的次数，同一个进程里面可能会 Build 多次，例如测试或者多个 gin.Engine，
+// 所以是包变量，只注册一次
+var (
+	renewCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "webook_server",
+		Subsystem: "webook",
+		Name:      "jwt_renew",
+		Help:      "短 token 滑动续期的次数",
+	}, []string{"result"})
+	renewCounterOnce sync.Once
+)
+
+// PATVerifier 校验个人访问令牌
+type PATVerifier interface {
+	Verify(ctx context.Context, token string) (domain.PersonalAccessToken, error)
+}
+
+func NewJWTLoginMiddlewareBuilder(hdl ijwt.Handler, policies *AuthPolicies, l logger.Logger) *JWTLoginMiddlewareBuilder {
 	return &JWTLoginMiddlewareBuilder{
-		publicPaths: s,
-		Handler:     hdl,
+		policies: policies,
+		Handler:  hdl,
+		l:        l,
 	}
 }
 
+// WithPAT 允许使用个人访问令牌代替 JWT，只能访问令牌的权限范围对应的接口
+func (j *JWTLoginMiddlewareBuilder) WithPAT(pats PATVerifier) *JWTLoginMiddlewareBuilder {
+	j.pats = pats
+	return j
+}
+
+// RenewWithin 开启短 token 的滑动续期，剩余的有效期小于 threshold 的时候会签发新的短 token，
+// 这样活跃的用户就不会每隔一段时间就收到 401，然后去调用刷新接口
+// 续期的次数和结果会记录到 Prometheus 里面
+func (j *JWTLoginMiddlewareBuilder) RenewWithin(threshold time.Duration) *JWTLoginMiddlewareBuilder {
+	j.renewThreshold = threshold
+	return j
+}
+
 // Build 方法创建并返回一个Gin的中间件，负责JWT的验证。
 func (j *JWTLoginMiddlewareBuilder) Build() gin.HandlerFunc {
+	if j.renewThreshold > 0 {
+		renewCounterOnce.Do(func() {
+			prometheus.MustRegister(renewCounter)
+		})
+		j.renewCounter = renewCounter
+	}
 	return func(ctx *gin.Context) {
-		// 不需要校验
-		if j.publicPaths.Exist(ctx.Request.URL.Path) {
+		// 全局的中间件在路由匹配之后才执行，所以这里可以拿到 FullPath
+		policy := j.policies.Policy(ctx.FullPath())
+		if policy == AuthNone {
+			// 不需要校验
 			return
 		}
 
 		tokenStr := j.ExtractTokenString(ctx)
-
-		// 创建UserClaims结构体用于解析token中的claim信息
-		uc := ijwt.UserClaims{}
-
-		// 使用jwt.ParseWithClaims解析token并验证其合法性
-		// tokenStr是待验证的JWT字符串，uc是用于存放解析后的claims信息
-		// web.JWTKey是密钥，用于验证token的签名
-		token, err := jwt.ParseWithClaims(tokenStr, &uc, func(token *jwt.Token) (interface{}, error) {
-			return ijwt.AccessTokenKey, nil
-		})
-
-		if err != nil || !token.Valid {
-			// 如果token解析失败或无效，返回401 Unauthorized
-			ctx.AbortWithStatus(http.StatusUnauthorized)
-			return
+		var (
+			uc ijwt.UserClaims
+			ok bool
+		)
+		if j.pats != nil && strings.HasPrefix(tokenStr, domain.PATPrefix) {
+			var pat domain.PersonalAccessToken
+			pat, ok = j.verifyPAT(ctx, tokenStr)
+			// 个人访问令牌只能访问声明了权限范围的接口
+			if ok && !j.policies.Allow(ctx.FullPath(), pat) {
+				ctx.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			// 访问令牌没有会话，也没有角色，所以管理后台之类的接口都用不了
+			uc = ijwt.UserClaims{Id: pat.Uid}
+		} else {
+			uc, ok = j.verify(ctx, tokenStr)
 		}
-
-		// 从UserClaims中获取过期时间（expiresAt）
-		expireTime, err := uc.GetExpirationTime()
-		if err != nil {
-			// 如果无法获取过期时间，返回401 Unauthorized
+		if !ok {
+			if policy == AuthOptional {
+				// 没有登录或者登录已经失效，当成游客来处理，uid 为 0
+				ctx.Set("user", ijwt.UserClaims{})
+				return
+			}
 			ctx.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
 
-		// 如果token已经过期，返回401 Unauthorized
-		if expireTime.Before(time.Now()) {
-			ctx.AbortWithStatus(http.StatusUnauthorized)
-			return
-		}
+		// 如果token有效且未过期，则将token中的用户信息（claims）存放到Gin的上下文中
+		// 这样后续的请求可以通过ctx.Get("user")来获取用户信息，避免重复解析token
+		ctx.Set("user", uc)
+	}
+}
 
-		if ctx.GetHeader("User-Agent") != uc.UserAgent {
-			// 换了一个 User-Agent，可能是攻击者
-			ctx.AbortWithStatus(http.StatusUnauthorized)
-			return
-		}
+// verify 校验 token，校验通过的话返回 token 中的用户信息
+func (j *JWTLoginMiddlewareBuilder) verify(ctx *gin.Context, tokenStr string) (ijwt.UserClaims, bool) {
+	// 解析token并验证其合法性，根据 header 里面的 kid 选择验证的密钥
+	uc, err := j.ParseAccessToken(tokenStr)
+	if err != nil {
+		return ijwt.UserClaims{}, false
+	}
 
-		err = j.CheckSession(ctx, uc.Ssid)
-		if err != nil {
-			ctx.AbortWithStatus(http.StatusUnauthorized)
-			return
-		}
+	// 从UserClaims中获取过期时间（expiresAt）
+	expireTime, err := uc.GetExpirationTime()
+	if err != nil || expireTime == nil {
+		return ijwt.UserClaims{}, false
+	}
 
-		//// 刷新 token
-		//if expireTime.Sub(time.Now()) < time.Second*50 {
-		//	// 如果距离过期时间小于 50 秒，进行 token 刷新
-		//	uc.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Minute)) // 设置新的过期时间为当前时间加一分钟
-		//	newToken, err := token.SignedString(web.AccessTokenKey)                // 使用原来的 JWT 密钥对新的 claims 生成新的 token
-		//	if err != nil {
-		//		// 如果生成新的 token 出错，则打印错误日志
-		//		log.Println(err)
-		//	} else {
-		//		// 如果生成新 token 成功，则将新的 token 添加到响应头中
-		//		// 这里的 "x-jwt-token" 是自定义的 HTTP 响应头，客户端可以使用该 header 获取新的 token
-		//		ctx.Header("x-jwt-token", newToken)
-		//	}
-		//}
+	// 如果token已经过期
+	if expireTime.Before(time.Now()) {
+		return ijwt.UserClaims{}, false
+	}
 
-		// 如果token有效且未过期，则将token中的用户信息（claims）存放到Gin的上下文中
-		// 这样后续的请求可以通过ctx.Get("user")来获取用户信息，避免重复解析token
-		ctx.Set("user", uc)
+	if ctx.GetHeader("User-Agent") != uc.UserAgent {
+		// 换了一个 User-Agent，可能是攻击者
+		return ijwt.UserClaims{}, false
+	}
+
+	err = j.CheckSession(ctx, uc.Ssid)
+	if err != nil {
+		return ijwt.UserClaims{}, false
+	}
+
+	// 快要过期了，续期短 token。续期失败不影响这一次请求
+	if j.renewThreshold > 0 && time.Until(expireTime.Time) < j.renewThreshold {
+		j.renew(ctx, uc)
+	}
+	return uc, true
+}
+
+func (j *JWTLoginMiddlewareBuilder) verifyPAT(ctx *gin.Context, tokenStr string) (domain.PersonalAccessToken, bool) {
+	pat, err := j.pats.Verify(ctx, tokenStr)
+	if err != nil {
+		j.l.Warn("个人访问令牌校验失败", logger.Error(err))
+		return domain.PersonalAccessToken{}, false
+	}
+	return pat, true
+}
+
+func (j *JWTLoginMiddlewareBuilder) renew(ctx *gin.Context, uc ijwt.UserClaims) {
+	err := j.RenewAccessToken(ctx, uc)
+	if errors.Is(err, ijwt.ErrSessionExpired) {
+		// 会话到期了，等短 token 过期之后前端走刷新 token 的流程
+		j.renewCounter.WithLabelValues("session_expired").Inc()
+		return
+	}
+	if err != nil {
+		j.renewCounter.WithLabelValues("failed").Inc()
+		j.l.Error("续期短 token 失败", logger.Int64("uid", uc.Id), logger.Error(err))
+		return
 	}
+	j.renewCounter.WithLabelValues("success").Inc()
 }
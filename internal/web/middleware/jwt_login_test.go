@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/cache/redismocks"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+func TestJWTLoginMiddlewareBuilder_Policy(t *testing.T) {
+	ks, err := ijwt.NewKeySet("k1", ijwt.NewHMACKey("k1", []byte("secret")))
+	require.NoError(t, err)
+	keys := ijwt.Keys{Access: ks, Refresh: ks}
+
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+		path string
+		// 是否带上登录的 token
+		login bool
+
+		wantCode int
+		wantUid  int64
+	}{
+		{
+			name: "不需要登录",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				return redismocks.NewMockCmdable(ctrl)
+			},
+			path:     "/users/login",
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "必须登录，没有登录",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				return redismocks.NewMockCmdable(ctrl)
+			},
+			path:     "/users/profile",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "可选登录，没有登录",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				return redismocks.NewMockCmdable(ctrl)
+			},
+			path:     "/articles/pub/12",
+			wantCode: http.StatusOK,
+			wantUid:  0,
+		},
+		{
+			name: "可选登录，已经登录",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				cmd.EXPECT().Exists(gomock.Any(), gomock.Any()).
+					Return(redis.NewIntResult(0, nil))
+				return cmd
+			},
+			path:     "/articles/pub/12",
+			login:    true,
+			wantCode: http.StatusOK,
+			wantUid:  123,
+		},
+		{
+			name: "没有注册的路由",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				return redismocks.NewMockCmdable(ctrl)
+			},
+			path:     "/not/found",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := ijwt.NewRedisHandler(tc.mock(ctrl), func(ctx context.Context, uid int64) ([]string, error) {
+				return nil, nil
+			}, keys)
+			policies := NewAuthPolicies().
+				None("/users/login").
+				Optional("/articles/pub/:id")
+
+			server := gin.New()
+			server.Use(NewJWTLoginMiddlewareBuilder(hdl, policies, logger.NewZapLogger(zap.NewNop())).Build())
+			var uid int64
+			handle := func(ctx *gin.Context) {
+				if uc, ok := ctx.Get("user"); ok {
+					uid = uc.(ijwt.UserClaims).Id
+				}
+			}
+			server.POST("/users/login", handle)
+			server.GET("/users/profile", handle)
+			server.GET("/articles/pub/:id", handle)
+
+			method := http.MethodGet
+			if tc.path == "/users/login" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, tc.path, nil)
+			if tc.login {
+				// 借用登录的逻辑签发一个短 token
+				recorder := httptest.NewRecorder()
+				ctx, _ := gin.CreateTestContext(recorder)
+				ctx.Request = req
+				require.NoError(t, hdl.SetJWTToken(ctx, "ssid", 123))
+				req.Header.Set("Authorization", "Bearer "+recorder.Header().Get("x-jwt-token"))
+			}
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantUid, uid)
+		})
+	}
+}
+
+func TestJWTLoginMiddlewareBuilder_PAT(t *testing.T) {
+	ks, err := ijwt.NewKeySet("k1", ijwt.NewHMACKey("k1", []byte("secret")))
+	require.NoError(t, err)
+	keys := ijwt.Keys{Access: ks, Refresh: ks}
+	pats := patVerifierFunc(func(ctx context.Context, token string) (domain.PersonalAccessToken, error) {
+		if token != "wbk_valid" {
+			return domain.PersonalAccessToken{}, errors.New("令牌无效")
+		}
+		return domain.PersonalAccessToken{
+			Uid:    123,
+			Scopes: []domain.Scope{domain.ScopeArticleRead},
+		}, nil
+	})
+
+	testCases := []struct {
+		name  string
+		path  string
+		token string
+
+		wantCode int
+		wantUid  int64
+	}{
+		{
+			name:     "拥有权限范围",
+			path:     "/articles/pub/12",
+			token:    "wbk_valid",
+			wantCode: http.StatusOK,
+			wantUid:  123,
+		},
+		{
+			name:     "没有权限范围",
+			path:     "/feed/following",
+			token:    "wbk_valid",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "没有声明权限范围的接口",
+			path:     "/users/tokens",
+			token:    "wbk_valid",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "令牌无效",
+			path:     "/feed/following",
+			token:    "wbk_invalid",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := ijwt.NewRedisHandler(redismocks.NewMockCmdable(ctrl),
+				func(ctx context.Context, uid int64) ([]string, error) {
+					return nil, nil
+				}, keys)
+			policies := NewAuthPolicies().
+				Scope(domain.ScopeArticleRead, "/articles/pub/:id").
+				Scope(domain.ScopeFeedRead, "/feed/following")
+
+			server := gin.New()
+			server.Use(NewJWTLoginMiddlewareBuilder(hdl, policies, logger.NewZapLogger(zap.NewNop())).
+				WithPAT(pats).Build())
+			var uid int64
+			handle := func(ctx *gin.Context) {
+				uid = ctx.MustGet("user").(ijwt.UserClaims).Id
+			}
+			server.GET("/articles/pub/:id", handle)
+			server.GET("/feed/following", handle)
+			server.GET("/users/tokens", handle)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantUid, uid)
+		})
+	}
+}
+
+type patVerifierFunc func(ctx context.Context, token string) (domain.PersonalAccessToken, error)
+
+func (f patVerifierFunc) Verify(ctx context.Context, token string) (domain.PersonalAccessToken, error) {
+	return f(ctx, token)
+}
+
+func TestJWTLoginMiddlewareBuilder_BuildTwice(t *testing.T) {
+	// 开启续期之后 Build 多次，例如多个 gin.Engine，不能重复注册 Prometheus 指标
+	assert.NotPanics(t, func() {
+		for i := 0; i < 2; i++ {
+			NewJWTLoginMiddlewareBuilder(nil, NewAuthPolicies(), logger.NewZapLogger(zap.NewNop())).
+				RenewWithin(time.Minute * 5).Build()
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"webook/internal/domain"
+	ijwt "webook/internal/web/jwt"
+)
+
+// RBACMiddlewareBuilder 校验用户是否拥有权限，必须放在 JWT 登录校验的后面
+// 一般是挂在路由分组上，整个分组的接口都要求这些权限
+type RBACMiddlewareBuilder struct {
+	perms []domain.Permission
+}
+
+// NewRBACMiddlewareBuilder perms 是要求的权限，必须全部拥有
+func NewRBACMiddlewareBuilder(perms ...domain.Permission) *RBACMiddlewareBuilder {
+	return &RBACMiddlewareBuilder{
+		perms: perms,
+	}
+}
+
+func (b *RBACMiddlewareBuilder) Build() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		uc, ok := ctx.MustGet("user").(ijwt.UserClaims)
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		roles := slice.Map(uc.Roles, func(idx int, src string) domain.Role {
+			return domain.Role(src)
+		})
+		for _, p := range b.perms {
+			if !domain.HasPermission(roles, p) {
+				ctx.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"errors"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+// PATHandler 管理个人访问令牌
+// 这些接口没有声明权限范围，所以只能用登录的 JWT 访问，不能用令牌来创建令牌
+type PATHandler struct {
+	svc service.PATService
+	l   logger.Logger
+}
+
+func NewPATHandler(svc service.PATService, l logger.Logger) *PATHandler {
+	return &PATHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *PATHandler) RegisterRoutes(s *gin.Engine) {
+	g := s.Group("/users/tokens")
+	g.POST("", ginx.WrapClaimsAndReq[CreatePATReq](h.Create))
+	g.GET("", ginx.WrapClaims(h.List))
+	g.POST("/revoke", ginx.WrapClaimsAndReq[RevokePATReq](h.Revoke))
+}
+
+func (h *PATHandler) Create(ctx *gin.Context, req CreatePATReq, uc ginx.UserClaims) (Result, error) {
+	if req.Name == "" || len(req.Name) > 64 {
+		return Result{Code: 4, Msg: "名字不能为空，并且不能超过 64 个字符"}, nil
+	}
+	scopes := slice.Map(req.Scopes, func(idx int, src string) domain.Scope {
+		return domain.Scope(src)
+	})
+	token, pat, err := h.svc.Create(ctx, uc.Id, req.Name, scopes, time.Duration(req.ExpireDays)*time.Hour*24)
+	switch {
+	case err == nil:
+		return Result{Data: CreatePATVo{
+			Token: token,
+			PAT:   h.toVo(pat),
+		}}, nil
+	case errors.Is(err, service.ErrInvalidScope):
+		return Result{Code: 4, Msg: "权限范围不对"}, nil
+	case errors.Is(err, service.ErrInvalidPATTTL):
+		return Result{Code: 4, Msg: "有效期必须在 1 到 365 天之间"}, nil
+	case errors.Is(err, service.ErrTooManyPATs):
+		return Result{Code: 4, Msg: "访问令牌太多了，请先撤销不用的"}, nil
+	default:
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *PATHandler) List(ctx *gin.Context, uc ginx.UserClaims) (Result, error) {
+	pats, err := h.svc.List(ctx, uc.Id)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: slice.Map(pats, func(idx int, src domain.PersonalAccessToken) PATVo {
+		return h.toVo(src)
+	})}, nil
+}
+
+func (h *PATHandler) Revoke(ctx *gin.Context, req RevokePATReq, uc ginx.UserClaims) (Result, error) {
+	err := h.svc.Revoke(ctx, uc.Id, req.Id)
+	switch {
+	case err == nil:
+		return Result{Msg: "撤销成功"}, nil
+	case errors.Is(err, service.ErrPATNotFound):
+		return Result{Code: 4, Msg: "访问令牌不存在"}, nil
+	default:
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+func (h *PATHandler) toVo(pat domain.PersonalAccessToken) PATVo {
+	return PATVo{
+		Id:   pat.Id,
+		Name: pat.Name,
+		Scopes: slice.Map(pat.Scopes, func(idx int, src domain.Scope) string {
+			return string(src)
+		}),
+		Expiration: pat.Expiration.Format(time.DateTime),
+		Expired:    pat.Expired(time.Now()),
+		Ctime:      pat.Ctime.Format(time.DateTime),
+	}
+}
@@ -0,0 +1,27 @@
+package web
+
+type CreatePATReq struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// 有效期，天数
+	ExpireDays int `json:"expireDays"`
+}
+
+type RevokePATReq struct {
+	Id int64 `json:"id"`
+}
+
+type PATVo struct {
+	Id         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	Expiration string   `json:"expiration"`
+	Expired    bool     `json:"expired"`
+	Ctime      string   `json:"ctime"`
+}
+
+// CreatePATVo 令牌只在创建的时候返回这一次
+type CreatePATVo struct {
+	Token string `json:"token"`
+	PAT   PATVo  `json:"pat"`
+}
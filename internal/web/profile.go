@@ -0,0 +1,203 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	regexp "github.com/dlclark/regexp2"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"io"
+	"net/http"
+	"net/url"
+	followv1 "webook/api/proto/gen/follow/v1"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/service/storage"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+const (
+	// 用户名以字母开头，只能包含字母、数字和下划线，长度 4 到 20
+	handleRegexPattern = `^[a-zA-Z][a-zA-Z0-9_]{3,19}$`
+	// 头像最大 2MB
+	maxAvatarSize = 2 << 20
+	// 个人主页展示最近发表的文章数量
+	profileArticleCnt = 10
+)
+
+// avatarExts 允许上传的头像格式
+var avatarExts = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// ProfileHandler 用户名、头像以及公开的个人主页
+type ProfileHandler struct {
+	svc            service.UserService
+	artSvc         service.ArticleService
+	followSvc      followv1.FollowServiceClient
+	storage        storage.Service
+	handleRegexExp *regexp.Regexp
+	l              logger.Logger
+}
+
+func NewProfileHandler(svc service.UserService, artSvc service.ArticleService,
+	followSvc followv1.FollowServiceClient, storage storage.Service, l logger.Logger) *ProfileHandler {
+	return &ProfileHandler{
+		svc:            svc,
+		artSvc:         artSvc,
+		followSvc:      followSvc,
+		storage:        storage,
+		handleRegexExp: regexp.MustCompile(handleRegexPattern, regexp.None),
+		l:              l,
+	}
+}
+
+func (h *ProfileHandler) RegisterRoutes(s *gin.Engine) {
+	g := s.Group("/users")
+	g.POST("/handle", ginx.WrapClaimsAndReq[ChangeHandleReq](h.ChangeHandle))
+	g.POST("/avatar", ginx.WrapClaimsAndReq[UpdateAvatarReq](h.UpdateAvatar))
+	g.POST("/avatar/upload", ginx.WrapClaims(h.UploadAvatar))
+	// 不需要登录
+	g.GET("/public/profile", ginx.WrapReq[PublicProfileReq](h.PublicProfile))
+}
+
+func (h *ProfileHandler) ChangeHandle(ctx *gin.Context, req ChangeHandleReq, uc ginx.UserClaims) (Result, error) {
+	ok, err := h.handleRegexExp.MatchString(req.Handle)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	if !ok {
+		return Result{Code: 4, Msg: "用户名以字母开头，只能包含字母、数字和下划线，长度 4 到 20"}, nil
+	}
+	err = h.svc.ChangeHandle(ctx, uc.Id, req.Handle)
+	switch {
+	case err == nil:
+		return Result{Msg: "OK"}, nil
+	case errors.Is(err, service.ErrHandleReserved), errors.Is(err, service.ErrHandleTaken):
+		return Result{Code: 4, Msg: "用户名不可用"}, nil
+	case errors.Is(err, service.ErrHandleChangeTooOften):
+		return Result{Code: 4, Msg: "用户名 30 天内只能修改一次"}, nil
+	default:
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+}
+
+// UpdateAvatar 直接使用外部图片的 URL 作为头像
+func (h *ProfileHandler) UpdateAvatar(ctx *gin.Context, req UpdateAvatarReq, uc ginx.UserClaims) (Result, error) {
+	u, err := url.Parse(req.Avatar)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Host == "" || len(req.Avatar) > 1024 {
+		return Result{Code: 4, Msg: "头像地址不合法"}, nil
+	}
+	err = h.svc.UpdateAvatar(ctx, uc.Id, req.Avatar)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Msg: "OK"}, nil
+}
+
+// UploadAvatar 上传头像，表单字段是 file
+func (h *ProfileHandler) UploadAvatar(ctx *gin.Context, uc ginx.UserClaims) (Result, error) {
+	// 多留一点给表单的其它部分
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxAvatarSize+4096)
+	fh, err := ctx.FormFile("file")
+	if err != nil {
+		return Result{Code: 4, Msg: "头像不能超过 2MB"}, nil
+	}
+	if fh.Size > maxAvatarSize {
+		return Result{Code: 4, Msg: "头像不能超过 2MB"}, nil
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	// 不相信前端传过来的 Content-Type，根据内容判断
+	ext, ok := avatarExts[http.DetectContentType(data)]
+	if !ok {
+		return Result{Code: 4, Msg: "只支持 png、jpg、gif 和 webp 格式的图片"}, nil
+	}
+	key := fmt.Sprintf("avatars/%d/%s.%s", uc.Id, uuid.New().String(), ext)
+	avatar, err := h.storage.Upload(ctx, key, data)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	err = h.svc.UpdateAvatar(ctx, uc.Id, avatar)
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: avatar}, nil
+}
+
+// PublicProfile 公开的个人主页，可以通过用户名或者 ID 访问
+func (h *ProfileHandler) PublicProfile(ctx *gin.Context, req PublicProfileReq) (Result, error) {
+	var (
+		u   domain.User
+		err error
+	)
+	switch {
+	case req.Handle != "":
+		u, err = h.svc.FindByHandle(ctx, req.Handle)
+	case req.Id > 0:
+		u, err = h.svc.Profile(ctx, req.Id)
+	default:
+		return Result{Code: 4, Msg: "参数错误"}, nil
+	}
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return Result{Code: 4, Msg: "用户不存在"}, nil
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+
+	vo := PublicProfileVo{
+		Id:       u.Id,
+		Handle:   u.Handle,
+		Nickname: u.Nickname,
+		AboutMe:  u.AboutMe,
+		Avatar:   u.Avatar,
+	}
+	var eg errgroup.Group
+	eg.Go(func() error {
+		resp, err := h.followSvc.GetFollowStatics(ctx, &followv1.GetFollowStaticsRequest{
+			Uid: u.Id,
+		})
+		if err != nil {
+			return err
+		}
+		vo.Followers = resp.GetStatics().GetFollowers()
+		vo.Followees = resp.GetStatics().GetFollowees()
+		return nil
+	})
+	eg.Go(func() error {
+		arts, err := h.artSvc.ListPubByAuthor(ctx, u.Id, 0, profileArticleCnt)
+		if err != nil {
+			return err
+		}
+		vo.Articles = slice.Map(arts, func(idx int, src domain.Article) PublicArticleVo {
+			return PublicArticleVo{
+				Id:       src.Id,
+				Title:    src.Title,
+				Abstract: src.Abstract(),
+				Utime:    src.Utime.UnixMilli(),
+			}
+		})
+		return nil
+	})
+	err = eg.Wait()
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: vo}, nil
+}
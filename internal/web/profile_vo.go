@@ -0,0 +1,35 @@
+package web
+
+type ChangeHandleReq struct {
+	Handle string `json:"handle"`
+}
+
+type UpdateAvatarReq struct {
+	// 头像的 URL，使用外部图片的时候传这个，上传图片走 /users/avatar/upload
+	Avatar string `json:"avatar"`
+}
+
+type PublicProfileReq struct {
+	// 用户名和 ID 二选一，优先使用用户名
+	Handle string `form:"handle"`
+	Id     int64  `form:"id"`
+}
+
+// PublicProfileVo 公开的个人主页，不能包含邮箱、手机号之类的隐私信息
+type PublicProfileVo struct {
+	Id        int64             `json:"id"`
+	Handle    string            `json:"handle"`
+	Nickname  string            `json:"nickname"`
+	AboutMe   string            `json:"aboutMe"`
+	Avatar    string            `json:"avatar"`
+	Followers int64             `json:"followers"`
+	Followees int64             `json:"followees"`
+	Articles  []PublicArticleVo `json:"articles"`
+}
+
+type PublicArticleVo struct {
+	Id       int64  `json:"id"`
+	Title    string `json:"title"`
+	Abstract string `json:"abstract"`
+	Utime    int64  `json:"utime"`
+}
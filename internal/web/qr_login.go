@@ -0,0 +1,145 @@
+package web
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+	"webook/pkg/logger"
+)
+
+const (
+	// qrLoginWaitTimeout 长轮询最多等待的时间，要比网关的超时时间短
+	qrLoginWaitTimeout = time.Second * 25
+)
+
+// QRLoginHandler 扫码登录
+// 桌面端创建二维码，然后长轮询二维码的状态；已经登录的移动端扫码之后确认，桌面端就登录了
+type QRLoginHandler struct {
+	svc service.QRLoginService
+	ijwt.Handler
+	l logger.Logger
+}
+
+func NewQRLoginHandler(svc service.QRLoginService, jwtHdl ijwt.Handler, l logger.Logger) *QRLoginHandler {
+	return &QRLoginHandler{
+		svc:     svc,
+		Handler: jwtHdl,
+		l:       l,
+	}
+}
+
+func (h *QRLoginHandler) RegisterRoutes(s *gin.Engine) {
+	g := s.Group("/users/qr_login")
+	// 桌面端调用，这个时候还没有登录
+	g.POST("/ticket", h.CreateTicket)
+	g.GET("/status", ginx.WrapReq[QRLoginStatusReq](h.Status))
+	// 移动端调用，必须已经登录了
+	g.POST("/scan", ginx.WrapClaimsAndReq[QRLoginTicketReq](h.Scan))
+	g.POST("/confirm", ginx.WrapClaimsAndReq[QRLoginConfirmReq](h.Confirm))
+}
+
+// CreateTicket 没有请求参数，所以不用 ginx 包装
+func (h *QRLoginHandler) CreateTicket(ctx *gin.Context) {
+	t, err := h.svc.Create(ctx, ctx.GetHeader("User-Agent"), ctx.ClientIP())
+	if err != nil {
+		h.l.Error("创建扫码登录的票据失败", logger.Error(err))
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Data: QRLoginTicketVo{
+		Ticket: t.Ticket,
+		Secret: t.Secret,
+		Status: qrLoginStatusName(t.Status),
+	}})
+}
+
+// Status 长轮询二维码的状态，状态变化了或者超时了才返回
+// 必须带上创建票据时返回的 secret，只拿到二维码里面的 ticket 是查不了的
+// 状态变成 confirmed 的时候，会在响应头里面带上登录的 token
+func (h *QRLoginHandler) Status(ctx *gin.Context, req QRLoginStatusReq) (Result, error) {
+	known := qrLoginStatusFromName(req.Status)
+	t, err := h.svc.Wait(ctx, req.Ticket, req.Secret, known, qrLoginWaitTimeout)
+	if errors.Is(err, service.ErrQRLoginInvalidSecret) {
+		return Result{Code: 4, Msg: "二维码无效，请刷新"}, err
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	if t.Status == domain.QRLoginStatusConfirmed {
+		err = h.SetLoginToken(ctx, t.Uid)
+		if err != nil {
+			return Result{Code: 5, Msg: "系统错误"}, err
+		}
+	}
+	return Result{Data: QRLoginTicketVo{
+		Ticket: t.Ticket,
+		Status: qrLoginStatusName(t.Status),
+	}}, nil
+}
+
+// Scan 移动端扫码，返回桌面端的信息，让用户确认是不是自己在登录
+func (h *QRLoginHandler) Scan(ctx *gin.Context, req QRLoginTicketReq, uc ginx.UserClaims) (Result, error) {
+	t, err := h.svc.Scan(ctx, req.Ticket, uc.Id)
+	if res, ok := h.userError(err); ok {
+		return res, nil
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	return Result{Data: QRLoginScanVo{
+		UserAgent: t.UserAgent,
+		IP:        t.IP,
+	}}, nil
+}
+
+func (h *QRLoginHandler) Confirm(ctx *gin.Context, req QRLoginConfirmReq, uc ginx.UserClaims) (Result, error) {
+	err := h.svc.Confirm(ctx, req.Ticket, uc.Id, req.Approve)
+	if res, ok := h.userError(err); ok {
+		return res, nil
+	}
+	if err != nil {
+		return Result{Code: 5, Msg: "系统错误"}, err
+	}
+	if req.Approve {
+		h.l.Info("扫码登录确认", logger.Int64("uid", uc.Id))
+		return Result{Msg: "已确认登录"}, nil
+	}
+	return Result{Msg: "已拒绝登录"}, nil
+}
+
+func (h *QRLoginHandler) userError(err error) (Result, bool) {
+	switch {
+	case errors.Is(err, service.ErrQRLoginExpired):
+		return Result{Code: 4, Msg: "二维码已经过期，请刷新"}, true
+	case errors.Is(err, service.ErrQRLoginInvalidState):
+		return Result{Code: 4, Msg: "二维码已经被使用"}, true
+	}
+	return Result{}, false
+}
+
+var qrLoginStatusNames = map[domain.QRLoginStatus]string{
+	domain.QRLoginStatusUnknown:   "expired",
+	domain.QRLoginStatusPending:   "pending",
+	domain.QRLoginStatusScanned:   "scanned",
+	domain.QRLoginStatusConfirmed: "confirmed",
+	domain.QRLoginStatusRejected:  "rejected",
+}
+
+func qrLoginStatusName(s domain.QRLoginStatus) string {
+	return qrLoginStatusNames[s]
+}
+
+// qrLoginStatusFromName 前端第一次轮询的时候可以不传，当成 pending
+func qrLoginStatusFromName(name string) domain.QRLoginStatus {
+	for s, n := range qrLoginStatusNames {
+		if n == name {
+			return s
+		}
+	}
+	return domain.QRLoginStatusPending
+}
@@ -0,0 +1,33 @@
+package web
+
+type QRLoginTicketReq struct {
+	Ticket string `json:"ticket"`
+}
+
+type QRLoginStatusReq struct {
+	Ticket string `form:"ticket"`
+	// 创建票据的时候返回的 secret
+	Secret string `form:"secret"`
+	// 前端已知的状态，状态变化了才会返回
+	Status string `form:"status"`
+}
+
+type QRLoginConfirmReq struct {
+	Ticket string `json:"ticket"`
+	// true 确认登录，false 拒绝
+	Approve bool `json:"approve"`
+}
+
+type QRLoginTicketVo struct {
+	Ticket string `json:"ticket"`
+	// Secret 只在创建票据的时候返回，轮询状态的时候要带上，不能放进二维码
+	Secret string `json:"secret,omitempty"`
+	// pending、scanned、confirmed、rejected 或者 expired
+	Status string `json:"status"`
+}
+
+// QRLoginScanVo 发起登录的桌面端的信息
+type QRLoginScanVo struct {
+	UserAgent string `json:"userAgent"`
+	IP        string `json:"ip"`
+}
@@ -0,0 +1,116 @@
+package web
+
+import (
+	"crypto/subtle"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/service/sms"
+	"webook/internal/web/middleware"
+	"webook/pkg/logger"
+)
+
+// SMSHandler 短信的状态报告回调，以及给客服查询发送记录
+type SMSHandler struct {
+	svc service.SMSRecordService
+	// parsers 服务商的名字到状态报告格式的映射，没有的服务商回调直接 404
+	parsers map[string]sms.ReceiptParser
+	// token 服务商都不对回调签名，所以在回调地址里面带上一个约定的 token
+	token string
+	l     logger.Logger
+}
+
+func NewSMSHandler(svc service.SMSRecordService, parsers map[string]sms.ReceiptParser,
+	token string, l logger.Logger) *SMSHandler {
+	return &SMSHandler{
+		svc:     svc,
+		parsers: parsers,
+		token:   token,
+		l:       l,
+	}
+}
+
+func (h *SMSHandler) RegisterRoutes(s *gin.Engine) {
+	s.POST("/sms/callback/:provider", h.Callback)
+
+	g := s.Group("/admin/sms",
+		middleware.NewRBACMiddlewareBuilder(domain.PermissionSMSRead).Build())
+	g.GET("/records", h.Records)
+}
+
+// Callback 服务商推送的状态报告，处理失败的时候返回 5xx，服务商会重新推送
+func (h *SMSHandler) Callback(ctx *gin.Context) {
+	provider := ctx.Param("provider")
+	parser, ok := h.parsers[provider]
+	if !ok {
+		ctx.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	if h.token == "" || subtle.ConstantTimeCompare([]byte(ctx.Query("token")), []byte(h.token)) != 1 {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, 1<<20))
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	receipts, err := parser.Parse(body)
+	if err != nil {
+		h.l.Warn("解析短信状态报告失败", logger.String("provider", provider), logger.Error(err))
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	err = h.svc.HandleReceipts(ctx, provider, receipts)
+	if err != nil {
+		h.l.Error("处理短信状态报告失败", logger.String("provider", provider), logger.Error(err))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	ctx.JSON(http.StatusOK, parser.Ack())
+}
+
+// Records 某个手机号的短信发送记录，客服排查用户收不到验证码的问题
+func (h *SMSHandler) Records(ctx *gin.Context) {
+	var req AdminSMSRecordReq
+	if err := ctx.BindQuery(&req); err != nil {
+		return
+	}
+	// 客服输入的一般是不带区号的号码
+	phone, ok := normalizePhone(ctx, req.Phone)
+	if !ok {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	rs, err := h.svc.ListByPhone(ctx, phone, req.Offset, req.Limit)
+	if err != nil {
+		h.l.Error("查询短信发送记录失败", logger.Error(err))
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Data: slice.Map(rs, func(idx int, src domain.SMSRecord) SMSRecordVo {
+		vo := SMSRecordVo{
+			Id:         src.Id,
+			Provider:   src.Provider,
+			TplId:      src.TplId,
+			Number:     src.Number,
+			MessageId:  src.MessageId,
+			LatencyMs:  src.Latency.Milliseconds(),
+			Status:     src.Status.String(),
+			Err:        src.Err,
+			ReportCode: src.ReportCode,
+			ReportMsg:  src.ReportMsg,
+			Ctime:      src.Ctime.Format(time.DateTime),
+		}
+		if !src.ReportTime.IsZero() {
+			vo.ReportTime = src.ReportTime.Format(time.DateTime)
+		}
+		return vo
+	})})
+}
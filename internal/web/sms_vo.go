@@ -0,0 +1,24 @@
+package web
+
+type AdminSMSRecordReq struct {
+	Phone  string `form:"phone"`
+	Offset int    `form:"offset"`
+	Limit  int    `form:"limit"`
+}
+
+// SMSRecordVo 号码是打码之后的
+type SMSRecordVo struct {
+	Id        int64  `json:"id"`
+	Provider  string `json:"provider"`
+	TplId     string `json:"tplId"`
+	Number    string `json:"number"`
+	MessageId string `json:"messageId"`
+	LatencyMs int64  `json:"latencyMs"`
+	// Status failed、sent、delivered 或者 undelivered
+	Status     string `json:"status"`
+	Err        string `json:"err"`
+	ReportCode string `json:"reportCode"`
+	ReportMsg  string `json:"reportMsg"`
+	ReportTime string `json:"reportTime"`
+	Ctime      string `json:"ctime"`
+}
@@ -4,13 +4,17 @@ import (
 	"errors"
 	regexp "github.com/dlclark/regexp2"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"net/http"
 	"time"
 	"webook/internal/domain"
 	"webook/internal/service"
+	"webook/internal/service/password"
 	ijwt "webook/internal/web/jwt"
+	"webook/internal/web/middleware"
+	"webook/pkg/logger"
+	"webook/pkg/phonex"
+	"webook/pkg/ratelimit"
 )
 
 const (
@@ -22,6 +26,10 @@ const (
 
 	// 用于验证码登录
 	bizLogin = "login"
+	// 用于绑定或者更换手机号
+	bizBindPhone = "bind_phone"
+	// 用于没有密码的账号第一次设置密码
+	bizSetPassword = "set_password"
 )
 
 //  var _ handler = &UserHandler{}
@@ -32,18 +40,23 @@ type UserHandler struct {
 	codeSvc          service.CodeService // 引用service层的CodeService，处理短信服务
 	emailRegexExp    *regexp.Regexp      // 用于邮箱格式验证的正则表达式对象
 	passwordRegexExp *regexp.Regexp      // 用于密码格式验证的正则表达式对象
+	captcha          gin.HandlerFunc     // 超过软阈值之后要求先通过图形验证码，防止机器批量注册和撞库
 
 	ijwt.Handler // 用于 JWT 鉴权登录
 }
 
 // NewUserHandler 构造函数，创建并返回一个新的UserHandler实例
 // 接收一个service.UserService对象，用于处理注册、登录等请求
-func NewUserHandler(svc service.UserService, codeSvc service.CodeService, jwthdl ijwt.Handler) *UserHandler {
+// captchaLimiter 是注册和密码登录的图形验证码软阈值，为 nil 的话每次都要图形验证码
+func NewUserHandler(svc service.UserService, codeSvc service.CodeService,
+	captchaSvc service.CaptchaService, captchaLimiter ratelimit.Limiter,
+	jwthdl ijwt.Handler, l logger.Logger) *UserHandler {
 	return &UserHandler{
 		svc:              svc,
 		codeSvc:          codeSvc,
 		emailRegexExp:    regexp.MustCompile(emailRegexPattern, regexp.None),    // 编译邮箱格式正则
 		passwordRegexExp: regexp.MustCompile(passwordRegexPattern, regexp.None), // 编译密码格式正则
+		captcha:          middleware.NewCaptchaMiddlewareBuilder(captchaSvc, l).SoftLimit(captchaLimiter).Build(),
 		Handler:          jwthdl,
 	}
 }
@@ -52,8 +65,9 @@ func NewUserHandler(svc service.UserService, codeSvc service.CodeService, jwthdl
 func (c *UserHandler) RegisterRoutes(server *gin.Engine) {
 	// 定义/users相关的路由组
 	ug := server.Group("/users")
-	ug.POST("/signup", c.SignUp) // 用户注册
-	ug.POST("/login", c.Login)   // 用户登录
+	// 注册和密码登录，同一个 IP 的次数超过软阈值之后要先通过图形验证码，凭证放在 X-Captcha-Token 里面
+	ug.POST("/signup", c.captcha, c.SignUp) // 用户注册
+	ug.POST("/login", c.captcha, c.Login)   // 用户登录
 	ug.POST("/logout", c.Logout)
 	ug.POST("/edit", c.Edit)      // 用户信息编辑
 	ug.GET("/profile", c.Profile) // 获取用户信息
@@ -61,24 +75,26 @@ func (c *UserHandler) RegisterRoutes(server *gin.Engine) {
 	ug.POST("/login_sms/code/send", c.SendSMSLoginCode)
 	ug.POST("/login_sms", c.LoginSMS)
 	ug.POST("/refresh_token", c.RefreshToken)
+
+	// 修改敏感信息，都需要重新验证
+	ug.POST("/password/change", c.ChangePassword)
+	ug.POST("/password/code/send", c.SendSetPasswordCode)
+	ug.POST("/password/set", c.SetPassword)
+	ug.POST("/phone/code/send", c.SendBindPhoneCode)
+	ug.POST("/phone/bind", c.BindPhone)
+	ug.POST("/email/change", c.ChangeEmail)
+	ug.GET("/email/confirm", c.ConfirmEmail)
 }
 
 func (c *UserHandler) RefreshToken(ctx *gin.Context) {
 	// 假定长 token 也放在这里
 	tokenStr := c.ExtractTokenString(ctx)
-	var rc ijwt.RefreshClaims
-	token, err := jwt.ParseWithClaims(tokenStr, &rc, func(token *jwt.Token) (interface{}, error) {
-		return ijwt.RefreshTokenKey, nil
-	})
+	rc, err := c.ParseRefreshToken(tokenStr)
 	// 这边要保持和登录校验一直的逻辑，即返回 401 响应
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, Result{Code: 4, Msg: "请登录"})
 		return
 	}
-	if token == nil || !token.Valid {
-		ctx.JSON(http.StatusUnauthorized, Result{Code: 4, Msg: "请登录"})
-		return
-	}
 
 	err = c.CheckSession(ctx, rc.Ssid)
 	if err != nil {
@@ -86,7 +102,8 @@ func (c *UserHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	err = c.SetJWTToken(ctx, rc.Ssid, rc.Id)
+	// 同时换发新的长 token，旧的长 token 再被使用的话，整个会话都会失效
+	err = c.RotateRefreshToken(ctx, rc)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, Result{Code: 4, Msg: "请登录"})
 		return
@@ -99,12 +116,18 @@ func (c *UserHandler) LoginSMS(ctx *gin.Context) {
 	type Req struct {
 		Phone string `json:"phone"`
 		Code  string `json:"code"`
+		// Channel 验证码是怎么收到的，和发送的时候一样
+		Channel string `json:"channel"`
 	}
 	var req Req
 	if err := ctx.Bind(&req); err != nil {
 		return
 	}
-	ok, err := c.codeSvc.Verify(ctx, bizLogin, req.Phone, req.Code)
+	phone, ok := normalizePhone(ctx, req.Phone)
+	if !ok {
+		return
+	}
+	ok, err := c.codeSvc.Verify(ctx, bizLogin, phoneCodeTarget(req.Channel, phone), req.Code)
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统异常"})
 		return
@@ -116,7 +139,11 @@ func (c *UserHandler) LoginSMS(ctx *gin.Context) {
 
 	// 验证码是对的
 	// 登录或者注册用户
-	u, err := c.svc.FindOrCreate(ctx, req.Phone)
+	u, err := c.svc.FindOrCreate(ctx, phone)
+	if errors.Is(err, service.ErrUserBanned) {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "账号已被封禁"})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "系统错误"})
 		return
@@ -131,26 +158,41 @@ func (c *UserHandler) LoginSMS(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, Result{Msg: "登录成功"})
 }
 
-// SendSMSLoginCode 发送短信验证码
+// SendSMSLoginCode 发送短信验证码，收不到短信的时候也可以选择语音验证码
 func (c *UserHandler) SendSMSLoginCode(ctx *gin.Context) {
 	type Req struct {
 		Phone string `json:"phone"`
+		// Channel sms 或者 voice，不传就是 sms
+		Channel string `json:"channel"`
+		// CaptchaToken 发送次数太多的时候，需要先通过图形验证码
+		CaptchaToken string `json:"captchaToken"`
 	}
 	var req Req
 	if err := ctx.Bind(&req); err != nil {
 		return
 	}
-	// 你也可以用正则表达式校验是不是合法的手机号
-	if req.Phone == "" {
-		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "请输入手机号码"})
+	phone, ok := normalizePhone(ctx, req.Phone)
+	if !ok {
 		return
 	}
-	err := c.codeSvc.Send(ctx, bizLogin, req.Phone)
+	err := c.codeSvc.Send(ctx, bizLogin, phoneCodeTarget(req.Channel, phone), codeClient(ctx, req.CaptchaToken))
 	switch {
 	case err == nil:
 		ctx.JSON(http.StatusOK, Result{Msg: "发送成功"})
+	case errors.Is(err, service.ErrCodeSendDeferred):
+		// 验证码依旧有效，只是短信会晚一点到
+		ctx.JSON(http.StatusOK, Result{Msg: "短信发送有延迟，请稍候查收"})
 	case errors.Is(err, service.ErrCodeSendTooMany):
 		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "短信发送太频繁，请稍后再试"})
+	case errors.Is(err, service.ErrCodeSendLimited):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "短信发送次数太多，请明天再试"})
+	case errors.Is(err, service.ErrCaptchaRequired):
+		// 前端看到这个错误码之后弹出图形验证码，通过之后带上凭证重新发送
+		ctx.JSON(http.StatusOK, Result{Code: 6, Msg: "请先完成图形验证码"})
+	case errors.Is(err, service.ErrCodeChannelUnsupported):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "不支持的验证码发送方式"})
+	case errors.Is(err, service.ErrCodeUnsupportedRegion):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "暂不支持该国家或地区的手机号"})
 	default:
 		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
 		// 要打印日志
@@ -158,6 +200,42 @@ func (c *UserHandler) SendSMSLoginCode(ctx *gin.Context) {
 	}
 }
 
+// normalizePhone 把用户输入的手机号转成 E.164 格式，不带国际区号的当成国内的号码
+// 格式不对的时候直接返回错误信息给前端
+func normalizePhone(ctx *gin.Context, raw string) (string, bool) {
+	if raw == "" {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "请输入手机号码"})
+		return "", false
+	}
+	phone, err := phonex.Normalize(raw, domain.DefaultPhoneRegion)
+	switch {
+	case err == nil:
+		return phone, true
+	case errors.Is(err, phonex.ErrUnknownRegion):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "暂不支持该国家或地区的手机号"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "手机号码格式不正确"})
+	}
+	return "", false
+}
+
+// phoneCodeTarget 发到手机号的验证码，channel 为空的时候是短信
+func phoneCodeTarget(channel string, phone string) domain.CodeTarget {
+	if channel == "" {
+		channel = string(domain.CodeChannelSMS)
+	}
+	return domain.CodeTarget{Channel: domain.CodeChannel(channel), Address: phone}
+}
+
+// codeClient 发送验证码的客户端，设备标识由前端生成之后放在 X-Device-Id 里面
+func codeClient(ctx *gin.Context, captchaToken string) domain.CodeClient {
+	return domain.CodeClient{
+		IP:           ctx.ClientIP(),
+		Device:       ctx.GetHeader("X-Device-Id"),
+		CaptchaToken: captchaToken,
+	}
+}
+
 // SignUp 用户注册接口
 // 处理用户提交的注册信息，并进行验证，最后调用service层完成注册操作
 func (c *UserHandler) SignUp(ctx *gin.Context) {
@@ -218,6 +296,11 @@ func (c *UserHandler) SignUp(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "重复邮箱，请换一个邮箱")
 		return
 	}
+	// 密码太常见之类的，直接把原因告诉用户
+	if password.IsPolicyError(err) {
+		ctx.String(http.StatusOK, err.Error())
+		return
+	}
 	if err != nil {
 		// 如果发生其他错误，返回服务器异常提示
 		ctx.String(http.StatusOK, "服务器异常，注册失败")
@@ -252,6 +335,14 @@ func (c *UserHandler) Login(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "用户名或者密码不正确，请重试")
 		return
 	}
+	if errors.Is(err, service.ErrUserBanned) {
+		ctx.String(http.StatusOK, "账号已被封禁")
+		return
+	}
+	if err != nil {
+		ctx.String(http.StatusOK, "系统错误")
+		return
+	}
 
 	err = c.SetLoginToken(ctx, u.Id)
 	if err != nil {
@@ -326,6 +417,8 @@ func (c *UserHandler) Profile(ctx *gin.Context) {
 		Nickname string
 		Birthday string
 		AboutMe  string
+		Handle   string
+		Avatar   string
 	}
 
 	// 从上下文中获取JWT中的用户信息（UserClaims），通过ctx.MustGet("user")来获取
@@ -347,5 +440,301 @@ func (c *UserHandler) Profile(ctx *gin.Context) {
 		Nickname: u.Nickname,
 		Birthday: u.Birthday.Format(time.DateOnly),
 		AboutMe:  u.AboutMe,
+		Handle:   u.Handle,
+		Avatar:   u.Avatar,
 	})
 }
+
+// ChangePassword 修改密码
+// 修改成功之后，除了当前会话，其余的登录会话都会失效
+func (c *UserHandler) ChangePassword(ctx *gin.Context) {
+	type Req struct {
+		OldPassword     string `json:"oldPassword"`
+		NewPassword     string `json:"newPassword"`
+		ConfirmPassword string `json:"confirmPassword"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.NewPassword != req.ConfirmPassword {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "两次输入的密码不相同"})
+		return
+	}
+	isPassword, err := c.passwordRegexExp.MatchString(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if !isPassword {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "密码必须包含数字、特殊字符，并且长度不能小于 8 位"})
+		return
+	}
+	uc := ctx.MustGet("user").(ijwt.UserClaims)
+	err = c.svc.ChangePassword(ctx, uc.Id, req.OldPassword, req.NewPassword)
+	switch {
+	case err == nil:
+	case errors.Is(err, service.ErrIncorrectOldPassword):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "原密码不正确"})
+		return
+	case errors.Is(err, service.ErrPasswordNotSet):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "还没有设置过密码，请先通过验证码设置"})
+		return
+	case password.IsPolicyError(err):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: err.Error()})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if err = c.RevokeSessions(ctx, uc.Id, uc.Ssid); err != nil {
+		// 密码已经改好了，这里只是其它会话没能下线
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "密码已修改，但其它设备下线失败"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
+
+// setPasswordCodeTarget 设置密码的验证码发到账号已经绑定的手机号，没有手机号的发到邮箱
+func setPasswordCodeTarget(u domain.User) (domain.CodeTarget, bool) {
+	switch {
+	case u.Phone != "":
+		return phoneCodeTarget("", u.Phone), true
+	case u.Email != "":
+		return domain.CodeTarget{Channel: domain.CodeChannelEmail, Address: u.Email}, true
+	default:
+		return domain.CodeTarget{}, false
+	}
+}
+
+// SendSetPasswordCode 没有密码的账号设置密码之前，先给账号绑定的手机号或者邮箱发送验证码
+func (c *UserHandler) SendSetPasswordCode(ctx *gin.Context) {
+	uc := ctx.MustGet("user").(ijwt.UserClaims)
+	u, err := c.svc.Profile(ctx, uc.Id)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if u.Password != "" {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "已经设置过密码，请使用原密码修改"})
+		return
+	}
+	target, ok := setPasswordCodeTarget(u)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "账号没有绑定手机号或者邮箱"})
+		return
+	}
+	err = c.codeSvc.Send(ctx, bizSetPassword, target, codeClient(ctx, ""))
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, Result{Msg: "发送成功"})
+	case errors.Is(err, service.ErrCodeSendDeferred):
+		ctx.JSON(http.StatusOK, Result{Msg: "短信发送有延迟，请稍候查收"})
+	case errors.Is(err, service.ErrCodeSendTooMany):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码发送太频繁，请稍后再试"})
+	case errors.Is(err, service.ErrCodeSendLimited), errors.Is(err, service.ErrCaptchaRequired):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码发送次数太多，请稍后再试"})
+	case errors.Is(err, service.ErrCodeUnsupportedRegion):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "暂不支持该国家或地区的手机号"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// SetPassword 没有密码的账号第一次设置密码，必须先校验验证码
+// 设置成功之后，除了当前会话，其余的登录会话都会失效
+func (c *UserHandler) SetPassword(ctx *gin.Context) {
+	type Req struct {
+		Code            string `json:"code"`
+		NewPassword     string `json:"newPassword"`
+		ConfirmPassword string `json:"confirmPassword"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.NewPassword != req.ConfirmPassword {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "两次输入的密码不相同"})
+		return
+	}
+	isPassword, err := c.passwordRegexExp.MatchString(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if !isPassword {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "密码必须包含数字、特殊字符，并且长度不能小于 8 位"})
+		return
+	}
+	uc := ctx.MustGet("user").(ijwt.UserClaims)
+	u, err := c.svc.Profile(ctx, uc.Id)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	target, ok := setPasswordCodeTarget(u)
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "账号没有绑定手机号或者邮箱"})
+		return
+	}
+	ok, err = c.codeSvc.Verify(ctx, bizSetPassword, target, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统异常"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码错误"})
+		return
+	}
+	err = c.svc.SetPassword(ctx, uc.Id, req.NewPassword)
+	switch {
+	case err == nil:
+	case errors.Is(err, service.ErrPasswordAlreadySet):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "已经设置过密码，请使用原密码修改"})
+		return
+	case password.IsPolicyError(err):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: err.Error()})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if err = c.RevokeSessions(ctx, uc.Id, uc.Ssid); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "密码已设置，但其它设备下线失败"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
+
+// SendBindPhoneCode 给需要绑定的新手机号发送验证码
+func (c *UserHandler) SendBindPhoneCode(ctx *gin.Context) {
+	type Req struct {
+		Phone string `json:"phone"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	phone, ok := normalizePhone(ctx, req.Phone)
+	if !ok {
+		return
+	}
+	// 已经登录了，所以配置上只有硬限制，不需要图形验证码
+	err := c.codeSvc.Send(ctx, bizBindPhone, phoneCodeTarget("", phone), codeClient(ctx, ""))
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, Result{Msg: "发送成功"})
+	case errors.Is(err, service.ErrCodeSendDeferred):
+		// 验证码依旧有效，只是短信会晚一点到
+		ctx.JSON(http.StatusOK, Result{Msg: "短信发送有延迟，请稍候查收"})
+	case errors.Is(err, service.ErrCodeSendTooMany):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "短信发送太频繁，请稍后再试"})
+	case errors.Is(err, service.ErrCodeSendLimited), errors.Is(err, service.ErrCaptchaRequired):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "短信发送次数太多，请稍后再试"})
+	case errors.Is(err, service.ErrCodeUnsupportedRegion):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "暂不支持该国家或地区的手机号"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// BindPhone 校验新手机号的验证码，然后绑定到当前用户
+// 绑定成功之后，除了当前会话，其余的登录会话都会失效
+func (c *UserHandler) BindPhone(ctx *gin.Context) {
+	type Req struct {
+		Phone string `json:"phone"`
+		Code  string `json:"code"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	phone, ok := normalizePhone(ctx, req.Phone)
+	if !ok {
+		return
+	}
+	ok, err := c.codeSvc.Verify(ctx, bizBindPhone, phoneCodeTarget("", phone), req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统异常"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "验证码错误"})
+		return
+	}
+	uc := ctx.MustGet("user").(ijwt.UserClaims)
+	err = c.svc.BindPhone(ctx, uc.Id, phone)
+	switch {
+	case err == nil:
+	case errors.Is(err, service.ErrUserDuplicatePhone):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "该手机号已经被其它账号使用"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if err = c.RevokeSessions(ctx, uc.Id, uc.Ssid); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "手机号已绑定，但其它设备下线失败"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "OK"})
+}
+
+// ChangeEmail 修改邮箱，会往新邮箱发送一个确认链接
+// 用户点击确认链接之后，邮箱才会真的被修改
+func (c *UserHandler) ChangeEmail(ctx *gin.Context) {
+	type Req struct {
+		Email string `json:"email"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	isEmail, err := c.emailRegexExp.MatchString(req.Email)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if !isEmail {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "邮箱不正确"})
+		return
+	}
+	uc := ctx.MustGet("user").(ijwt.UserClaims)
+	err = c.svc.SendChangeEmailLink(ctx, uc.Id, req.Email)
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, Result{Msg: "确认邮件已发送，请前往新邮箱确认"})
+	case errors.Is(err, service.ErrUserDuplicateEmail):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "该邮箱已经被其它账号使用"})
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+	}
+}
+
+// ConfirmEmail 用户点击邮件中的确认链接
+// 这个接口不需要登录，凭令牌确认身份。确认成功之后，该用户所有的登录会话都会失效
+func (c *UserHandler) ConfirmEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "确认链接无效"})
+		return
+	}
+	uid, err := c.svc.ConfirmChangeEmail(ctx, token)
+	switch {
+	case err == nil:
+	case errors.Is(err, service.ErrInvalidEmailToken):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "确认链接无效或者已经过期"})
+		return
+	case errors.Is(err, service.ErrUserDuplicateEmail):
+		ctx.JSON(http.StatusOK, Result{Code: 4, Msg: "该邮箱已经被其它账号使用"})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "系统错误"})
+		return
+	}
+	if err = c.RevokeSessions(ctx, uid, ""); err != nil {
+		ctx.JSON(http.StatusOK, Result{Code: 5, Msg: "邮箱已修改，但其它设备下线失败"})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{Msg: "邮箱修改成功，请重新登录"})
+}
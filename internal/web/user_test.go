@@ -7,11 +7,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"webook/internal/service"
 	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
 )
 
 func TestUserHandler_SignUp(t *testing.T) {
@@ -187,8 +189,11 @@ func TestUserHandler_SignUp(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			usersvc, codesvc := tc.mock(ctrl)
+			// 没有软阈值，注册每次都要先通过图形验证码，这里都当作已经通过了
+			captchaSvc := svcmocks.NewMockCaptchaService(ctrl)
+			captchaSvc.EXPECT().VerifyPass(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
 			// 利用 mock 来构造 UserHandler
-			hdl := NewUserHandler(usersvc, codesvc)
+			hdl := NewUserHandler(usersvc, codesvc, captchaSvc, nil, nil, logger.NewZapLogger(zap.NewNop()))
 
 			// 注册路由
 			server := gin.Default()
@@ -230,7 +235,7 @@ func TestEmailPattern(t *testing.T) {
 		},
 	}
 
-	h := NewUserHandler(nil, nil)
+	h := NewUserHandler(nil, nil, nil, nil, nil, nil)
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -269,7 +274,7 @@ func TestPasswordPattern(t *testing.T) {
 		},
 	}
 
-	h := NewUserHandler(nil, nil)
+	h := NewUserHandler(nil, nil, nil, nil, nil, nil)
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
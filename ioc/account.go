@@ -0,0 +1,30 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	"webook/internal/repository"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+func InitAccountService(repo repository.AccountRepository,
+	userRepo repository.UserRepository,
+	artRepo repository.ArticleRepository,
+	intrSvc intrv1.InteractiveServiceClient,
+	jwtHdl ijwt.Handler,
+	l logger.Logger) service.AccountService {
+	type Config struct {
+		// Dir 导出的个人数据存放的目录
+		Dir string `yaml:"dir"`
+	}
+	cfg := Config{
+		Dir: "/tmp/webook/exports",
+	}
+	err := viper.UnmarshalKey("export", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return service.NewAccountService(repo, userRepo, artRepo, intrSvc, jwtHdl, l, cfg.Dir)
+}
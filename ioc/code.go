@@ -0,0 +1,38 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/service/email"
+	"webook/internal/service/sms"
+	"webook/internal/service/sms/memory"
+	"webook/pkg/logger"
+)
+
+// InitCodeService 从配置 code.biz 加载每种业务的验证码配置，code.hashKey 是计算验证码哈希值的密钥
+// 短信和邮件渠道一直都有，语音渠道目前只有假的短信服务支持，
+// 接入了真实的语音服务商之后再在这里加上
+func InitCodeService(smsSvc sms.Service, emailSvc email.Service, inbox *memory.Service,
+	repo repository.CodeRepository, limitRepo repository.CodeLimitRepository,
+	captcha service.CaptchaService, l logger.Logger) service.CodeService {
+	var configs map[string]service.CodeConfig
+	err := viper.UnmarshalKey("code.biz", &configs)
+	if err != nil {
+		panic(err)
+	}
+	hashKey := viper.GetString("code.hashKey")
+	if hashKey == "" {
+		panic("没有配置 code.hashKey")
+	}
+	senders := map[domain.CodeChannel]service.CodeSender{
+		domain.CodeChannelSMS:   service.NewSMSCodeSender(smsSvc),
+		domain.CodeChannelEmail: service.NewEmailCodeSender(emailSvc),
+	}
+	if inbox != nil {
+		// 语音验证码也放到假的短信服务里面，可以在 /dev/sms 看到
+		senders[domain.CodeChannelVoice] = service.NewVoiceCodeSender(inbox)
+	}
+	return service.NewCodeService(senders, repo, limitRepo, captcha, configs, []byte(hashKey), l)
+}
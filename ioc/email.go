@@ -0,0 +1,29 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"webook/internal/service/email"
+	"webook/internal/service/email/local"
+	"webook/internal/service/email/smtp"
+	"webook/pkg/logger"
+)
+
+func InitEmailService(l logger.Logger) email.Service {
+	type Config struct {
+		Host     string `yaml:"host"`
+		Port     int    `yaml:"port"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+		From     string `yaml:"from"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("email.smtp", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	// 没有配置 SMTP 的时候，例如本地开发，邮件内容只打印到日志
+	if cfg.Host == "" {
+		return local.NewService(l)
+	}
+	return smtp.NewService(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From)
+}
@@ -4,16 +4,27 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
 	"strings"
 	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
 	"webook/internal/web"
 	ijwt "webook/internal/web/jwt"
 	"webook/internal/web/middleware"
+	"webook/pkg/ginx"
 	"webook/pkg/ginx/middleware/metrics"
 	"webook/pkg/logger"
 )
 
-func InitWebServer(funcs []gin.HandlerFunc, userHdl *web.UserHandler, artHdl *web.ArticleHandler) *gin.Engine {
+func InitWebServer(funcs []gin.HandlerFunc, userHdl *web.UserHandler, artHdl *web.ArticleHandler,
+	followHdl *web.FollowHandler, feedHdl *web.FeedHandler, accountHdl *web.AccountHandler,
+	profileHdl *web.ProfileHandler, adminHdl *web.AdminHandler, jwksHdl *web.JWKSHandler,
+	patHdl *web.PATHandler, qrLoginHdl *web.QRLoginHandler, magicLinkHdl *web.MagicLinkHandler,
+	devSMSHdl *web.DevSMSHandler, captchaHdl *web.CaptchaHandler, smsHdl *web.SMSHandler,
+	l logger.Logger) *gin.Engine {
+	// ginx 中包装的 handler 需要用这个打日志
+	ginx.SetLogger(l)
 	server := gin.Default() // 初始化一个默认的 Gin 引擎实例
 	gin.ForceConsoleColor() // 强制开启控制台的彩色输出
 
@@ -23,11 +34,73 @@ func InitWebServer(funcs []gin.HandlerFunc, userHdl *web.UserHandler, artHdl *we
 	// 注册用户相关的路由
 	userHdl.RegisterRoutes(server)
 	artHdl.RegisterRoutes(server)
+	followHdl.RegisterRoutes(server)
+	feedHdl.RegisterRoutes(server)
+	accountHdl.RegisterRoutes(server)
+	profileHdl.RegisterRoutes(server)
+	adminHdl.RegisterRoutes(server)
+	jwksHdl.RegisterRoutes(server)
+	patHdl.RegisterRoutes(server)
+	qrLoginHdl.RegisterRoutes(server)
+	magicLinkHdl.RegisterRoutes(server)
+	devSMSHdl.RegisterRoutes(server)
+	captchaHdl.RegisterRoutes(server)
+	smsHdl.RegisterRoutes(server)
+
+	// 本地存储的文件，例如头像。线上应该交给 CDN 或者 nginx
+	if dir := viper.GetString("storage.local.dir"); dir != "" {
+		server.Static("/static", dir)
+	}
 
 	return server // 返回配置好的 Gin 引擎实例
 }
 
-func GinMiddlewares(cmd redis.Cmdable, hdl ijwt.Handler, l logger.Logger) []gin.HandlerFunc {
+// InitAuthPolicies 声明路由的登录校验策略，路径是注册路由时候的完整路径，也就是 gin 的 FullPath
+// 没有声明的路由都必须登录
+func InitAuthPolicies() *middleware.AuthPolicies {
+	return middleware.NewAuthPolicies().
+		// 注册、登录、刷新 token 这些本来就是还没登录的时候调用的
+		None("/users/signup",
+			"/users/login_sms/code/send",
+			"/users/login_sms",
+			"/users/login",
+			"/users/refresh_token").
+		// 修改邮箱的确认链接是在邮件里面点开的，凭令牌确认身份
+		None("/users/email/confirm").
+		// 公开的个人主页
+		None("/users/public/profile").
+		// 扫码登录的桌面端，这个时候还没有登录
+		None("/users/qr_login/ticket", "/users/qr_login/status").
+		// 登录链接
+		None("/users/login_link/send", "/users/login_link/confirm").
+		// 别的服务获取验证 token 的公钥
+		None("/.well-known/jwks.json").
+		// 本地存储的静态文件
+		None("/static/*filepath").
+		// 图形验证码，注册、登录之前就要用
+		None("/captcha/generate", "/captcha/verify").
+		// 开发环境查看假的短信服务发出去的短信
+		None("/dev/sms").
+		// 短信服务商推送状态报告，凭回调地址里面的 token 校验
+		None("/sms/callback/:provider").
+		// 游客也可以看文章，登录了的话会带上点赞收藏的状态
+		Optional("/articles/pub/:id").
+		// 个人访问令牌可以访问的接口，别的接口，特别是管理令牌本身的接口，只能用登录的 JWT 访问
+		Scope(domain.ScopeUserRead, "/users/profile").
+		Scope(domain.ScopeArticleRead,
+			"/articles/list",
+			"/articles/detail/:id",
+			"/articles/pub/:id",
+			"/articles/pub/ranking").
+		Scope(domain.ScopeArticleWrite,
+			"/articles/edit",
+			"/articles/publish",
+			"/articles/withdraw").
+		Scope(domain.ScopeFeedRead, "/feed/following")
+}
+
+func GinMiddlewares(cmd redis.Cmdable, hdl ijwt.Handler, policies *middleware.AuthPolicies,
+	patSvc service.PATService, l logger.Logger) []gin.HandlerFunc {
 
 	pb := &metrics.PrometheusBuilder{
 		Namespace:  "webook_server",
@@ -49,7 +122,11 @@ func GinMiddlewares(cmd redis.Cmdable, hdl ijwt.Handler, l logger.Logger) []gin.
 		pb.BuildActiveRequest(),
 
 		// 使用 JWT 中间件
-		middleware.NewJWTLoginMiddlewareBuilder(hdl).Build(),
+		// 短 token 快要过期的时候自动续期
+		// 也可以使用个人访问令牌
+		middleware.NewJWTLoginMiddlewareBuilder(hdl, policies, l).
+			RenewWithin(viper.GetDuration("jwt.renewThreshold")).
+			WithPAT(patSvc).Build(),
 
 		// 访问日志中间件
 		//accesslog.NewMiddlewareBuilder(func(ctx context.Context, al accesslog.AccessLog) {
@@ -64,9 +141,9 @@ func GinMiddlewares(cmd redis.Cmdable, hdl ijwt.Handler, l logger.Logger) []gin.
 
 func corsHandler() gin.HandlerFunc {
 	return cors.New(cors.Config{
-		AllowCredentials: true,                                       // 允许客户端发送认证信息
-		AllowHeaders:     []string{"Content-Type", "Authorization"},  // 允许的请求头
-		ExposeHeaders:    []string{"X-Jwt-Token", "X-Refresh-Token"}, // 暴露的响应头
+		AllowCredentials: true,                                                                               // 允许客户端发送认证信息
+		AllowHeaders:     []string{"Content-Type", "Authorization", "X-Device-Id", middleware.CaptchaHeader}, // 允许的请求头
+		ExposeHeaders:    []string{"X-Jwt-Token", "X-Refresh-Token"},                                         // 暴露的响应头
 		AllowOriginFunc: func(origin string) bool {
 			// 允许来自 localhost 和指定公司域名的请求
 			if strings.HasPrefix(origin, "http://localhost") {
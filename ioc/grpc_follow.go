@@ -0,0 +1,29 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	followv1 "webook/api/proto/gen/follow/v1"
+)
+
+func InitFollowGRPCClient() followv1.FollowServiceClient {
+	type Config struct {
+		Addr   string
+		Secure bool
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("grpc.client.follow", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	var opts []grpc.DialOption
+	if !cfg.Secure {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	cc, err := grpc.Dial(cfg.Addr, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return followv1.NewFollowServiceClient(cc)
+}
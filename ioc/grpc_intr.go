@@ -5,13 +5,18 @@ import (
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	followv1 "webook/api/proto/gen/follow/v1"
 	intrv1 "webook/api/proto/gen/intr/v1"
 	"webook/interactive/service"
+	service2 "webook/internal/service"
 	"webook/internal/web/client"
 	"webook/pkg/logger"
 )
 
-func InitIntrGRPCClient(svc service.InteractiveService, l logger.Logger) intrv1.InteractiveServiceClient {
+// InitIntrGRPCClient 按照 Threshold 的比例在本地和 gRPC 之间切换，
+// 最外面套一层拉黑的检查，所有点赞、收藏文章的调用者都会检查
+func InitIntrGRPCClient(svc service.InteractiveService, artSvc service2.ArticleService,
+	followSvc followv1.FollowServiceClient, l logger.Logger) intrv1.InteractiveServiceClient {
 	type Config struct {
 		Addr      string
 		Secure    bool
@@ -46,5 +51,5 @@ func InitIntrGRPCClient(svc service.InteractiveService, l logger.Logger) intrv1.
 		// 这边更新 Threshold
 		res.UpdateThreshold(cfg.Threshold)
 	})
-	return res
+	return client.NewBlockCheckInteractiveClient(res, artSvc, followSvc)
 }
@@ -1,8 +1,11 @@
 package ioc
 
 import (
+	"context"
+	"webook/internal/domain"
 	"webook/internal/job"
 	"webook/internal/service"
+	"webook/internal/service/sms/async"
 	"webook/pkg/logger"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,3 +31,41 @@ func InitJobs(l logger.Logger, rankingJob *job.RankingJob) *cron.Cron {
 	}
 	return expr
 }
+
+// InitScheduler 基于 MySQL 的分布式任务调度，多个实例之间只会有一个实例执行同一个任务
+func InitScheduler(l logger.Logger, svc service.CronJobService,
+	accountSvc service.AccountService, smsSvc *async.Service) *job.Scheduler {
+	res := job.NewScheduler(svc, l)
+	exec := job.NewLocalFuncExecutor()
+	exec.AddLocalFunc("user_data_export", func(ctx context.Context, j domain.CronJob) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute*10)
+		defer cancel()
+		return accountSvc.ExecutePendingExports(ctx)
+	})
+	exec.AddLocalFunc("account_deletion", func(ctx context.Context, j domain.CronJob) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute*30)
+		defer cancel()
+		return accountSvc.ExecuteDueDeletions(ctx)
+	})
+	exec.AddLocalFunc("async_sms_retry", func(ctx context.Context, j domain.CronJob) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		return smsSvc.SendPending(ctx)
+	})
+	res.RegisterExecutor(exec)
+
+	jobs := []domain.CronJob{
+		{Name: "user_data_export", Executor: exec.Name(), Expression: "0 * * * * *"},
+		{Name: "account_deletion", Executor: exec.Name(), Expression: "0 0 * * * *"},
+		{Name: "async_sms_retry", Executor: exec.Name(), Expression: "*/5 * * * * *"},
+	}
+	for _, j := range jobs {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := res.RegisterJob(ctx, j)
+		cancel()
+		if err != nil {
+			panic(err)
+		}
+	}
+	return res
+}
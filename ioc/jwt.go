@@ -0,0 +1,112 @@
+package ioc
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"os"
+	ijwt "webook/internal/web/jwt"
+)
+
+// InitJWTKeys 从配置中加载长短 token 的签名密钥
+// 轮换密钥的时候，先把新的密钥加进 keys，再修改 active，最后等旧的 token 过期之后删掉旧的密钥
+func InitJWTKeys() ijwt.Keys {
+	type KeyConfig struct {
+		Kid string `yaml:"kid"`
+		// HS256、RS256 或者 EdDSA
+		Alg string `yaml:"alg"`
+		// HS256 的密钥
+		Secret string `yaml:"secret"`
+		// RS256 和 EdDSA 的私钥和公钥，PEM 文件的路径
+		// 只用来验证的旧密钥可以不配置私钥
+		PrivateKey string `yaml:"privateKey"`
+		PublicKey  string `yaml:"publicKey"`
+	}
+	type KeySetConfig struct {
+		// Active 用来签名的密钥的 kid
+		Active string      `yaml:"active"`
+		Keys   []KeyConfig `yaml:"keys"`
+	}
+	type Config struct {
+		Access  KeySetConfig `yaml:"access"`
+		Refresh KeySetConfig `yaml:"refresh"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("jwt", &cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	loadKey := func(kc KeyConfig) (ijwt.Key, error) {
+		switch kc.Alg {
+		case "", jwt.SigningMethodHS256.Alg():
+			if kc.Secret == "" {
+				return ijwt.Key{}, fmt.Errorf("密钥 %s 没有配置 secret", kc.Kid)
+			}
+			return ijwt.NewHMACKey(kc.Kid, []byte(kc.Secret)), nil
+		case jwt.SigningMethodRS256.Alg():
+			if kc.PrivateKey != "" {
+				data, err := os.ReadFile(kc.PrivateKey)
+				if err != nil {
+					return ijwt.Key{}, err
+				}
+				priv, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+				if err != nil {
+					return ijwt.Key{}, err
+				}
+				return ijwt.NewRSAKey(kc.Kid, priv, nil), nil
+			}
+			data, err := os.ReadFile(kc.PublicKey)
+			if err != nil {
+				return ijwt.Key{}, err
+			}
+			pub, err := jwt.ParseRSAPublicKeyFromPEM(data)
+			if err != nil {
+				return ijwt.Key{}, err
+			}
+			return ijwt.NewRSAKey(kc.Kid, nil, pub), nil
+		case jwt.SigningMethodEdDSA.Alg():
+			if kc.PrivateKey != "" {
+				data, err := os.ReadFile(kc.PrivateKey)
+				if err != nil {
+					return ijwt.Key{}, err
+				}
+				priv, err := jwt.ParseEdPrivateKeyFromPEM(data)
+				if err != nil {
+					return ijwt.Key{}, err
+				}
+				return ijwt.NewEdDSAKey(kc.Kid, priv.(ed25519.PrivateKey), nil), nil
+			}
+			data, err := os.ReadFile(kc.PublicKey)
+			if err != nil {
+				return ijwt.Key{}, err
+			}
+			pub, err := jwt.ParseEdPublicKeyFromPEM(data)
+			if err != nil {
+				return ijwt.Key{}, err
+			}
+			return ijwt.NewEdDSAKey(kc.Kid, nil, pub.(ed25519.PublicKey)), nil
+		}
+		return ijwt.Key{}, fmt.Errorf("密钥 %s 使用了不支持的算法 %s", kc.Kid, kc.Alg)
+	}
+	loadKeySet := func(ksc KeySetConfig) *ijwt.KeySet {
+		keys := make([]ijwt.Key, 0, len(ksc.Keys))
+		for _, kc := range ksc.Keys {
+			k, err := loadKey(kc)
+			if err != nil {
+				panic(err)
+			}
+			keys = append(keys, k)
+		}
+		ks, err := ijwt.NewKeySet(ksc.Active, keys...)
+		if err != nil {
+			panic(err)
+		}
+		return ks
+	}
+	return ijwt.Keys{
+		Access:  loadKeySet(cfg.Access),
+		Refresh: loadKeySet(cfg.Refresh),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/spf13/viper"
 	events2 "webook/interactive/events"
+	"webook/internal/events/feed"
 	"webook/pkg/saramax"
 )
 
@@ -34,8 +35,9 @@ func NewSyncProducer(client sarama.Client) sarama.SyncProducer {
 }
 
 // NewConsumers 面临的问题依旧是所有的 Consumer 在这里注册一下
-func NewConsumers(c1 *events2.InteractiveReadEventBatchConsumer) []saramax.Consumer {
-	return []saramax.Consumer{c1}
+func NewConsumers(c1 *events2.InteractiveReadEventBatchConsumer,
+	c2 *feed.ArticlePublishEventConsumer) []saramax.Consumer {
+	return []saramax.Consumer{c1, c2}
 }
 
 //func NewConsumers(c1 *article.InteractiveReadEventConsumer) []events.Consumer {
@@ -0,0 +1,19 @@
+package ioc
+
+import (
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"time"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/service/email"
+	"webook/pkg/logger"
+	"webook/pkg/ratelimit"
+)
+
+// InitMagicLinkService 登录链接和短信验证码一样，同一个邮箱一分钟只能发送一次
+func InitMagicLinkService(cmd redis.Cmdable, tokenRepo repository.TokenRepository,
+	userSvc service.UserService, emailSvc email.Service, l logger.Logger) service.MagicLinkService {
+	limiter := ratelimit.NewRedisSlidingWindowLimiter(cmd, time.Minute, 1)
+	return service.NewMagicLinkService(tokenRepo, userSvc, emailSvc, limiter, viper.GetString("web.baseURL"), l)
+}
@@ -0,0 +1,59 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"webook/internal/service/password"
+)
+
+// InitPasswordHasher 新的密码用 hasher 配置的算法，另一种算法只用来校验老的密码
+// 老的密码会在用户登录的时候升级成新的算法和参数
+func InitPasswordHasher() password.Hasher {
+	type Config struct {
+		// argon2id 或者 bcrypt
+		Hasher string `yaml:"hasher"`
+		Bcrypt struct {
+			Cost int `yaml:"cost"`
+		} `yaml:"bcrypt"`
+		Argon2id struct {
+			// KiB
+			Memory      uint32 `yaml:"memory"`
+			Iterations  uint32 `yaml:"iterations"`
+			Parallelism uint8  `yaml:"parallelism"`
+		} `yaml:"argon2id"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("password", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	params := password.DefaultArgon2idParams
+	if cfg.Argon2id.Memory > 0 {
+		params.Memory = cfg.Argon2id.Memory
+	}
+	if cfg.Argon2id.Iterations > 0 {
+		params.Iterations = cfg.Argon2id.Iterations
+	}
+	if cfg.Argon2id.Parallelism > 0 {
+		params.Parallelism = cfg.Argon2id.Parallelism
+	}
+	argon := password.NewArgon2idHasher(params)
+	bc := password.NewBcryptHasher(cfg.Bcrypt.Cost)
+	if cfg.Hasher == "bcrypt" {
+		return password.NewMultiHasher(bc, argon)
+	}
+	return password.NewMultiHasher(argon, bc)
+}
+
+// InitPasswordPolicy 设置密码的时候的强度要求
+func InitPasswordPolicy() *password.Policy {
+	var cfg password.PolicyConfig
+	err := viper.UnmarshalKey("password.policy", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	p, err := password.NewPolicy(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
@@ -0,0 +1,49 @@
+package ioc
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+// InitRBACService 启动的时候会给配置里面的用户授予超级管理员的角色，
+// 不然第一个管理员就只能手动改数据库了
+func InitRBACService(repo repository.RBACRepository, l logger.Logger) service.RBACService {
+	svc := service.NewRBACService(repo, l)
+	type Config struct {
+		Admins []int64 `yaml:"admins"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("rbac", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	for _, uid := range cfg.Admins {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err = svc.AssignRole(ctx, 0, uid, domain.RoleAdmin)
+		cancel()
+		if err != nil {
+			panic(err)
+		}
+	}
+	return svc
+}
+
+func InitJWTHandler(cmd redis.Cmdable, rbacSvc service.RBACService, keys ijwt.Keys) ijwt.Handler {
+	return ijwt.NewRedisHandler(cmd, func(ctx context.Context, uid int64) ([]string, error) {
+		roles, err := rbacSvc.UserRoles(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+		return slice.Map(roles, func(idx int, src domain.Role) string {
+			return string(src)
+		}), nil
+	}, keys)
+}
@@ -1,23 +1,198 @@
 package ioc
 
 import (
+	"fmt"
+	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
+	dysmsapi "github.com/alibabacloud-go/dysmsapi-20170525/v3/client"
+	"github.com/alibabacloud-go/tea/tea"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
 	tencentSMS "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/sms/v20210111"
 	"os"
 	"time"
+	"webook/internal/repository"
+	"webook/internal/repository/dao"
+	"webook/internal/service"
 	"webook/internal/service/sms"
+	"webook/internal/service/sms/aliyun"
+	"webook/internal/service/sms/async"
+	"webook/internal/service/sms/audit"
+	"webook/internal/service/sms/circuitbreaker"
+	failover "webook/internal/service/sms/faliover"
+	"webook/internal/service/sms/memory"
 	smsRatelimit "webook/internal/service/sms/ratelimit"
+	"webook/internal/service/sms/region"
 	"webook/internal/service/sms/tencent"
+	"webook/internal/web"
+	"webook/pkg/logger"
 	pkgRatelimit "webook/pkg/ratelimit"
 )
 
-func InitSmsService(cmd redis.Cmdable) sms.Service {
-	return initRedisSlidingWindowLimiter(cmd)
+const smsProviderFake = "fake"
+
+// InitSMSInbox 只要有一个区号使用假的短信服务，短信就都存在这里，可以通过 /dev/sms 查看
+// 别的情况下返回 nil，也就不会注册 /dev/sms
+func InitSMSInbox() *memory.Service {
+	for _, provider := range smsRegionProviders() {
+		if provider == smsProviderFake {
+			return memory.NewService(20)
+		}
+	}
+	return nil
+}
+
+// smsRegionProviders 读取 sms.regions，区号到服务商，空的服务商换成 sms.provider
+func smsRegionProviders() map[string]string {
+	regions := viper.GetStringMapString("sms.regions")
+	if len(regions) == 0 {
+		// 没有配置的时候和以前一样，只支持国内的号码
+		regions = map[string]string{"86": ""}
+	}
+	res := make(map[string]string, len(regions))
+	for cc, provider := range regions {
+		if provider == "" {
+			provider = viper.GetString("sms.provider")
+		}
+		res[cc] = provider
+	}
+	return res
+}
+
+// InitSMSTemplates 从配置 sms.templates 加载短信模板
+// 业务方只使用模板的名字，每个服务商的模板 ID、签名和参数都在配置里面
+func InitSMSTemplates() *sms.TemplateRegistry {
+	type ProviderConfig struct {
+		TplId    string   `yaml:"tplId"`
+		SignName string   `yaml:"signName"`
+		Params   []string `yaml:"params"`
+	}
+	type TemplateConfig struct {
+		Params    []string                  `yaml:"params"`
+		Providers map[string]ProviderConfig `yaml:"providers"`
+	}
+	var cfg map[string]TemplateConfig
+	err := viper.UnmarshalKey("sms.templates", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	tpls := make([]sms.Template, 0, len(cfg))
+	for name, tc := range cfg {
+		providers := make(map[string]sms.ProviderTemplate, len(tc.Providers))
+		for provider, pc := range tc.Providers {
+			providers[provider] = sms.ProviderTemplate{
+				TplId:    pc.TplId,
+				SignName: pc.SignName,
+				Params:   pc.Params,
+			}
+		}
+		tpls = append(tpls, sms.Template{
+			Name:      name,
+			Params:    tc.Params,
+			Providers: providers,
+		})
+	}
+	res, err := sms.NewTemplateRegistry(tpls...)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// InitSmsService 根据配置 sms.regions 按照号码的国际电话区号选择短信服务，
+// 每个区号的服务商可以是：
+//   - fake：假的短信服务，不需要任何凭证，用于本地开发和集成测试
+//   - tencent、aliyun：只用一个服务商
+//   - failover：按照 sms.failover 里面的服务商轮流发送
+//   - 空的：使用 sms.provider
+//
+// 没有配置的区号直接返回 sms.ErrUnsupportedRegion
+// 同步发送失败的短信会转成异步重试，重试由定时任务 async_sms_retry 负责
+// 每个服务商的每一次发送都会记录到 records 里面
+func InitSmsService(cmd redis.Cmdable, repo repository.AsyncSMSRepository,
+	records repository.SMSRecordRepository,
+	inbox *memory.Service, tpls *sms.TemplateRegistry, l logger.Logger) *async.Service {
+	svc := initRegionSMSService(records, inbox, tpls, l)
+	svc = initRedisSlidingWindowLimiter(cmd, svc)
+	return async.NewService(svc, repo, l)
+}
+
+func initRegionSMSService(records repository.SMSRecordRepository,
+	inbox *memory.Service, tpls *sms.TemplateRegistry, l logger.Logger) sms.Service {
+	regions := smsRegionProviders()
+	// 多个区号用同一个服务商的时候共用一个实例，熔断和故障转移的状态也是共用的
+	providers := make(map[string]sms.Service, len(regions))
+	routes := make(map[string]sms.Service, len(regions))
+	for cc, provider := range regions {
+		svc, ok := providers[provider]
+		if !ok {
+			svc = initProviderSMSService(provider, records, inbox, tpls, l)
+			providers[provider] = svc
+		}
+		routes[cc] = svc
+	}
+	return region.NewRegionSMSService(routes)
+}
+
+func initProviderSMSService(provider string, records repository.SMSRecordRepository,
+	inbox *memory.Service, tpls *sms.TemplateRegistry, l logger.Logger) sms.Service {
+	switch provider {
+	case smsProviderFake:
+		if inbox == nil {
+			// 正常不会走到这里，InitSMSInbox 和这里用的是同一份配置
+			panic("使用假的短信服务，但是没有初始化 inbox")
+		}
+		l.Warn("使用假的短信服务，短信不会真的发出去")
+		return audit.NewAuditSMSService(inbox, smsProviderFake, records, l)
+	case "failover":
+		return initFailoverSMSService(viper.GetStringSlice("sms.failover"), records, tpls, l)
+	case "":
+		// 没有配置的时候和以前一样，使用腾讯云
+		return initSMSProvider(tencent.ProviderName, records, tpls, l)
+	default:
+		return initSMSProvider(provider, records, tpls, l)
+	}
+}
+
+// initFailoverSMSService 每个服务商都套一层熔断，熔断的服务商会立刻失败，换成下一个服务商
+// 模板需要在每个服务商那边都配置好
+func initFailoverSMSService(names []string, records repository.SMSRecordRepository,
+	tpls *sms.TemplateRegistry, l logger.Logger) sms.Service {
+	providers := make([]failover.Provider, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, failover.Provider{Name: name, Svc: initSMSProvider(name, records, tpls, l)})
+	}
+	return failover.NewFailoverSMSService(providers, l).Threshold(3).
+		ProbeInterval(time.Second * 30).
+		Timeout(time.Second * 3).
+		WithMetrics(prometheus.CounterOpts{
+			Namespace: "webook_server",
+			Subsystem: "webook",
+			Name:      "sms_provider_send",
+			Help:      "每个短信服务商发送的次数，按照成功、失败和超时区分",
+		})
+}
+
+// initSMSProvider 真实的服务商，先套上发送记录，再套上熔断
+// 熔断的时候没有真的调用服务商，所以不会有记录
+func initSMSProvider(name string, records repository.SMSRecordRepository,
+	tpls *sms.TemplateRegistry, l logger.Logger) sms.Service {
+	var svc sms.Service
+	switch name {
+	case tencent.ProviderName:
+		svc = initSmsTencentService(tpls)
+	case aliyun.ProviderName:
+		svc = initSmsAliyunService(tpls)
+	default:
+		panic(fmt.Sprintf("未知的短信服务商 %s", name))
+	}
+	svc = audit.NewAuditSMSService(svc, name, records, l)
+	return circuitbreaker.NewCircuitBreakerSMSService(svc, name, l)
 }
 
-func initSmsTencentService() sms.Service {
+func initSmsTencentService(tpls *sms.TemplateRegistry) sms.Service {
 	secretId, ok := os.LookupEnv("Tencent_SMS_Secret_Id")
 	if !ok {
 		panic("没有找到环境变量 Tencent_SMS_Secret_Id ")
@@ -33,11 +208,66 @@ func initSmsTencentService() sms.Service {
 	if err != nil {
 		panic("tencentSMS 初始化失败 ")
 	}
-	return tencent.NewService(c, "1400952398", "南絮0124公众号")
+	return tencent.NewService(c, "1400952398", "南絮0124公众号", tpls)
+}
+
+func initSmsAliyunService(tpls *sms.TemplateRegistry) sms.Service {
+	keyId, ok := os.LookupEnv("Aliyun_SMS_Access_Key_Id")
+	if !ok {
+		panic("没有找到环境变量 Aliyun_SMS_Access_Key_Id ")
+	}
+	keySecret, ok := os.LookupEnv("Aliyun_SMS_Access_Key_Secret")
+	if !ok {
+		panic("没有找到环境变量 Aliyun_SMS_Access_Key_Secret ")
+	}
+	c, err := dysmsapi.NewClient(&openapi.Config{
+		AccessKeyId:     tea.String(keyId),
+		AccessKeySecret: tea.String(keySecret),
+		Endpoint:        tea.String("dysmsapi.aliyuncs.com"),
+	})
+	if err != nil {
+		panic("aliyunSMS 初始化失败 ")
+	}
+	return aliyun.NewService(c, viper.GetString("sms.aliyun.signName"), tpls)
+}
+
+// InitAsyncSMSRepository 等待重试的短信里面可能有验证码，模板参数用 sms.async.key 加密之后再存
+func InitAsyncSMSRepository(d dao.AsyncSMSDAO) repository.AsyncSMSRepository {
+	key := viper.GetString("sms.async.key")
+	if key == "" {
+		panic("没有配置 sms.async.key")
+	}
+	return repository.NewAsyncSMSRepository(d, []byte(key))
+}
+
+// InitSMSRecordRepository 发送记录里面不保存完整的号码，按照号码查询用的是
+// sms.record.hashKey 计算的 HMAC，换了密钥以前的记录就查不到了
+func InitSMSRecordRepository(d dao.SMSRecordDAO) repository.SMSRecordRepository {
+	key := viper.GetString("sms.record.hashKey")
+	if key == "" {
+		panic("没有配置 sms.record.hashKey")
+	}
+	return repository.NewSMSRecordRepository(d, []byte(key))
+}
+
+// InitSMSHandler 状态报告的回调地址是 /sms/callback/{服务商}?token={sms.callback.token}，
+// 需要在服务商的控制台配置，没有配置 token 的时候拒绝所有的回调
+func InitSMSHandler(svc service.SMSRecordService, inbox *memory.Service, l logger.Logger) *web.SMSHandler {
+	token := viper.GetString("sms.callback.token")
+	if token == "" {
+		l.Warn("没有配置 sms.callback.token，不会处理短信状态报告")
+	}
+	parsers := map[string]sms.ReceiptParser{
+		tencent.ProviderName: tencent.ReceiptParser{},
+		aliyun.ProviderName:  aliyun.ReceiptParser{},
+	}
+	if inbox != nil {
+		parsers[smsProviderFake] = memory.ReceiptParser{}
+	}
+	return web.NewSMSHandler(svc, parsers, token, l)
 }
 
-func initRedisSlidingWindowLimiter(cmd redis.Cmdable) sms.Service {
+func initRedisSlidingWindowLimiter(cmd redis.Cmdable, svc sms.Service) sms.Service {
 	limiter := pkgRatelimit.NewRedisSlidingWindowLimiter(cmd, time.Minute, 3)
-	tencentSMSService := initSmsTencentService()
-	return smsRatelimit.NewRatelimitSMSService(tencentSMSService, limiter)
+	return smsRatelimit.NewRatelimitSMSService(svc, limiter)
 }
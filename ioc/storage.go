@@ -0,0 +1,20 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"webook/internal/service/storage"
+	"webook/internal/service/storage/local"
+)
+
+func InitStorageService() storage.Service {
+	type Config struct {
+		Dir       string `yaml:"dir"`
+		URLPrefix string `yaml:"urlPrefix"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("storage.local", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return local.NewService(cfg.Dir, cfg.URLPrefix)
+}
@@ -0,0 +1,40 @@
+package ioc
+
+import (
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/service/email"
+	"webook/internal/service/password"
+	"webook/internal/web"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+	"webook/pkg/ratelimit"
+)
+
+// InitUserService 邮件里面的确认链接要用对外的地址，不同环境不一样
+func InitUserService(repo repository.UserRepository, tokenRepo repository.TokenRepository,
+	emailSvc email.Service, hasher password.Hasher, policy *password.Policy, l logger.Logger) service.UserService {
+	baseURL := viper.GetString("web.baseURL")
+	if baseURL == "" {
+		panic("没有配置 web.baseURL")
+	}
+	return service.NewUserService(repo, tokenRepo, emailSvc, hasher, policy, baseURL, l)
+}
+
+// InitUserHandler 注册和密码登录的图形验证码软阈值在 captcha.soft 里面配置
+// 同一个 IP 在 window 内超过 threshold 次之后才需要图形验证码，threshold 为 0 的话每次都要
+func InitUserHandler(svc service.UserService, codeSvc service.CodeService,
+	captchaSvc service.CaptchaService, cmd redis.Cmdable, jwtHdl ijwt.Handler, l logger.Logger) *web.UserHandler {
+	var limiter ratelimit.Limiter
+	threshold := viper.GetInt("captcha.soft.threshold")
+	if threshold > 0 {
+		window := viper.GetDuration("captcha.soft.window")
+		if window <= 0 {
+			panic("没有配置 captcha.soft.window")
+		}
+		limiter = ratelimit.NewRedisSlidingWindowLimiter(cmd, window, threshold)
+	}
+	return web.NewUserHandler(svc, codeSvc, captchaSvc, limiter, jwtHdl, l)
+}
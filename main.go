@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -25,6 +27,14 @@ func main() {
 		ctx := app.cron.Stop()
 		<-ctx.Done()
 	}()
+	schedulerCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		err := app.scheduler.Schedule(schedulerCtx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			panic(err)
+		}
+	}()
 
 	server := app.web
 	//注册路由
@@ -36,6 +36,7 @@ func WrapClaimsAndReq[Req any](fn func(*gin.Context, Req, UserClaims) (Result, e
 				Id:               rawClaims.Id,
 				UserAgent:        rawClaims.UserAgent,
 				Ssid:             rawClaims.Ssid,
+				Roles:            rawClaims.Roles,
 				RegisteredClaims: rawClaims.RegisteredClaims,
 			}
 		} else {
@@ -70,6 +71,7 @@ func WrapClaims(fn func(*gin.Context, UserClaims) (Result, error)) gin.HandlerFu
 				Id:               rawClaims.Id,
 				UserAgent:        rawClaims.UserAgent,
 				Ssid:             rawClaims.Ssid,
+				Roles:            rawClaims.Roles,
 				RegisteredClaims: rawClaims.RegisteredClaims,
 			}
 		} else {
@@ -86,3 +88,32 @@ func WrapClaims(fn func(*gin.Context, UserClaims) (Result, error)) gin.HandlerFu
 		ctx.JSON(http.StatusOK, res)
 	}
 }
+
+// WrapReq 不需要登录的接口使用，只负责解析请求
+func WrapReq[Req any](fn func(*gin.Context, Req) (Result, error)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req Req
+		if err := ctx.Bind(&req); err != nil {
+			log.Error("解析请求失败", logger.Error(err))
+			return
+		}
+		res, err := fn(ctx, req)
+		if err != nil {
+			log.Error("执行业务逻辑失败",
+				logger.Error(err))
+		}
+		ctx.JSON(http.StatusOK, res)
+	}
+}
+
+// Wrap 不需要登录，也没有请求参数的接口使用
+func Wrap(fn func(*gin.Context) (Result, error)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := fn(ctx)
+		if err != nil {
+			log.Error("执行业务逻辑失败",
+				logger.Error(err))
+		}
+		ctx.JSON(http.StatusOK, res)
+	}
+}
@@ -12,5 +12,6 @@ type UserClaims struct {
 	Id        int64
 	UserAgent string
 	Ssid      string
+	Roles     []string
 	jwt.RegisteredClaims
 }
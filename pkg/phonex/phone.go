@@ -0,0 +1,185 @@
+// Package phonex 手机号的解析、校验和 E.164 格式化
+// 只覆盖了我们支持的国家和地区的手机号，规则参考各国的号码分配方案，
+// 没有引入完整的 libphonenumber，新的国家或地区在 regions 里面加一行就可以
+package phonex
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrInvalidNumber 号码的格式不对，或者不是对应国家的手机号
+	ErrInvalidNumber = errors.New("手机号码格式不正确")
+	// ErrUnknownRegion 不认识的国家或地区
+	ErrUnknownRegion = errors.New("不支持的国家或地区")
+)
+
+// Region 一个国家或地区的手机号规则
+type Region struct {
+	// Code ISO 3166-1 的两位代码，例如 CN
+	Code string
+	// CountryCode 国际电话区号，不带加号，例如 86
+	CountryCode string
+	// TrunkPrefix 国内拨号的前缀，E.164 格式里面要去掉，例如英国的 07 开头的手机号
+	TrunkPrefix string
+	// mobile 去掉区号和国内前缀之后的手机号
+	mobile *regexp.Regexp
+}
+
+// regions 支持的国家和地区
+// 北美的 +1 是好几个国家共用的，统一当成 US 处理
+var regions = []Region{
+	{Code: "CN", CountryCode: "86", mobile: regexp.MustCompile(`^1[3-9]\d{9}$`)},
+	{Code: "HK", CountryCode: "852", mobile: regexp.MustCompile(`^[4-9]\d{7}$`)},
+	{Code: "MO", CountryCode: "853", mobile: regexp.MustCompile(`^6\d{7}$`)},
+	{Code: "TW", CountryCode: "886", TrunkPrefix: "0", mobile: regexp.MustCompile(`^9\d{8}$`)},
+	{Code: "US", CountryCode: "1", TrunkPrefix: "1", mobile: regexp.MustCompile(`^[2-9]\d{2}[2-9]\d{6}$`)},
+	{Code: "GB", CountryCode: "44", TrunkPrefix: "0", mobile: regexp.MustCompile(`^7\d{9}$`)},
+	{Code: "JP", CountryCode: "81", TrunkPrefix: "0", mobile: regexp.MustCompile(`^[789]0\d{8}$`)},
+	{Code: "KR", CountryCode: "82", TrunkPrefix: "0", mobile: regexp.MustCompile(`^1\d{8,9}$`)},
+	{Code: "SG", CountryCode: "65", mobile: regexp.MustCompile(`^[89]\d{7}$`)},
+	{Code: "AU", CountryCode: "61", TrunkPrefix: "0", mobile: regexp.MustCompile(`^4\d{8}$`)},
+}
+
+var (
+	regionsByCode        = make(map[string]Region, len(regions))
+	regionsByCountryCode = make(map[string]Region, len(regions))
+)
+
+func init() {
+	for _, r := range regions {
+		regionsByCode[r.Code] = r
+		regionsByCountryCode[r.CountryCode] = r
+	}
+}
+
+// Number 解析之后的手机号
+type Number struct {
+	Region Region
+	// National 去掉区号和国内前缀之后的号码
+	National string
+}
+
+// E164 例如 +8613812345678，存储和发送短信都用这个格式
+func (n Number) E164() string {
+	return "+" + n.Region.CountryCode + n.National
+}
+
+// Parse 解析用户输入的手机号，允许带空格、横线、括号和点
+// 以 + 或者 00 开头的按照国际号码解析，否则按照 defaultRegion 的国内号码解析
+func Parse(raw string, defaultRegion string) (Number, error) {
+	s := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '.':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+
+	var (
+		region   Region
+		national string
+	)
+	switch {
+	case strings.HasPrefix(s, "+"):
+		var err error
+		region, national, err = splitCountryCode(s[1:])
+		if err != nil {
+			return Number{}, err
+		}
+	case strings.HasPrefix(s, "00"):
+		var err error
+		region, national, err = splitCountryCode(s[2:])
+		if err != nil {
+			return Number{}, err
+		}
+	default:
+		var ok bool
+		region, ok = regionsByCode[strings.ToUpper(defaultRegion)]
+		if !ok {
+			return Number{}, ErrUnknownRegion
+		}
+		national = s
+	}
+	if !isDigits(national) {
+		return Number{}, ErrInvalidNumber
+	}
+	// 国际格式里面一般不带国内前缀，但是有人会写成 +44 07xxx，这里兼容一下
+	if region.TrunkPrefix != "" && !region.mobile.MatchString(national) {
+		national = strings.TrimPrefix(national, region.TrunkPrefix)
+	}
+	if !region.mobile.MatchString(national) {
+		return Number{}, ErrInvalidNumber
+	}
+	return Number{Region: region, National: national}, nil
+}
+
+// Normalize 解析并且返回 E.164 格式
+func Normalize(raw string, defaultRegion string) (string, error) {
+	n, err := Parse(raw, defaultRegion)
+	if err != nil {
+		return "", err
+	}
+	return n.E164(), nil
+}
+
+// CountryCode 返回 E.164 格式的号码的国际电话区号，例如 +8613812345678 返回 86
+func CountryCode(e164 string) (string, error) {
+	if !strings.HasPrefix(e164, "+") {
+		return "", ErrInvalidNumber
+	}
+	r, _, err := splitCountryCode(e164[1:])
+	if err != nil {
+		return "", err
+	}
+	return r.CountryCode, nil
+}
+
+// Mask 打码之后的号码，保留区号、号码的前三位和后四位，例如 +86138****5678
+// 用在日志、审计记录这些给人看的地方，不是 E.164 格式的原样打码
+func Mask(e164 string) string {
+	cc, err := CountryCode(e164)
+	if err != nil {
+		return maskMiddle(e164)
+	}
+	return "+" + cc + maskMiddle(e164[1+len(cc):])
+}
+
+// maskMiddle 号码短的时候少保留几位，至少遮住四位
+func maskMiddle(s string) string {
+	head, tail := 3, 4
+	if len(s) < 11 {
+		head, tail = 2, 2
+	}
+	if len(s) < 8 {
+		head, tail = len(s)/4, len(s)/4
+	}
+	return s[:head] + strings.Repeat("*", len(s)-head-tail) + s[len(s)-tail:]
+}
+
+// splitCountryCode 国际电话区号是前缀码，一到三位，不会有一个是另一个的前缀
+func splitCountryCode(s string) (Region, string, error) {
+	for i := 1; i <= 3 && i <= len(s); i++ {
+		if r, ok := regionsByCountryCode[s[:i]]; ok {
+			return r, s[i:], nil
+		}
+	}
+	if !isDigits(s) {
+		return Region{}, "", ErrInvalidNumber
+	}
+	return Region{}, "", ErrUnknownRegion
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
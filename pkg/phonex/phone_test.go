@@ -0,0 +1,118 @@
+package phonex
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name          string
+		raw           string
+		defaultRegion string
+
+		want    string
+		wantErr error
+	}{
+		{
+			name:          "国内号码",
+			raw:           "13812345678",
+			defaultRegion: "CN",
+			want:          "+8613812345678",
+		},
+		{
+			name:          "带空格和横线",
+			raw:           " 138-1234 5678 ",
+			defaultRegion: "CN",
+			want:          "+8613812345678",
+		},
+		{
+			name:          "国际格式",
+			raw:           "+86 138 1234 5678",
+			defaultRegion: "CN",
+			want:          "+8613812345678",
+		},
+		{
+			name:          "00 开头的国际格式",
+			raw:           "0085291234567",
+			defaultRegion: "CN",
+			want:          "+85291234567",
+		},
+		{
+			name:          "去掉国内前缀",
+			raw:           "+44 07911 123456",
+			defaultRegion: "CN",
+			want:          "+447911123456",
+		},
+		{
+			name:          "默认地区不是中国",
+			raw:           "(212) 555-1234",
+			defaultRegion: "us",
+			want:          "+12125551234",
+		},
+		{
+			name:          "韩国",
+			raw:           "+82 010-1234-5678",
+			defaultRegion: "CN",
+			want:          "+821012345678",
+		},
+		{
+			name:          "不是手机号",
+			raw:           "01012345678",
+			defaultRegion: "CN",
+			wantErr:       ErrInvalidNumber,
+		},
+		{
+			name:          "位数不对",
+			raw:           "+86 138123456",
+			defaultRegion: "CN",
+			wantErr:       ErrInvalidNumber,
+		},
+		{
+			name:          "有字母",
+			raw:           "1381234abcd",
+			defaultRegion: "CN",
+			wantErr:       ErrInvalidNumber,
+		},
+		{
+			name:          "不支持的国家",
+			raw:           "+7 912 345 6789",
+			defaultRegion: "CN",
+			wantErr:       ErrUnknownRegion,
+		},
+		{
+			name:    "空的",
+			raw:     "",
+			wantErr: ErrUnknownRegion,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Normalize(tc.raw, tc.defaultRegion)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCountryCode(t *testing.T) {
+	cc, err := CountryCode("+8613812345678")
+	assert.NoError(t, err)
+	assert.Equal(t, "86", cc)
+	cc, err = CountryCode("+85291234567")
+	assert.NoError(t, err)
+	assert.Equal(t, "852", cc)
+	_, err = CountryCode("13812345678")
+	assert.Equal(t, ErrInvalidNumber, err)
+	_, err = CountryCode("+79123456789")
+	assert.Equal(t, ErrUnknownRegion, err)
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "+86138****5678", Mask("+8613812345678"))
+	assert.Equal(t, "+85291****67", Mask("+85291234567"))
+	assert.Equal(t, "+121******34", Mask("+12125551234"))
+	// 不认识的号码也要打码
+	assert.Equal(t, "138****5678", Mask("13812345678"))
+	assert.Equal(t, "1*****7", Mask("1234567"))
+}
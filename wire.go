@@ -10,11 +10,15 @@ import (
 	dao2 "webook/interactive/repository/dao"
 	service2 "webook/interactive/service"
 	eventsArticle "webook/internal/events/article"
+	"webook/internal/events/feed"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/repository/dao/article"
 	"webook/internal/service"
+	"webook/internal/service/captcha"
+	"webook/internal/service/sms"
+	"webook/internal/service/sms/async"
 	"webook/internal/web"
 	ijwt "webook/internal/web/jwt"
 	"webook/ioc"
@@ -55,41 +59,98 @@ func InitApp() *App {
 		rankServiceProvider,
 		ioc.InitJobs,
 		ioc.InitRankingJob,
+		ioc.InitScheduler,
+		service.NewCronJobService,
+		repository.NewCronJobRepositoryImpl,
+		dao.NewGORMJobDAO,
 
 		// 微服务部分
 		interactiveServiceProducer,
 		ioc.InitIntrGRPCClient,
+		ioc.InitFollowGRPCClient,
 
 		// DAO 部分
 		dao.NewGormUserDAO,
 		article.NewGORMArticleDAO,
+		dao.NewGORMAccountDAO,
+		dao.NewGORMRBACDAO,
+		dao.NewGORMPATDAO,
+		dao.NewGORMAsyncSMSDAO,
+		dao.NewGORMSMSRecordDAO,
 
 		// Cache 部分
 		cache.NewRedisUserCache,
 		cache.NewRedisCodeCache,
+		cache.NewRedisCodeLimitCache,
 		cache.NewRedisArticleCache,
+		cache.NewRedisTokenCache,
+		cache.NewRedisFeedCache,
+		cache.NewRedisQRLoginCache,
 
 		// repository 部分
 		repository.NewCachedUserRepository,
 		repository.NewCachedCodeRepository,
+		repository.NewCachedCodeLimitRepository,
 		repository.NewArticleRepository,
+		repository.NewCachedTokenRepository,
+		repository.NewCachedFeedRepository,
+		repository.NewCachedQRLoginRepository,
+		repository.NewAccountRepository,
+		repository.NewRBACRepository,
+		repository.NewPATRepository,
+		ioc.InitAsyncSMSRepository,
+		ioc.InitSMSRecordRepository,
 
 		// events 部分
 		eventsArticle.NewKafkaProducer,
+		feed.NewArticlePublishEventConsumer,
 		ioc.NewConsumers,
 
 		// service 部分
-		service.NewUserService,
-		service.NewSMSCodeService,
+		ioc.InitUserService,
+		ioc.InitCodeService,
+		service.NewCaptchaService,
+		captcha.NewImageGenerator,
+		wire.Bind(new(captcha.Generator), new(*captcha.ImageGenerator)),
 		service.NewArticleService,
+		service.NewFeedService,
+		ioc.InitSMSInbox,
+		ioc.InitSMSTemplates,
 		ioc.InitSmsService,
+		wire.Bind(new(sms.Service), new(*async.Service)),
+		service.NewSMSRecordService,
+		ioc.InitEmailService,
+		ioc.InitAccountService,
+		ioc.InitStorageService,
+		ioc.InitRBACService,
+		service.NewAdminService,
+		service.NewPATService,
+		service.NewQRLoginService,
+		ioc.InitMagicLinkService,
+		ioc.InitPasswordHasher,
+		ioc.InitPasswordPolicy,
+		wire.Bind(new(service.SessionRevoker), new(ijwt.Handler)),
 
 		// handler 部分
-		ijwt.NewRedisHandler,
-		web.NewUserHandler,
+		ioc.InitJWTKeys,
+		ioc.InitJWTHandler,
+		ioc.InitUserHandler,
 		web.NewArticleHandler,
+		web.NewFollowHandler,
+		web.NewFeedHandler,
+		web.NewAccountHandler,
+		web.NewProfileHandler,
+		web.NewAdminHandler,
+		web.NewJWKSHandler,
+		web.NewPATHandler,
+		web.NewQRLoginHandler,
+		web.NewMagicLinkHandler,
+		web.NewDevSMSHandler,
+		web.NewCaptchaHandler,
+		ioc.InitSMSHandler,
 
 		// gin 的中间件
+		ioc.InitAuthPolicies,
 		ioc.GinMiddlewares,
 
 		// Web 服务器
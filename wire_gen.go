@@ -14,13 +14,14 @@ import (
 	dao2 "webook/interactive/repository/dao"
 	service2 "webook/interactive/service"
 	article2 "webook/internal/events/article"
+	"webook/internal/events/feed"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/repository/dao/article"
 	"webook/internal/service"
+	"webook/internal/service/captcha"
 	"webook/internal/web"
-	"webook/internal/web/jwt"
 	"webook/ioc"
 )
 
@@ -32,19 +33,42 @@ import (
 
 func InitApp() *App {
 	cmdable := ioc.InitRedis()
-	handler := jwt.NewRedisHandler(cmdable)
 	logger := ioc.InitLogger()
-	v := ioc.GinMiddlewares(cmdable, handler, logger)
 	db := ioc.InitDB(logger)
+	rbacdao := dao.NewGORMRBACDAO(db)
+	rbacRepository := repository.NewRBACRepository(rbacdao)
+	rbacService := ioc.InitRBACService(rbacRepository, logger)
+	keys := ioc.InitJWTKeys()
+	handler := ioc.InitJWTHandler(cmdable, rbacService, keys)
+	authPolicies := ioc.InitAuthPolicies()
+	patdao := dao.NewGORMPATDAO(db)
+	patRepository := repository.NewPATRepository(patdao)
 	userDAO := dao.NewGormUserDAO(db)
 	userCache := cache.NewRedisUserCache(cmdable)
 	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
-	userService := service.NewUserService(userRepository, logger)
-	smsService := ioc.InitSmsService(cmdable)
+	patService := service.NewPATService(patRepository, userRepository)
+	v := ioc.GinMiddlewares(cmdable, handler, authPolicies, patService, logger)
+	tokenCache := cache.NewRedisTokenCache(cmdable)
+	tokenRepository := repository.NewCachedTokenRepository(tokenCache)
+	emailService := ioc.InitEmailService(logger)
+	hasher := ioc.InitPasswordHasher()
+	policy := ioc.InitPasswordPolicy()
+	userService := ioc.InitUserService(userRepository, tokenRepository, emailService, hasher, policy, logger)
+	asyncSMSDAO := dao.NewGORMAsyncSMSDAO(db)
+	asyncSMSRepository := ioc.InitAsyncSMSRepository(asyncSMSDAO)
+	smsRecordDAO := dao.NewGORMSMSRecordDAO(db)
+	smsRecordRepository := ioc.InitSMSRecordRepository(smsRecordDAO)
+	memoryService := ioc.InitSMSInbox()
+	templateRegistry := ioc.InitSMSTemplates()
+	asyncService := ioc.InitSmsService(cmdable, asyncSMSRepository, smsRecordRepository, memoryService, templateRegistry, logger)
 	codeCache := cache.NewRedisCodeCache(cmdable)
 	codeRepository := repository.NewCachedCodeRepository(codeCache)
-	codeService := service.NewSMSCodeService(smsService, codeRepository, logger)
-	userHandler := web.NewUserHandler(userService, codeService, handler)
+	codeLimitCache := cache.NewRedisCodeLimitCache(cmdable)
+	codeLimitRepository := repository.NewCachedCodeLimitRepository(codeLimitCache)
+	imageGenerator := captcha.NewImageGenerator()
+	captchaService := service.NewCaptchaService(tokenRepository, imageGenerator)
+	codeService := ioc.InitCodeService(asyncService, emailService, memoryService, codeRepository, codeLimitRepository, captchaService, logger)
+	userHandler := ioc.InitUserHandler(userService, codeService, captchaService, cmdable, handler, logger)
 	articleDAO := article.NewGORMArticleDAO(db)
 	articleCache := cache.NewRedisArticleCache(cmdable)
 	articleRepository := repository.NewArticleRepository(articleDAO, userRepository, articleCache, logger)
@@ -56,21 +80,53 @@ func InitApp() *App {
 	interactiveCache := cache2.NewRedisInteractiveCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, interactiveCache, logger)
 	interactiveService := service2.NewInteractiveService(interactiveRepository, logger)
-	interactiveServiceClient := ioc.InitIntrGRPCClient(interactiveService, logger)
-	articleHandler := web.NewArticleHandler(articleService, interactiveServiceClient, logger)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler)
-	interactiveReadEventBatchConsumer := events.NewInteractiveReadEventBatchConsumer(client, logger, interactiveRepository)
-	v2 := ioc.NewConsumers(interactiveReadEventBatchConsumer)
+	followServiceClient := ioc.InitFollowGRPCClient()
+	interactiveServiceClient := ioc.InitIntrGRPCClient(interactiveService, articleService, followServiceClient, logger)
 	redisRankingCache := cache.NewRedisRankingCache(cmdable)
 	rankingLocalCache := cache.NewRankingLocalCache()
 	rankingRepository := repository.NewCachedRankingRepository(redisRankingCache, rankingLocalCache)
 	rankingService := service.NewBatchRankingService(interactiveServiceClient, articleService, rankingRepository)
+	articleHandler := web.NewArticleHandler(articleService, interactiveServiceClient, followServiceClient, rankingService, logger)
+	followHandler := web.NewFollowHandler(followServiceClient, logger)
+	feedCache := cache.NewRedisFeedCache(cmdable)
+	feedRepository := repository.NewCachedFeedRepository(feedCache)
+	feedService := service.NewFeedService(feedRepository, articleRepository, followServiceClient)
+	feedHandler := web.NewFeedHandler(feedService, logger)
+	accountDAO := dao.NewGORMAccountDAO(db)
+	accountRepository := repository.NewAccountRepository(accountDAO)
+	accountService := ioc.InitAccountService(accountRepository, userRepository, articleRepository, interactiveServiceClient, handler, logger)
+	accountHandler := web.NewAccountHandler(accountService, logger)
+	storageService := ioc.InitStorageService()
+	profileHandler := web.NewProfileHandler(userService, articleService, followServiceClient, storageService, logger)
+	adminService := service.NewAdminService(userRepository, articleRepository, rbacRepository, handler, logger)
+	adminHandler := web.NewAdminHandler(adminService, rbacService, logger)
+	jwksHandler := web.NewJWKSHandler(keys)
+	patHandler := web.NewPATHandler(patService, logger)
+	qrLoginCache := cache.NewRedisQRLoginCache(cmdable)
+	qrLoginRepository := repository.NewCachedQRLoginRepository(qrLoginCache)
+	qrLoginService := service.NewQRLoginService(qrLoginRepository)
+	qrLoginHandler := web.NewQRLoginHandler(qrLoginService, handler, logger)
+	magicLinkService := ioc.InitMagicLinkService(cmdable, tokenRepository, userService, emailService, logger)
+	magicLinkHandler := web.NewMagicLinkHandler(magicLinkService, handler, logger)
+	devSMSHandler := web.NewDevSMSHandler(memoryService)
+	captchaHandler := web.NewCaptchaHandler(captchaService)
+	smsRecordService := service.NewSMSRecordService(smsRecordRepository)
+	smsHandler := ioc.InitSMSHandler(smsRecordService, memoryService, logger)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, followHandler, feedHandler, accountHandler, profileHandler, adminHandler, jwksHandler, patHandler, qrLoginHandler, magicLinkHandler, devSMSHandler, captchaHandler, smsHandler, logger)
+	interactiveReadEventBatchConsumer := events.NewInteractiveReadEventBatchConsumer(client, logger, interactiveRepository)
+	articlePublishEventConsumer := feed.NewArticlePublishEventConsumer(client, feedService, logger)
+	v2 := ioc.NewConsumers(interactiveReadEventBatchConsumer, articlePublishEventConsumer)
 	rankingJob := ioc.InitRankingJob(rankingService, logger)
 	cron := ioc.InitJobs(logger, rankingJob)
+	cronJobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewCronJobRepositoryImpl(cronJobDAO)
+	cronJobService := service.NewCronJobService(cronJobRepository, logger)
+	scheduler := ioc.InitScheduler(logger, cronJobService, accountService, asyncService)
 	app := &App{
 		web:       engine,
 		consumers: v2,
 		cron:      cron,
+		scheduler: scheduler,
 	}
 	return app
 }